@@ -1,36 +1,118 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
 	"log"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/mjrtuhin/loomis-backend/internal/assets"
 	"github.com/mjrtuhin/loomis-backend/internal/auth"
 	"github.com/mjrtuhin/loomis-backend/internal/charts"
 	"github.com/mjrtuhin/loomis-backend/internal/config"
+	"github.com/mjrtuhin/loomis-backend/internal/dashboards"
 	"github.com/mjrtuhin/loomis-backend/internal/data"
+	"github.com/mjrtuhin/loomis-backend/internal/geocode"
 	"github.com/mjrtuhin/loomis-backend/internal/middleware"
+	"github.com/mjrtuhin/loomis-backend/internal/pages"
+	"github.com/mjrtuhin/loomis-backend/internal/shares"
+	"github.com/mjrtuhin/loomis-backend/internal/storage"
+	"github.com/mjrtuhin/loomis-backend/internal/templates"
+	"github.com/mjrtuhin/loomis-backend/internal/themes"
 )
 
 func main() {
 	cfg := config.Load()
 
-	firebaseAuth, err := auth.NewFirebaseAuth(cfg.FirebaseCredentials)
-	if err != nil {
-		log.Fatal("Failed to initialize Firebase:", err)
+	if err := assets.Check(); err != nil {
+		log.Fatal("Failed to load page assets:", err)
 	}
 
-	authMiddleware := middleware.NewAuthMiddleware(firebaseAuth)
+	var (
+		verifier       auth.Verifier
+		localAuth      *auth.LocalAuth
+		dashboardStore dashboards.Store
+		shareStore     shares.Store
+		sheetCache     data.Cache
+		themeStore     themes.Store
+		shareSecret    = []byte(cfg.ShareSecret)
+	)
+	switch cfg.Storage {
+	case "firebase":
+		firebaseAuth, err := auth.NewFirebaseAuth(cfg.FirebaseCredentials)
+		if err != nil {
+			log.Fatal("Failed to initialize Firebase:", err)
+		}
+		verifier = firebaseAuth
+
+		dashboardStore, err = dashboards.NewFirestoreStore(context.Background(), cfg.FirebaseProjectID, cfg.FirebaseCredentials)
+		if err != nil {
+			log.Fatal("Failed to open dashboard store:", err)
+		}
+		shareStore, err = shares.NewFirestoreStore(context.Background(), cfg.FirebaseProjectID, cfg.FirebaseCredentials)
+		if err != nil {
+			log.Fatal("Failed to open share store:", err)
+		}
+		themeStore, err = themes.NewFirestoreStore(context.Background(), cfg.FirebaseProjectID, cfg.FirebaseCredentials)
+		if err != nil {
+			log.Fatal("Failed to open theme store:", err)
+		}
+		if len(shareSecret) == 0 {
+			log.Print("SHARE_SECRET is not set: share links will stop working on restart")
+			shareSecret = make([]byte, 32)
+			if _, err := rand.Read(shareSecret); err != nil {
+				log.Fatal("Failed to generate share secret:", err)
+			}
+		}
+	case "local":
+		db, err := storage.Open(cfg.DatabasePath)
+		if err != nil {
+			log.Fatal("Failed to open database:", err)
+		}
+		defer db.Close()
+
+		localAuth, err = auth.NewLocalAuth(db, cfg.AuthSecret)
+		if err != nil {
+			log.Fatal("Failed to initialize local auth:", err)
+		}
+		verifier = localAuth
+		dashboardStore = dashboards.NewBoltStore(db)
+		shareStore = shares.NewBoltStore(db)
+		sheetCache = data.NewBoltCache(db)
+		themeStore = themes.NewBoltStore(db)
+		if len(shareSecret) == 0 {
+			shareSecret, err = auth.StoredSecret(db, "shareSecret")
+			if err != nil {
+				log.Fatal("Failed to load share secret:", err)
+			}
+		}
+	default:
+		log.Fatalf("Unknown storage %q: use firebase or local", cfg.Storage)
+	}
 
-	dataHandler := data.NewHandler()
-	chartHandler := charts.NewHandler()
+	authMiddleware := middleware.NewAuthMiddleware(verifier)
+
+	geocoder, err := geocode.NewGeocoder(cfg.GazetteerPath)
+	if err != nil {
+		log.Fatal("Failed to load gazetteer:", err)
+	}
+
+	dataHandler := data.NewHandler(sheetCache)
+	chartHandler := charts.NewHandler(geocoder, themeStore)
+	themeHandler := themes.NewHandler(themeStore)
+	dashboardHandler := dashboards.NewHandler(dashboardStore, themeStore)
+	geocodeHandler := geocode.NewHandler(geocoder)
+	shareHandler := shares.NewHandler(shareStore, dashboardStore, auth.NewSigner(shareSecret))
+	pageHandler := pages.NewHandler(dashboardStore, shareHandler)
+	templateHandler := templates.NewHandler(charts.NewChartGenerator(geocoder, themeStore), dashboardStore, sheetCache)
 
 	router := gin.Default()
 
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     cfg.AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", shares.PasswordHeader},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 	}))
@@ -39,8 +121,18 @@ func main() {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	router.GET("/assets/*name", assets.Serve)
+	router.GET("/embed/charts/:id", pageHandler.Chart)
+	router.GET("/embed/dashboards/:id", pageHandler.Dashboard)
+	router.GET("/oembed", pageHandler.OEmbed)
+
 	api := router.Group("/api")
 	{
+		if localAuth != nil {
+			localAuthHandler := auth.NewLocalHandler(localAuth)
+			api.POST("/auth/register", localAuthHandler.Register)
+			api.POST("/auth/login", localAuthHandler.Login)
+		}
 		api.POST("/auth/verify-token", authMiddleware.RequireAuth(), func(c *gin.Context) {
 			userID, _ := c.Get("userID")
 			email, _ := c.Get("email")
@@ -55,6 +147,38 @@ func main() {
 		api.POST("/sheets/analyze", dataHandler.AnalyzeSheet)
 		api.POST("/charts/generate", chartHandler.GenerateChart)
 		api.GET("/charts/types", chartHandler.GetChartTypes)
+		api.GET("/charts/palettes", chartHandler.GetPalettes)
+		api.POST("/geocode", geocodeHandler.Geocode)
+
+		themesAPI := api.Group("/themes", authMiddleware.RequireAuth())
+		themesAPI.GET("", themeHandler.List)
+		themesAPI.POST("", themeHandler.Create)
+		themesAPI.GET("/:id", themeHandler.Get)
+		themesAPI.GET("/:id/versions", themeHandler.Versions)
+		themesAPI.PUT("/:id", themeHandler.Update)
+		themesAPI.DELETE("/:id", themeHandler.Delete)
+
+		api.GET("/templates", templateHandler.List)
+		api.POST("/templates/:id/instantiate", authMiddleware.RequireAuth(), templateHandler.Instantiate)
+
+		dashboardsAPI := api.Group("/dashboards", authMiddleware.RequireAuth())
+		dashboardsAPI.GET("", dashboardHandler.List)
+		dashboardsAPI.POST("", dashboardHandler.Create)
+		dashboardsAPI.GET("/:id", dashboardHandler.Get)
+		dashboardsAPI.PUT("/:id", dashboardHandler.Update)
+		dashboardsAPI.DELETE("/:id", dashboardHandler.Delete)
+		dashboardsAPI.GET("/:id/revisions", dashboardHandler.Revisions)
+		dashboardsAPI.GET("/:id/revisions/:rev", dashboardHandler.Revision)
+		dashboardsAPI.POST("/:id/revisions/:rev/restore", dashboardHandler.Restore)
+		dashboardsAPI.GET("/:id/diff", dashboardHandler.Diff)
+		dashboardsAPI.GET("/:id/export", dashboardHandler.Export)
+		dashboardsAPI.GET("/:id/export/html", pageHandler.Export)
+		dashboardsAPI.POST("/import", dashboardHandler.Import)
+		dashboardsAPI.GET("/:id/shares", shareHandler.List)
+		dashboardsAPI.POST("/:id/shares", shareHandler.Create)
+		dashboardsAPI.DELETE("/:id/shares/:shareId", shareHandler.Revoke)
+
+		api.GET("/shared/:token", shareHandler.View)
 	}
 
 	log.Printf("Server starting on port %s", cfg.Port)
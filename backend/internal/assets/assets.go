@@ -0,0 +1,116 @@
+// Package assets embeds the scripts go-echarts pages load — the ECharts
+// core, echarts-gl, the word cloud and liquid fill extensions, the preset
+// themes and maps — so that pages render without reaching the go-echarts
+// assets host. The files are fetched into files/ by go generate before the
+// build, and the server refuses to start without them, see Check.
+package assets
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-echarts/go-echarts/v2/datasets"
+	"github.com/go-echarts/go-echarts/v2/types"
+)
+
+//go:generate go run ./fetch
+
+// Host is the go-echarts assets host the files are fetched from.
+const Host = "https://go-echarts.github.io/go-echarts-assets/assets/"
+
+//go:embed all:files
+var embedded embed.FS
+
+var files = mustSub(embedded, "files")
+
+// Names lists the files pages may load, as named by go-echarts, with
+// every preset map.
+var Names = func() []string {
+	names := []string{
+		"echarts.min.js",
+		"echarts@4.min.js",
+		"echarts-gl.min.js",
+		"echarts-wordcloud.min.js",
+		"echarts-liquidfill.min.js",
+	}
+	for _, theme := range []string{
+		types.ThemeChalk, types.ThemeEssos, types.ThemeInfographic, types.ThemeMacarons,
+		types.ThemePurplePassion, types.ThemeRoma, types.ThemeRomantic, types.ThemeShine,
+		types.ThemeVintage, types.ThemeWalden, types.ThemeWesteros, types.ThemeWonderland,
+	} {
+		names = append(names, "themes/"+theme+".js")
+	}
+	maps := make(map[string]bool)
+	for _, file := range datasets.PresetMapFileNames {
+		maps["maps/"+file+".js"] = true
+	}
+	var sorted []string
+	for name := range maps {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return append(names, sorted...)
+}()
+
+// Check fails when files in Names were not fetched before the build.
+func Check() error {
+	var missing []string
+	for _, name := range Names {
+		if _, err := fs.Stat(files, name); err != nil {
+			missing = append(missing, name)
+		}
+	}
+	n := len(missing)
+	switch {
+	case n == 0:
+		return nil
+	case n > 3:
+		missing = append(missing[:3], "...")
+	}
+	return fmt.Errorf("%d of %d assets are not embedded (%s); run go generate ./internal/assets before building",
+		n, len(Names), strings.Join(missing, ", "))
+}
+
+// Read returns the embedded file name. It fails with fs.ErrNotExist for
+// files not in Names.
+func Read(name string) ([]byte, error) {
+	if !known(name) {
+		return nil, fs.ErrNotExist
+	}
+	return fs.ReadFile(files, name)
+}
+
+// Serve handles GET /assets/*name, serving the embedded files.
+func Serve(c *gin.Context) {
+	name := strings.TrimPrefix(path.Clean(c.Param("name")), "/")
+	body, err := Read(name)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "asset not found"})
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, "application/javascript; charset=utf-8", body)
+}
+
+func known(name string) bool {
+	for _, n := range Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
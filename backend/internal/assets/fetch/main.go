@@ -0,0 +1,46 @@
+// Command fetch downloads the go-echarts assets listed in assets.Names into
+// the files directory, to be embedded in the server. It is run by go
+// generate in the assets package.
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mjrtuhin/loomis-backend/internal/assets"
+)
+
+func main() {
+	client := &http.Client{Timeout: 2 * time.Minute}
+	for _, name := range assets.Names {
+		if err := fetch(client, name); err != nil {
+			log.Fatalf("Failed to fetch %s: %v", name, err)
+		}
+		log.Printf("Fetched %s", name)
+	}
+}
+
+func fetch(client *http.Client, name string) error {
+	resp, err := client.Get(assets.Host + name)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	dest := filepath.Join("files", filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dest, body, 0644)
+}
@@ -0,0 +1,15 @@
+package auth
+
+import "context"
+
+// Identity is the user a verified token belongs to.
+type Identity struct {
+	UID   string
+	Email string
+}
+
+// Verifier checks the bearer tokens of API requests: Firebase ID tokens,
+// or the session tokens of LocalAuth on self-hosted installs.
+type Verifier interface {
+	Verify(ctx context.Context, token string) (*Identity, error)
+}
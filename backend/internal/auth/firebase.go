@@ -39,6 +39,16 @@ func (f *FirebaseAuth) VerifyToken(ctx context.Context, idToken string) (*auth.T
 	return token, nil
 }
 
+// Verify implements Verifier with Firebase ID tokens.
+func (f *FirebaseAuth) Verify(ctx context.Context, idToken string) (*Identity, error) {
+	token, err := f.VerifyToken(ctx, idToken)
+	if err != nil {
+		return nil, err
+	}
+	email, _ := token.Claims["email"].(string)
+	return &Identity{UID: token.UID, Email: email}, nil
+}
+
 func (f *FirebaseAuth) GetUser(ctx context.Context, uid string) (*auth.UserRecord, error) {
 	user, err := f.client.GetUser(ctx, uid)
 	if err != nil {
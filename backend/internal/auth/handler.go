@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LocalHandler serves sign-up and log-in for LocalAuth.
+type LocalHandler struct {
+	auth *LocalAuth
+}
+
+func NewLocalHandler(auth *LocalAuth) *LocalHandler {
+	return &LocalHandler{auth: auth}
+}
+
+type CredentialsRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// Register handles POST /api/auth/register
+func (h *LocalHandler) Register(c *gin.Context) {
+	var req CredentialsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	user, err := h.auth.Register(c.Request.Context(), req.Email, req.Password)
+	switch {
+	case errors.Is(err, ErrEmailTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, ErrInvalidEmail), errors.Is(err, ErrWeakPassword):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"userId": user.ID, "email": user.Email})
+}
+
+// Login handles POST /api/auth/login
+func (h *LocalHandler) Login(c *gin.Context) {
+	var req CredentialsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	token, user, err := h.auth.Login(c.Request.Context(), req.Email, req.Password)
+	if errors.Is(err, ErrInvalidCredentials) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"token": token, "userId": user.ID, "email": user.Email})
+}
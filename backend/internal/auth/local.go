@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mjrtuhin/loomis-backend/internal/storage"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// usersBucket holds local users keyed by normalized email.
+	usersBucket = "users"
+	// secretKey is the key of the generated session token secret.
+	secretKey      = "authSecret"
+	sessionTTL     = 7 * 24 * time.Hour
+	minPasswordLen = 8
+)
+
+var (
+	ErrEmailTaken         = errors.New("email is already registered")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrInvalidEmail       = errors.New("email is not valid")
+	ErrWeakPassword       = fmt.Errorf("password must have at least %d characters", minPasswordLen)
+)
+
+// User is an account of a self-hosted install.
+type User struct {
+	ID           string    `json:"id"`
+	Email        string    `json:"email"`
+	PasswordHash []byte    `json:"passwordHash"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// LocalAuth keeps users in the embedded database and signs its own session
+// tokens, replacing Firebase Authentication on self-hosted installs.
+type LocalAuth struct {
+	db     *storage.DB
+	signer *Signer
+}
+
+// NewLocalAuth creates a LocalAuth on db. Session tokens are signed with
+// secret; when it is empty a random secret is generated on first start
+// and kept in the database, so that sessions survive restarts.
+func NewLocalAuth(db *storage.DB, secret string) (*LocalAuth, error) {
+	key := []byte(secret)
+	if secret == "" {
+		var err error
+		if key, err = StoredSecret(db, secretKey); err != nil {
+			return nil, err
+		}
+	}
+	return &LocalAuth{db: db, signer: NewSigner(key)}, nil
+}
+
+// Register creates a user with email and password.
+func (a *LocalAuth) Register(ctx context.Context, email, password string) (*User, error) {
+	key := normalizeEmail(email)
+	if !strings.Contains(key, "@") {
+		return nil, ErrInvalidEmail
+	}
+	if len(password) < minPasswordLen {
+		return nil, ErrWeakPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 14)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	user := User{ID: hex.EncodeToString(id), Email: strings.TrimSpace(email), PasswordHash: hash, CreatedAt: time.Now().UTC()}
+
+	err = a.db.Update(func(tx *storage.Tx) error {
+		var existing User
+		found, err := tx.Get(usersBucket, key, &existing)
+		if err != nil {
+			return err
+		}
+		if found {
+			return ErrEmailTaken
+		}
+		return tx.Put(usersBucket, key, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Login checks a user's password and returns a new session token.
+func (a *LocalAuth) Login(ctx context.Context, email, password string) (string, *User, error) {
+	var user User
+	found, err := a.db.Get(usersBucket, normalizeEmail(email), &user)
+	if err != nil {
+		return "", nil, err
+	}
+	if !found || bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(password)) != nil {
+		return "", nil, ErrInvalidCredentials
+	}
+	token, err := a.signer.Sign(sessionClaims{UID: user.ID, Email: user.Email, Expires: time.Now().Add(sessionTTL).Unix()})
+	if err != nil {
+		return "", nil, err
+	}
+	return token, &user, nil
+}
+
+// Verify implements Verifier with the session tokens of Login.
+func (a *LocalAuth) Verify(ctx context.Context, token string) (*Identity, error) {
+	var claims sessionClaims
+	if err := a.signer.Verify(token, &claims); err != nil {
+		return nil, err
+	}
+	if time.Now().Unix() >= claims.Expires {
+		return nil, ErrInvalidToken
+	}
+	return &Identity{UID: claims.UID, Email: claims.Email}, nil
+}
+
+// sessionClaims are the signed contents of a session token.
+type sessionClaims struct {
+	UID     string `json:"uid"`
+	Email   string `json:"email"`
+	Expires int64  `json:"exp"`
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjrtuhin/loomis-backend/internal/storage"
+)
+
+func openDB(t *testing.T, path string) *storage.DB {
+	t.Helper()
+	db, err := storage.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newLocalAuth(t *testing.T, db *storage.DB, secret string) *LocalAuth {
+	t.Helper()
+	a, err := NewLocalAuth(db, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestRegister(t *testing.T) {
+	ctx := context.Background()
+	a := newLocalAuth(t, openDB(t, filepath.Join(t.TempDir(), "loomis.db")), "")
+	user, err := a.Register(ctx, " Ada@Example.com ", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID == "" || user.Email != "Ada@Example.com" || string(user.PasswordHash) == "correct horse" {
+		t.Errorf("Register() = %+v, want an ID, the trimmed email and a password hash", user)
+	}
+
+	tests := []struct {
+		name, email, password string
+		want                  error
+	}{
+		{"taken", "ada@example.com", "another password", ErrEmailTaken},
+		{"invalid email", "ada", "correct horse", ErrInvalidEmail},
+		{"weak password", "bob@example.com", "short", ErrWeakPassword},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := a.Register(ctx, tt.email, tt.password); !errors.Is(err, tt.want) {
+				t.Errorf("Register(%q) error = %v, want %v", tt.email, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogin(t *testing.T) {
+	ctx := context.Background()
+	a := newLocalAuth(t, openDB(t, filepath.Join(t.TempDir(), "loomis.db")), "")
+	user, err := a.Register(ctx, "ada@example.com", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, got, err := a.Login(ctx, "ADA@example.com", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != user.ID {
+		t.Errorf("Login() user = %s, want %s", got.ID, user.ID)
+	}
+	id, err := a.Verify(ctx, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.UID != user.ID || id.Email != "ada@example.com" {
+		t.Errorf("Verify() = %+v, want the logged in user", id)
+	}
+
+	for name, creds := range map[string][2]string{
+		"wrong password": {"ada@example.com", "wrong horse"},
+		"unknown email":  {"bob@example.com", "correct horse"},
+	} {
+		if _, _, err := a.Login(ctx, creds[0], creds[1]); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Login() with %s error = %v, want ErrInvalidCredentials", name, err)
+		}
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	a := newLocalAuth(t, openDB(t, filepath.Join(t.TempDir(), "loomis.db")), "secret")
+	expired, err := a.signer.Sign(sessionClaims{UID: "u1", Expires: time.Now().Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Verify(context.Background(), expired); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify() of an expired session error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestSessionSecret(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "loomis.db")
+	db := openDB(t, path)
+	a := newLocalAuth(t, db, "")
+	if _, err := a.Register(ctx, "ada@example.com", "correct horse"); err != nil {
+		t.Fatal(err)
+	}
+	token, _, err := a.Login(ctx, "ada@example.com", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	reopened := openDB(t, path)
+	if _, err := newLocalAuth(t, reopened, "").Verify(ctx, token); err != nil {
+		t.Errorf("Verify() after a restart error = %v, want the stored secret to be reused", err)
+	}
+	if _, err := newLocalAuth(t, reopened, "configured").Verify(ctx, token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify() with another secret error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestLocalHandler(t *testing.T) {
+	h := NewLocalHandler(newLocalAuth(t, openDB(t, filepath.Join(t.TempDir(), "loomis.db")), ""))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/auth/register", h.Register)
+	router.POST("/api/auth/login", h.Login)
+
+	tests := []struct {
+		name, path, body string
+		want             int
+	}{
+		{"register", "/api/auth/register", `{"email": "ada@example.com", "password": "correct horse"}`, http.StatusCreated},
+		{"register taken", "/api/auth/register", `{"email": "ada@example.com", "password": "correct horse"}`, http.StatusConflict},
+		{"register weak", "/api/auth/register", `{"email": "bob@example.com", "password": "short"}`, http.StatusBadRequest},
+		{"register missing", "/api/auth/register", `{"email": "bob@example.com"}`, http.StatusBadRequest},
+		{"login", "/api/auth/login", `{"email": "ada@example.com", "password": "correct horse"}`, http.StatusOK},
+		{"login wrong", "/api/auth/login", `{"email": "ada@example.com", "password": "wrong horse"}`, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+		})
+	}
+}
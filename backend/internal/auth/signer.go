@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mjrtuhin/loomis-backend/internal/storage"
+)
+
+// metaBucket holds server settings such as generated secrets.
+const metaBucket = "meta"
+
+// Signer makes tokens of base64url JSON claims followed by a dot and their
+// HMAC-SHA256.
+type Signer struct {
+	secret []byte
+}
+
+func NewSigner(secret []byte) *Signer {
+	return &Signer{secret: secret}
+}
+
+// Sign encodes claims into a token.
+func (s *Signer) Sign(claims interface{}) (string, error) {
+	raw, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(raw)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.mac(payload)), nil
+}
+
+// Verify checks the signature of token and decodes its claims into
+// claims, returning ErrInvalidToken when either fails. Expiry is left to
+// the caller.
+func (s *Signer) Verify(token string, claims interface{}) error {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalidToken
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, s.mac(payload)) {
+		return ErrInvalidToken
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil || json.Unmarshal(raw, claims) != nil {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+func (s *Signer) mac(payload string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(payload))
+	return h.Sum(nil)
+}
+
+// StoredSecret returns the secret kept under key in db, generating a
+// random one on first use so that tokens survive restarts.
+func StoredSecret(db *storage.DB, key string) ([]byte, error) {
+	var secret []byte
+	err := db.Update(func(tx *storage.Tx) error {
+		found, err := tx.Get(metaBucket, key, &secret)
+		if err != nil || found {
+			return err
+		}
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		return tx.Put(metaBucket, key, secret)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", key, err)
+	}
+	return secret, nil
+}
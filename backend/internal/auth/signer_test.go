@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mjrtuhin/loomis-backend/internal/storage"
+)
+
+type testClaims struct {
+	Subject string `json:"sub"`
+	Expires int64  `json:"exp"`
+}
+
+func TestSigner(t *testing.T) {
+	signer := NewSigner([]byte("secret"))
+	token, err := signer.Sign(testClaims{Subject: "u1", Expires: 1700000000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var claims testClaims
+	if err := signer.Verify(token, &claims); err != nil {
+		t.Fatal(err)
+	}
+	if claims != (testClaims{Subject: "u1", Expires: 1700000000}) {
+		t.Errorf("Verify() claims = %+v, want the signed claims", claims)
+	}
+
+	payload, sig, _ := strings.Cut(token, ".")
+	forged, err := NewSigner([]byte("other")).Sign(testClaims{Subject: "u1", Expires: 1700000000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	extended, err := signer.Sign(testClaims{Subject: "u1", Expires: 1800000000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	extendedPayload, _, _ := strings.Cut(extended, ".")
+	tests := []struct {
+		name, token string
+	}{
+		{"other secret", forged},
+		{"changed claims", extendedPayload + "." + sig},
+		{"changed signature", payload + "." + sig[:len(sig)-2] + "AA"},
+		{"no signature", payload},
+		{"bad encoding", "!!." + sig},
+		{"not JSON", "bm90IGpzb24." + sig},
+		{"empty", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := signer.Verify(tt.token, &testClaims{}); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Verify(%q) error = %v, want ErrInvalidToken", tt.token, err)
+			}
+		})
+	}
+}
+
+func TestStoredSecret(t *testing.T) {
+	db, err := storage.Open(filepath.Join(t.TempDir(), "loomis.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	first, err := StoredSecret(db, "shareSecret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 32 {
+		t.Fatalf("StoredSecret() = %d bytes, want 32", len(first))
+	}
+	again, err := StoredSecret(db, "shareSecret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, again) {
+		t.Error("StoredSecret() generated a new secret for a stored key")
+	}
+	other, err := StoredSecret(db, "authSecret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(first, other) {
+		t.Error("StoredSecret() returned the same secret for two keys")
+	}
+}
@@ -0,0 +1,38 @@
+package charts
+
+import (
+	"strings"
+	"time"
+)
+
+// dateLayouts are the date formats accepted in sheet columns, tried in
+// order. Ambiguous day/month orders are read month first, as Google Sheets
+// does for en-US spreadsheets.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	"2006.01.02",
+	"1/2/2006 15:04:05",
+	"1/2/2006",
+	"2 Jan 2006",
+	"2 January 2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"2006-01",
+}
+
+// ParseDate reads a date in one of dateLayouts. Times without a zone are
+// taken as UTC.
+func ParseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
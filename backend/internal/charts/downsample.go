@@ -0,0 +1,259 @@
+package charts
+
+import (
+	"math"
+	"sort"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+)
+
+// Default point budgets, used when a chart's maxPoints option is unset.
+const (
+	defaultLinePoints    = 2000
+	defaultScatterPoints = 10000
+	defaultCategories    = 500
+)
+
+// Charts drawing more points than progressiveThreshold are rendered
+// progressively, progressiveChunk graphic elements per frame, and in large
+// mode where the series type has one.
+const (
+	progressiveThreshold = 3000
+	progressiveChunk     = 400
+)
+
+// otherCategory collects the categories dropped by topCategories.
+const otherCategory = "Other"
+
+// pointBudget returns the maxPoints option n, or def when it is unset.
+// A budget of 0 turns downsampling off.
+func pointBudget(n *int, def int) int {
+	if n != nil {
+		return *n
+	}
+	return def
+}
+
+// downsample reduces each series of values, the output of seriesValues,
+// to at most budget points with reduce. On a time axis each series keeps
+// its own points, dropping gap markers. On a category axis a category is
+// kept for every series when any series keeps it, and req is returned with
+// only those categories.
+func downsample(req ChartRequest, values [][]interface{}, budget int, reduce func([]overlayPoint, int) []overlayPoint, t *TimeOptions) (ChartRequest, [][]interface{}) {
+	if budget == 0 {
+		return req, values
+	}
+	if t != nil {
+		for j := range values {
+			if len(values[j]) <= budget {
+				continue
+			}
+			kept := reduce(overlayPoints(values[j]), budget)
+			reduced := make([]interface{}, len(kept))
+			for k, p := range kept {
+				reduced[k] = values[j][p.i]
+			}
+			values[j] = reduced
+		}
+		return req, values
+	}
+
+	keep := make(map[int]bool)
+	reduced := false
+	for j := range values {
+		if len(values[j]) <= budget {
+			for i := range values[j] {
+				keep[i] = true
+			}
+			continue
+		}
+		reduced = true
+		for _, p := range reduce(overlayPoints(values[j]), budget) {
+			keep[p.i] = true
+		}
+	}
+	if !reduced {
+		return req, values
+	}
+
+	indices := make([]int, 0, len(keep))
+	for i := range keep {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+
+	labels := make([]string, 0, len(indices))
+	for _, i := range indices {
+		if i < len(req.XAxisData) {
+			labels = append(labels, req.XAxisData[i])
+		}
+	}
+	req.XAxisData = labels
+	for j := range values {
+		kept := make([]interface{}, 0, len(indices))
+		for _, i := range indices {
+			if i < len(values[j]) {
+				kept = append(kept, values[j][i])
+			}
+		}
+		values[j] = kept
+	}
+	return req, values
+}
+
+// lttb picks threshold of points with the Largest-Triangle-Three-Buckets
+// algorithm, which keeps the visual shape of a line: the first and last
+// points, and from each of threshold-2 buckets the point forming the
+// largest triangle with the previously picked point and the average of the
+// next bucket.
+func lttb(points []overlayPoint, threshold int) []overlayPoint {
+	n := len(points)
+	if threshold >= n || threshold < 3 {
+		return points
+	}
+
+	sampled := make([]overlayPoint, 0, threshold)
+	sampled = append(sampled, points[0])
+	every := float64(n-2) / float64(threshold-2)
+	a := 0
+	for b := 0; b < threshold-2; b++ {
+		start := int(float64(b+1)*every) + 1
+		end := int(float64(b+2)*every) + 1
+		if end > n {
+			end = n
+		}
+		var avgX, avgY float64
+		for _, p := range points[start:end] {
+			avgX += p.x
+			avgY += p.y
+		}
+		avgX /= float64(end - start)
+		avgY /= float64(end - start)
+
+		best, bestArea := -1, -1.0
+		for k := int(float64(b)*every) + 1; k < start; k++ {
+			area := math.Abs((points[a].x-avgX)*(points[k].y-points[a].y) - (points[a].x-points[k].x)*(avgY-points[a].y))
+			if area > bestArea {
+				best, bestArea = k, area
+			}
+		}
+		sampled = append(sampled, points[best])
+		a = best
+	}
+	return append(sampled, points[n-1])
+}
+
+// binPoints lays a grid of at most budget cells over the extent of points
+// and keeps the first point falling in each cell, so that dense clouds are
+// thinned while outliers stay.
+func binPoints(points []overlayPoint, budget int) []overlayPoint {
+	if len(points) <= budget {
+		return points
+	}
+	side := int(math.Sqrt(float64(budget)))
+	if side < 1 {
+		side = 1
+	}
+
+	loX, hiX, loY, hiY := math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		loX, hiX = math.Min(loX, p.x), math.Max(hiX, p.x)
+		loY, hiY = math.Min(loY, p.y), math.Max(hiY, p.y)
+	}
+	cell := func(v, lo, hi float64) int {
+		if hi == lo {
+			return 0
+		}
+		c := int((v - lo) / (hi - lo) * float64(side))
+		if c == side {
+			c--
+		}
+		return c
+	}
+
+	seen := make(map[int]bool)
+	var kept []overlayPoint
+	for _, p := range points {
+		c := cell(p.x, loX, hiX)*side + cell(p.y, loY, hiY)
+		if !seen[c] {
+			seen[c] = true
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// topCategories keeps the n-1 categories with the largest absolute total
+// across series, in their original order, and sums the others into a last
+// "Other" category. Requests with at most n categories, or a budget of 0,
+// are returned unchanged.
+func topCategories(req ChartRequest, n int) ChartRequest {
+	if n == 0 || len(req.XAxisData) <= n {
+		return req
+	}
+
+	totals := make([]float64, len(req.XAxisData))
+	for _, s := range req.Series {
+		for i, v := range s.Data {
+			if i < len(totals) {
+				totals[i] += math.Abs(v)
+			}
+		}
+	}
+	order := make([]int, len(totals))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return totals[order[a]] > totals[order[b]] })
+	top := make(map[int]bool, n-1)
+	for _, i := range order[:n-1] {
+		top[i] = true
+	}
+
+	out := req
+	out.XAxisData = make([]string, 0, n)
+	for i, label := range req.XAxisData {
+		if top[i] {
+			out.XAxisData = append(out.XAxisData, label)
+		}
+	}
+	out.XAxisData = append(out.XAxisData, otherCategory)
+
+	out.Series = make([]SeriesData, len(req.Series))
+	for j, s := range req.Series {
+		data := make([]float64, 0, n)
+		var other float64
+		for i, v := range s.Data {
+			if top[i] {
+				data = append(data, v)
+			} else {
+				other += v
+			}
+		}
+		out.Series[j] = SeriesData{Name: s.Name, Data: append(data, other)}
+	}
+	return out
+}
+
+// largeDataOpts turns on progressive rendering for charts drawing more
+// points than progressiveThreshold, and large mode too when large is set,
+// as bar and scatter series support it. ECharts reads the options of the
+// series; the chart-wide ones are kept for renderers that look there.
+func largeDataOpts(values [][]interface{}, large bool) ([]charts.GlobalOpts, []charts.SeriesOpts) {
+	n := 0
+	for _, v := range values {
+		n += len(v)
+	}
+	if n <= progressiveThreshold {
+		return nil, nil
+	}
+	global := []charts.GlobalOpts{
+		charts.WithProgressive(progressiveChunk),
+		charts.WithProgressiveThreshold(progressiveThreshold),
+	}
+	series := []charts.SeriesOpts{charts.WithSeriesProgressive(progressiveChunk, progressiveThreshold)}
+	if large {
+		series = append(series, charts.WithSeriesLarge(progressiveThreshold))
+	}
+	return global, series
+}
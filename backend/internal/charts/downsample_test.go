@@ -0,0 +1,54 @@
+package charts
+
+import (
+	"reflect"
+	"testing"
+)
+
+// linePoints returns points at x = 0, 1, ... with the given values.
+func linePoints(ys ...float64) []overlayPoint {
+	points := make([]overlayPoint, len(ys))
+	for i, y := range ys {
+		points[i] = overlayPoint{i: i, x: float64(i), y: y}
+	}
+	return points
+}
+
+func TestLTTB(t *testing.T) {
+	tests := []struct {
+		name      string
+		points    []overlayPoint
+		threshold int
+		want      []int
+	}{
+		{"spike", linePoints(0, 0, 0, 0, 10, 0, 0, 0, 0, 0), 3, []int{0, 4, 9}},
+		{"peak and trough", linePoints(0, 0, 5, 0, 0, 0, 0, -5, 0, 0), 4, []int{0, 2, 7, 9}},
+		{"under threshold", linePoints(1, 2, 3), 5, []int{0, 1, 2}},
+		{"threshold too small", linePoints(1, 2, 3, 4), 2, []int{0, 1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for _, p := range lttb(tt.points, tt.threshold) {
+				got = append(got, p.i)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lttb() kept %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBinPoints(t *testing.T) {
+	points := []overlayPoint{{0, 0, 0}, {1, 0.1, 0.1}, {2, 0.2, 0}, {3, 1, 1}, {4, 0, 1}}
+	var got []int
+	for _, p := range binPoints(points, 4) {
+		got = append(got, p.i)
+	}
+	if want := []int{0, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("binPoints() kept %v, want %v", got, want)
+	}
+	if kept := binPoints(points, 5); len(kept) != len(points) {
+		t.Errorf("binPoints() within budget kept %d points, want all %d", len(kept), len(points))
+	}
+}
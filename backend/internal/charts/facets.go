@@ -0,0 +1,328 @@
+package charts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// maxFacets bounds the number of panels of a faceted chart.
+const maxFacets = 24
+
+// FacetRequest splits a chart into small multiples, one panel per distinct
+// facet value, all in a single ECharts option.
+type FacetRequest struct {
+	// Values holds the facet of each row, parallel to XAxisData.
+	Values []string `json:"values"`
+	// Columns is the number of panels per row, by default about the square
+	// root of the number of panels.
+	Columns int `json:"columns"`
+	// Scales is "shared" (default), giving every panel the same value range,
+	// or "independent".
+	Scales string `json:"scales"`
+}
+
+// panelFunc builds a chart on a single grid and validates it.
+type panelFunc func(g *ChartGenerator, req ChartRequest) (*charts.RectChart, error)
+
+// validatedChart is a chart whose Validate already ran while it was built as
+// a panel. Validating it again would reset the data of its axes.
+type validatedChart struct {
+	*charts.RectChart
+}
+
+func (validatedChart) Validate() {}
+
+func barPanel(fn func(*ChartGenerator, ChartRequest) (*charts.Bar, error)) panelFunc {
+	return func(g *ChartGenerator, req ChartRequest) (*charts.RectChart, error) {
+		bar, err := fn(g, req)
+		if err != nil {
+			return nil, err
+		}
+		bar.Validate()
+		return &bar.RectChart, nil
+	}
+}
+
+func linePanel(fn func(*ChartGenerator, ChartRequest) (*charts.Line, error)) panelFunc {
+	return func(g *ChartGenerator, req ChartRequest) (*charts.RectChart, error) {
+		line, err := fn(g, req)
+		if err != nil {
+			return nil, err
+		}
+		line.Validate()
+		return &line.RectChart, nil
+	}
+}
+
+func scatterPanel(fn func(*ChartGenerator, ChartRequest) (*charts.Scatter, error)) panelFunc {
+	return func(g *ChartGenerator, req ChartRequest) (*charts.RectChart, error) {
+		scatter, err := fn(g, req)
+		if err != nil {
+			return nil, err
+		}
+		scatter.Validate()
+		return &scatter.RectChart, nil
+	}
+}
+
+// generateFacetedChart builds one panel per facet value and lays the panels
+// out on a grid of opts.Grid components, each with its own axis pair.
+func (g *ChartGenerator) generateFacetedChart(req ChartRequest, panel panelFunc) (map[string]interface{}, error) {
+	f := req.Facet
+	var errs []FieldError
+	if f.Scales != "" && f.Scales != "shared" && f.Scales != "independent" {
+		errs = append(errs, FieldError{Field: "facet.scales", Message: "must be one of shared, independent"})
+	}
+	if f.Columns < 0 {
+		errs = append(errs, FieldError{Field: "facet.columns", Message: "must not be negative"})
+	}
+	names, subs, err := splitFacets(req)
+	if err != nil {
+		errs = append(errs, FieldError{Field: "facet.values", Message: err.Error()})
+	}
+	if len(errs) > 0 {
+		return nil, &OptionsError{ChartType: req.Type, Fields: errs}
+	}
+
+	panels := make([]*charts.RectChart, len(subs))
+	for i, sub := range subs {
+		if panels[i], err = panel(g, sub); err != nil {
+			return nil, err
+		}
+	}
+
+	var xAxes []opts.XAxis
+	var yAxes []opts.YAxis
+	var series charts.MultiSeries
+	for i, p := range panels {
+		x, y := p.XAxisList[0], p.YAxisList[0]
+		x.GridIndex, y.GridIndex = i, i
+		xAxes, yAxes = append(xAxes, x), append(yAxes, y)
+		for _, s := range p.MultiSeries {
+			s.XAxisIndex, s.YAxisIndex = i, i
+			series = append(series, s)
+		}
+	}
+	if f.Scales != "independent" {
+		lo, hi := facetExtent(panels)
+		shareValueScale(xAxes, yAxes, lo, hi)
+	}
+
+	grids, titles := facetLayout(names, f.Columns)
+	chart := panels[0]
+	chart.XAxisList, chart.YAxisList, chart.MultiSeries = xAxes, yAxes, series
+	chart.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithGridOpts(grids...),
+	)
+
+	config, err := g.extractEChartsConfig(validatedChart{chart})
+	if err != nil {
+		return nil, err
+	}
+	config["title"] = append([]interface{}{config["title"]}, titles...)
+	return config, nil
+}
+
+// splitFacets groups the rows of req by facet value, in order of first
+// appearance. Its errors describe the facet values.
+func splitFacets(req ChartRequest) ([]string, []ChartRequest, error) {
+	values := req.Facet.Values
+	if len(values) != len(req.XAxisData) {
+		return nil, nil, fmt.Errorf("must have one value per row, got %d values for %d rows", len(values), len(req.XAxisData))
+	}
+
+	var names []string
+	var subs []ChartRequest
+	index := make(map[string]int)
+	for row, value := range values {
+		i, ok := index[value]
+		if !ok {
+			if len(names) == maxFacets {
+				return nil, nil, fmt.Errorf("must have at most %d distinct values", maxFacets)
+			}
+			i = len(names)
+			index[value] = i
+			names = append(names, value)
+
+			sub := req
+			sub.Title, sub.Facet, sub.XAxisData = "", nil, nil
+			sub.Series = make([]SeriesData, len(req.Series))
+			for j, s := range req.Series {
+				sub.Series[j].Name = s.Name
+			}
+			subs = append(subs, sub)
+		}
+
+		sub := &subs[i]
+		sub.XAxisData = append(sub.XAxisData, req.XAxisData[row])
+		for j, s := range req.Series {
+			if row < len(s.Data) {
+				sub.Series[j].Data = append(sub.Series[j].Data, s.Data[row])
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil, errors.New("must not be empty")
+	}
+	return names, subs, nil
+}
+
+// facetExtent returns the value range over the series drawn in every
+// panel, so after resampling, aggregation and bucketing of small
+// categories. Stacked series are summed per point, positives and negatives
+// apart.
+func facetExtent(panels []*charts.RectChart) (lo, hi float64) {
+	type point struct {
+		stack string
+		index int
+	}
+	for _, p := range panels {
+		pos, neg := make(map[point]float64), make(map[point]float64)
+		for _, s := range p.MultiSeries {
+			for i, v := range seriesNumbers(s.Data) {
+				switch {
+				case math.IsNaN(v):
+				case s.Stack == "":
+					lo, hi = math.Min(lo, v), math.Max(hi, v)
+				case v >= 0:
+					pos[point{s.Stack, i}] += v
+				default:
+					neg[point{s.Stack, i}] += v
+				}
+			}
+		}
+		for _, v := range pos {
+			hi = math.Max(hi, v)
+		}
+		for _, v := range neg {
+			lo = math.Min(lo, v)
+		}
+	}
+	return lo, hi
+}
+
+// seriesNumbers returns the values of the data of a bar, line or scatter
+// series, NaN for missing points. Values of [x, y] pairs are their y.
+func seriesNumbers(data interface{}) []float64 {
+	var values []interface{}
+	switch d := data.(type) {
+	case []opts.BarData:
+		for _, item := range d {
+			values = append(values, item.Value)
+		}
+	case []opts.LineData:
+		for _, item := range d {
+			values = append(values, item.Value)
+		}
+	case []opts.ScatterData:
+		for _, item := range d {
+			values = append(values, item.Value)
+		}
+	}
+	out := make([]float64, len(values))
+	for i, v := range values {
+		out[i] = math.NaN()
+		switch v := v.(type) {
+		case []interface{}:
+			if len(v) > 0 {
+				if f, ok := v[len(v)-1].(float64); ok {
+					out[i] = f
+				}
+			}
+		case []float64:
+			if len(v) > 0 {
+				out[i] = v[len(v)-1]
+			}
+		case float64:
+			out[i] = v
+		}
+	}
+	return out
+}
+
+// shareValueScale gives every value axis without explicit bounds the range
+// [lo, hi], rounded out to nice numbers.
+func shareValueScale(xAxes []opts.XAxis, yAxes []opts.YAxis, lo, hi float64) {
+	min, max := 0.0, niceCeil(hi)
+	if lo < 0 {
+		min = -niceCeil(-lo)
+	}
+	for i := range xAxes {
+		if a := &xAxes[i]; (a.Type == "value" || a.Type == "log") && a.Min == nil && a.Max == nil {
+			if a.Type == "value" {
+				a.Min = min
+			}
+			a.Max = max
+		}
+	}
+	for i := range yAxes {
+		if a := &yAxes[i]; (a.Type == "value" || a.Type == "log") && a.Min == nil && a.Max == nil {
+			if a.Type == "value" {
+				a.Min = min
+			}
+			a.Max = max
+		}
+	}
+}
+
+// niceCeil rounds x up to 1, 2, 2.5 or 5 times a power of ten.
+func niceCeil(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	scale := math.Pow(10, math.Floor(math.Log10(x)))
+	for _, step := range []float64{1, 2, 2.5, 5, 10} {
+		if x <= step*scale {
+			return step * scale
+		}
+	}
+	return 10 * scale
+}
+
+// facetLayout places the panels row by row below the main title and legend,
+// returning one grid and one panel title per facet.
+func facetLayout(names []string, columns int) ([]opts.Grid, []interface{}) {
+	const top, bottom, left, right, hGap, vGap = 14.0, 4.0, 3.0, 3.0, 4.0, 9.0
+
+	n := len(names)
+	if columns == 0 {
+		columns = int(math.Ceil(math.Sqrt(float64(n))))
+	}
+	if columns > n {
+		columns = n
+	}
+	rows := (n + columns - 1) / columns
+	width := (100 - left - right - float64(columns-1)*hGap) / float64(columns)
+	height := (100 - top - bottom - float64(rows-1)*vGap) / float64(rows)
+
+	grids := make([]opts.Grid, n)
+	titles := make([]interface{}, n)
+	for i, name := range names {
+		x := left + float64(i%columns)*(width+hGap)
+		y := top + float64(i/columns)*(height+vGap)
+		grids[i] = opts.Grid{
+			Left:         percent(x),
+			Top:          percent(y),
+			Width:        percent(width),
+			Height:       percent(height),
+			ContainLabel: opts.Bool(true),
+		}
+		titles[i] = map[string]interface{}{
+			"text":      name,
+			"left":      percent(x + width/2),
+			"top":       percent(y - 4),
+			"textAlign": "center",
+			"textStyle": map[string]interface{}{"fontSize": 12, "fontWeight": "normal"},
+		}
+	}
+	return grids, titles
+}
+
+func percent(v float64) string {
+	return fmt.Sprintf("%.2f%%", v)
+}
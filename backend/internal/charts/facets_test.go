@@ -0,0 +1,116 @@
+package charts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestFacetSharedScale(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     ChartRequest
+		wantMax float64
+	}{
+		{
+			name: "raw values",
+			req: ChartRequest{
+				Type:      "bar",
+				XAxisData: []string{"a", "b", "a", "b"},
+				Series:    []SeriesData{{Name: "s", Data: []float64{3, 7, 12, 4}}},
+				Facet:     &FacetRequest{Values: []string{"x", "x", "y", "y"}},
+			},
+			wantMax: 20,
+		},
+		{
+			name: "stacked",
+			req: ChartRequest{
+				Type:      "bar_stacked",
+				XAxisData: []string{"a", "a"},
+				Series:    []SeriesData{{Name: "s", Data: []float64{6, 1}}, {Name: "t", Data: []float64{6, 1}}},
+				Facet:     &FacetRequest{Values: []string{"x", "y"}},
+			},
+			wantMax: 20,
+		},
+		{
+			name: "resampled",
+			req: ChartRequest{
+				Type:      "bar",
+				XAxisData: []string{"2024-01-01", "2024-01-02", "2024-01-03", "2024-01-01"},
+				Series:    []SeriesData{{Name: "s", Data: []float64{10, 10, 10, 4}}},
+				Options:   json.RawMessage(`{"time": {"resample": "month", "aggregate": "sum"}}`),
+				Facet:     &FacetRequest{Values: []string{"x", "x", "x", "y"}},
+			},
+			wantMax: 50,
+		},
+		{
+			name: "other category",
+			req: ChartRequest{
+				Type:      "bar",
+				XAxisData: []string{"a", "b", "c", "d", "a"},
+				Series:    []SeriesData{{Name: "s", Data: []float64{9, 8, 8, 8, 1}}},
+				Options:   json.RawMessage(`{"maxPoints": 3}`),
+				Facet:     &FacetRequest{Values: []string{"x", "x", "x", "x", "y"}},
+			},
+			wantMax: 20,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewChartGenerator(nil, nil).GenerateChart(context.Background(), tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := json.Marshal(resp.ChartConfig["yAxis"])
+			if err != nil {
+				t.Fatal(err)
+			}
+			var axes []struct {
+				Max float64 `json:"max"`
+			}
+			if err := json.Unmarshal(b, &axes); err != nil {
+				t.Fatal(err)
+			}
+			if len(axes) != 2 {
+				t.Fatalf("got %d y axes, want 2", len(axes))
+			}
+			for i, a := range axes {
+				if a.Max != tt.wantMax {
+					t.Errorf("y axis %d max = %v, want %v", i, a.Max, tt.wantMax)
+				}
+			}
+		})
+	}
+}
+
+func TestFacetErrors(t *testing.T) {
+	many := make([]string, maxFacets+1)
+	for i := range many {
+		many[i] = string(rune('a' + i))
+	}
+	tests := []struct {
+		name      string
+		req       ChartRequest
+		wantField string
+	}{
+		{"values per row", ChartRequest{Type: "bar", XAxisData: []string{"a", "b"}, Facet: &FacetRequest{Values: []string{"x"}}}, "facet.values"},
+		{"too many panels", ChartRequest{Type: "bar", XAxisData: many, Facet: &FacetRequest{Values: many}}, "facet.values"},
+		{"no rows", ChartRequest{Type: "bar", Facet: &FacetRequest{}}, "facet.values"},
+		{"scales", ChartRequest{Type: "bar", XAxisData: []string{"a"}, Facet: &FacetRequest{Values: []string{"x"}, Scales: "free"}}, "facet.scales"},
+		{"columns", ChartRequest{Type: "bar", XAxisData: []string{"a"}, Facet: &FacetRequest{Values: []string{"x"}, Columns: -1}}, "facet.columns"},
+		{"chart type", ChartRequest{Type: "pie", XAxisData: []string{"a"}, Facet: &FacetRequest{Values: []string{"x"}}}, "facet"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewChartGenerator(nil, nil).GenerateChart(context.Background(), tt.req)
+			var optsErr *OptionsError
+			if !errors.As(err, &optsErr) {
+				t.Fatalf("GenerateChart() error = %v, want an OptionsError", err)
+			}
+			if len(optsErr.Fields) != 1 || optsErr.Fields[0].Field != tt.wantField {
+				t.Errorf("GenerateChart() fields = %+v, want one on %s", optsErr.Fields, tt.wantField)
+			}
+		})
+	}
+}
@@ -1,26 +1,60 @@
 package charts
 
 import (
-	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/go-echarts/go-echarts/v2/charts"
 	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/go-echarts/go-echarts/v2/types"
+	"github.com/mjrtuhin/loomis-backend/internal/geocode"
+	"github.com/mjrtuhin/loomis-backend/internal/themes"
 )
 
-type ChartGenerator struct{}
+type ChartGenerator struct {
+	geocoder    *geocode.Geocoder
+	brandThemes themes.Store
+	registry    *Registry
+}
 
-func NewChartGenerator() *ChartGenerator {
-	return &ChartGenerator{}
+// NewChartGenerator creates a generator. geocoder is used to place geo chart
+// points given by name only and brandThemes to look up the brandTheme
+// option; either may be nil.
+func NewChartGenerator(geocoder *geocode.Geocoder, brandThemes themes.Store) *ChartGenerator {
+	return &ChartGenerator{geocoder: geocoder, brandThemes: brandThemes, registry: NewRegistry()}
 }
 
 type ChartRequest struct {
-	Type      string                 `json:"type"`
-	Title     string                 `json:"title"`
-	XAxisData []string               `json:"xAxisData"`
-	Series    []SeriesData           `json:"series"`
-	Options   map[string]interface{} `json:"options"`
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	XAxisData []string     `json:"xAxisData"`
+	Series    []SeriesData `json:"series"`
+	// Options holds the display options of the chart type, see options.go.
+	Options json.RawMessage `json:"options"`
+
+	// MapType selects the registered map for map and geo charts, e.g.
+	// "world", "usa" or "china". Defaults to "world".
+	MapType string `json:"mapType"`
+	// Points holds lat/long points for geo charts. When empty, XAxisData is
+	// treated as place names and geocoded, with values from the first series.
+	Points []GeoPoint `json:"points"`
+	// Flows holds origin/destination pairs drawn as lines on globe charts.
+	Flows []GeoFlow `json:"flows"`
+	// Tasks holds the rows of Gantt charts.
+	Tasks []GanttTask `json:"tasks"`
+	// Links holds the rows of sankey, graph and tree charts.
+	Links []ChartLink `json:"links"`
+	// Facet, when set, repeats the chart once per facet value.
+	Facet *FacetRequest `json:"facet"`
+	// Marks adds reference lines, bands and points to bar, line and
+	// scatter charts.
+	Marks *Marks `json:"marks"`
 }
 
 type SeriesData struct {
@@ -28,260 +62,399 @@ type SeriesData struct {
 	Data []float64 `json:"data"`
 }
 
+type GeoPoint struct {
+	Name  string  `json:"name"`
+	Lat   float64 `json:"lat"`
+	Lng   float64 `json:"lng"`
+	Value float64 `json:"value"`
+}
+
+// GeoFlow is a line between two places. Endpoints without coordinates are
+// geocoded from their names.
+type GeoFlow struct {
+	From    string  `json:"from"`
+	To      string  `json:"to"`
+	FromLat float64 `json:"fromLat"`
+	FromLng float64 `json:"fromLng"`
+	ToLat   float64 `json:"toLat"`
+	ToLng   float64 `json:"toLng"`
+	Value   float64 `json:"value"`
+}
+
+// GanttTask is one bar of a Gantt chart. Start and End are dates in any of
+// the formats in dateLayouts; tasks sharing a Group share a color.
+type GanttTask struct {
+	Name  string `json:"name"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Group string `json:"group"`
+}
+
+// ChartLink is one row of a relationship chart: an edge from Source to
+// Target weighted by Value. Tree charts read Source as the parent of
+// Target; an empty Source makes Target a root.
+type ChartLink struct {
+	Source string  `json:"source"`
+	Target string  `json:"target"`
+	Value  float64 `json:"value"`
+}
+
 type ChartResponse struct {
 	ChartConfig map[string]interface{} `json:"chartConfig"`
+	// Theme is the ECharts theme to initialize the chart with, if any.
+	Theme string `json:"theme,omitempty"`
+	// UnmatchedRegions lists region names that could not be resolved
+	// against the selected map.
+	UnmatchedRegions []string `json:"unmatchedRegions,omitempty"`
 }
 
-func (g *ChartGenerator) GenerateChart(req ChartRequest) (*ChartResponse, error) {
-	var chartConfig map[string]interface{}
-	var err error
-
-	switch req.Type {
-	// BASIC CHARTS
-	case "bar":
-		chartConfig, err = g.generateBarChart(req)
-	case "bar_horizontal":
-		chartConfig, err = g.generateHorizontalBarChart(req)
-	case "bar_stacked":
-		chartConfig, err = g.generateStackedBarChart(req)
-	case "bar3d":
-		chartConfig, err = g.generateBar3DChart(req)
-	case "line":
-		chartConfig, err = g.generateLineChart(req)
-	case "line_smooth":
-		chartConfig, err = g.generateSmoothLineChart(req)
-	case "line_area":
-		chartConfig, err = g.generateAreaChart(req)
-	case "line3d":
-		chartConfig, err = g.generateLine3DChart(req)
-	case "pie":
-		chartConfig, err = g.generatePieChart(req)
-	case "pie_doughnut":
-		chartConfig, err = g.generateDoughnutChart(req)
-	case "scatter":
-		chartConfig, err = g.generateScatterChart(req)
-	case "scatter_effect":
-		chartConfig, err = g.generateEffectScatterChart(req)
-	case "scatter3d":
-		chartConfig, err = g.generateScatter3DChart(req)
-		
-	// STATISTICAL CHARTS
-	case "heatmap":
-		chartConfig, err = g.generateHeatmapChart(req)
-	case "boxplot":
-		chartConfig, err = g.generateBoxPlotChart(req)
-	case "candlestick":
-		chartConfig, err = g.generateCandlestickChart(req)
-		
-	// SPECIALIZED CHARTS
-	case "radar":
-		chartConfig, err = g.generateRadarChart(req)
-	case "funnel":
-		chartConfig, err = g.generateFunnelChart(req)
-	case "gauge":
-		chartConfig, err = g.generateGaugeChart(req)
-	case "wordcloud":
-		chartConfig, err = g.generateWordCloudChart(req)
-	case "liquid":
-		chartConfig, err = g.generateLiquidChart(req)
-	case "themeriver":
-		chartConfig, err = g.generateThemeRiverChart(req)
-		
-	// RELATIONSHIP CHARTS
-	case "graph":
-		chartConfig, err = g.generateGraphChart(req)
-	case "sankey":
-		chartConfig, err = g.generateSankeyChart(req)
-	case "tree":
-		chartConfig, err = g.generateTreeChart(req)
-	case "treemap":
-		chartConfig, err = g.generateTreemapChart(req)
-	case "sunburst":
-		chartConfig, err = g.generateSunburstChart(req)
-		
-	// MULTI-DIMENSIONAL
-	case "parallel":
-		chartConfig, err = g.generateParallelChart(req)
-		
-	// GEOGRAPHIC
-	case "geo":
-		chartConfig, err = g.generateGeoChart(req)
-	case "map":
-		chartConfig, err = g.generateMapChart(req)
-		
-	// 3D CHARTS
-	case "surface3d":
-		chartConfig, err = g.generateSurface3DChart(req)
-	case "globe":
-		chartConfig, err = g.generateGlobeChart(req)
-		
-	default:
-		return nil, fmt.Errorf("unsupported chart type: %s", req.Type)
+func (g *ChartGenerator) GenerateChart(ctx context.Context, req ChartRequest) (*ChartResponse, error) {
+	chartType, ok := g.registry.Lookup(req.Type)
+	if !ok {
+		return nil, inputError(req, "type", "%q is not a supported chart type", req.Type)
 	}
 
+	// Marks are drawn by the builders of single-grid charts, which are also
+	// the ones that can be faceted.
+	if req.Marks != nil && chartType.panel == nil {
+		return nil, &OptionsError{ChartType: req.Type, Fields: []FieldError{
+			{Field: "marks", Message: fmt.Sprintf("is not supported by %s charts", req.Type)},
+		}}
+	}
+	if req.Facet != nil {
+		if chartType.panel == nil {
+			return nil, &OptionsError{ChartType: req.Type, Fields: []FieldError{
+				{Field: "facet", Message: fmt.Sprintf("is not supported by %s charts", req.Type)},
+			}}
+		}
+		chartConfig, err := g.generateFacetedChart(req, chartType.panel)
+		if err != nil {
+			return nil, err
+		}
+		return g.styledResponse(ctx, req, chartConfig, nil)
+	}
+
+	chartConfig, unmatched, err := chartType.generate(g, req)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ChartResponse{ChartConfig: chartConfig}, nil
+	return g.styledResponse(ctx, req, chartConfig, unmatched)
 }
 
-// BASIC CHARTS
-func (g *ChartGenerator) generateBarChart(req ChartRequest) (map[string]interface{}, error) {
-	bar := charts.NewBar()
-	bar.SetGlobalOptions(
-		charts.WithTitleOpts(opts.Title{Title: req.Title}),
-		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
-		charts.WithLegendOpts(opts.Legend{Show: opts.Bool(true)}),
-	)
-	bar.SetXAxis(req.XAxisData)
-	for _, series := range req.Series {
-		items := make([]opts.BarData, len(series.Data))
-		for i, v := range series.Data {
-			items[i] = opts.BarData{Value: v}
+// inputError reports request data that does not fit its chart type, such
+// as missing series or unreadable dates, as an error on field.
+func inputError(req ChartRequest, field, format string, args ...interface{}) error {
+	return &OptionsError{ChartType: req.Type, Fields: []FieldError{{Field: field, Message: fmt.Sprintf(format, args...)}}}
+}
+
+// styledResponse applies the style options of req that go-echarts cannot
+// express to the generated config: the brand theme, then the pinned
+// category colors.
+func (g *ChartGenerator) styledResponse(ctx context.Context, req ChartRequest, chartConfig map[string]interface{}, unmatched []string) (*ChartResponse, error) {
+	style := readStyle(req)
+	if style.BrandTheme != "" {
+		brand, err := g.brandTheme(ctx, req, style)
+		if err != nil {
+			return nil, err
 		}
-		bar.AddSeries(series.Name, items)
+		brand.Definition.Apply(chartConfig, len(style.Colors) == 0 && style.Palette == "")
 	}
-	return g.extractEChartsConfig(bar)
+	pinCategoryColors(chartConfig, style.CategoryColors)
+	return &ChartResponse{ChartConfig: chartConfig, Theme: style.Theme, UnmatchedRegions: unmatched}, nil
 }
 
-func (g *ChartGenerator) generateHorizontalBarChart(req ChartRequest) (map[string]interface{}, error) {
-	bar := charts.NewBar()
-	bar.SetGlobalOptions(
-		charts.WithTitleOpts(opts.Title{Title: req.Title}),
-		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
-		charts.WithLegendOpts(opts.Legend{Show: opts.Bool(true)}),
-	)
-	bar.SetXAxis(req.XAxisData)
-	for _, series := range req.Series {
-		items := make([]opts.BarData, len(series.Data))
-		for i, v := range series.Data {
-			items[i] = opts.BarData{Value: v}
+// brandTheme looks up the brand theme selected by style, reporting unknown
+// themes as invalid options.
+func (g *ChartGenerator) brandTheme(ctx context.Context, req ChartRequest, style styleOptions) (*themes.Theme, error) {
+	if g.brandThemes == nil {
+		return nil, fmt.Errorf("brand themes are not available")
+	}
+	brand, err := g.brandThemes.Get(ctx, style.BrandTheme, style.BrandThemeVersion)
+	if errors.Is(err, themes.ErrNotFound) {
+		field := "options.brandTheme"
+		if style.BrandThemeVersion != 0 {
+			field = "options.brandThemeVersion"
 		}
-		bar.AddSeries(series.Name, items)
+		return nil, &OptionsError{ChartType: req.Type, Fields: []FieldError{{Field: field, Message: err.Error()}}}
 	}
-	bar.XYReversal()
-	return g.extractEChartsConfig(bar)
+	return brand, err
+}
+
+// Palettes returns the built-in color palettes.
+func (g *ChartGenerator) Palettes() []Palette {
+	return palettes
+}
+
+// Themes returns the names of the selectable themes.
+func (g *ChartGenerator) Themes() []string {
+	return themeNames
+}
+
+// ChartTypes returns every chart type the generator supports.
+func (g *ChartGenerator) ChartTypes() []*ChartType {
+	return g.registry.Types()
+}
+
+// BASIC CHARTS
+func (g *ChartGenerator) barChart(req ChartRequest) (*charts.Bar, error) {
+	var o BarOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	return g.newBarChart(req, o)
+}
+
+func (g *ChartGenerator) horizontalBarChart(req ChartRequest) (*charts.Bar, error) {
+	var o BarOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	if o.Orientation == "" {
+		o.Orientation = "horizontal"
+	}
+	return g.newBarChart(req, o)
+}
+
+func (g *ChartGenerator) stackedBarChart(req ChartRequest) (*charts.Bar, error) {
+	var o BarOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	o.Stack = true
+	return g.newBarChart(req, o)
 }
 
-func (g *ChartGenerator) generateStackedBarChart(req ChartRequest) (map[string]interface{}, error) {
+func (g *ChartGenerator) newBarChart(req ChartRequest, o BarOptions) (*charts.Bar, error) {
+	if o.Time == nil {
+		req = topCategories(req, pointBudget(o.MaxPoints, defaultCategories))
+	}
+	values, err := seriesValues(req, o.Time)
+	if err != nil {
+		return nil, err
+	}
+
 	bar := charts.NewBar()
 	bar.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
-		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
-		charts.WithLegendOpts(opts.Legend{Show: opts.Bool(true)}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
 	)
-	bar.SetXAxis(req.XAxisData)
-	for _, series := range req.Series {
-		items := make([]opts.BarData, len(series.Data))
-		for i, v := range series.Data {
+	bar.SetGlobalOptions(o.globalOpts()...)
+	largeGlobal, largeSeries := largeDataOpts(values, true)
+	bar.SetGlobalOptions(largeGlobal...)
+
+	category := opts.XAxis{Type: categoryAxisType(o.Time)}
+	if o.CategoryAxis != nil {
+		category.Name = o.CategoryAxis.Name
+	}
+	valueX, valueY := valueAxis(o.ValueAxis)
+	if o.Orientation == "horizontal" {
+		bar.SetGlobalOptions(
+			charts.WithXAxisOpts(valueX),
+			charts.WithYAxisOpts(opts.YAxis{Type: category.Type, Name: category.Name}),
+		)
+		bar.XYReversal()
+	} else {
+		bar.SetGlobalOptions(charts.WithXAxisOpts(category), charts.WithYAxisOpts(valueY))
+	}
+
+	var encode []charts.SeriesOpts
+	if o.Orientation == "horizontal" && o.Time != nil {
+		// Time values are [millis, value] pairs, with the time on the y axis.
+		encode = append(encode, charts.WithEncodeOpts(opts.Encode{X: 1, Y: 0}))
+	}
+	seriesOpts := append([]charts.SeriesOpts{charts.WithLabelOpts(labelOpts(o.Labels))}, encode...)
+	seriesOpts = append(seriesOpts, largeSeries...)
+	if o.Stack {
+		seriesOpts = append(seriesOpts, charts.WithBarChartOpts(opts.BarChart{Stack: "total"}))
+	}
+
+	for j, series := range req.Series {
+		items := make([]opts.BarData, len(values[j]))
+		for i, v := range values[j] {
 			items[i] = opts.BarData{Value: v}
 		}
-		bar.AddSeries(series.Name, items, charts.WithBarChartOpts(opts.BarChart{Stack: "total"}))
+		bar.AddSeries(series.Name, items, seriesOpts...)
 	}
-	return g.extractEChartsConfig(bar)
+	if err := addMarks(&bar.RectChart, req, values, o.Time, o.Orientation == "horizontal"); err != nil {
+		return nil, err
+	}
+	labels, err := addOverlays(&bar.RectChart, req, values, o.Overlays, o.Time, o.Orientation == "horizontal")
+	if err != nil {
+		return nil, err
+	}
+	if o.Time == nil {
+		bar.SetXAxis(labels)
+	}
+	return bar, nil
 }
 
+// generateBar3DChart raises one bar per cell of a grid with one column per
+// XAxisData label and one row per series.
 func (g *ChartGenerator) generateBar3DChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CommonOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
 	bar3d := charts.NewBar3D()
 	bar3d.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
 		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
 	)
-	return g.extractEChartsConfig(bar3d)
-}
-
-func (g *ChartGenerator) generateLineChart(req ChartRequest) (map[string]interface{}, error) {
-	line := charts.NewLine()
-	line.SetGlobalOptions(
-		charts.WithTitleOpts(opts.Title{Title: req.Title}),
-		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
-		charts.WithLegendOpts(opts.Legend{Show: opts.Bool(true)}),
+	bar3d.SetGlobalOptions(o.globalOpts()...)
+	bar3d.SetGlobalOptions(
+		charts.WithXAxis3DOpts(opts.XAxis3D{Type: "category", Data: req.XAxisData}),
+		charts.WithYAxis3DOpts(opts.YAxis3D{Type: "category", Data: seriesNames(req.Series)}),
+		charts.WithZAxis3DOpts(opts.ZAxis3D{Type: "value"}),
 	)
-	line.SetXAxis(req.XAxisData)
-	for _, series := range req.Series {
-		items := make([]opts.LineData, len(series.Data))
+
+	var items []opts.Chart3DData
+	var values []float64
+	for j, series := range req.Series {
 		for i, v := range series.Data {
-			items[i] = opts.LineData{Value: v}
+			if i >= len(req.XAxisData) {
+				break
+			}
+			items = append(items, opts.Chart3DData{Value: []interface{}{i, j, v}})
+			values = append(values, v)
 		}
-		line.AddSeries(series.Name, items)
 	}
-	return g.extractEChartsConfig(line)
+	bar3d.AddSeries("values", items)
+	if len(values) > 0 {
+		bar3d.SetGlobalOptions(charts.WithVisualMapOpts(valueVisualMap(values, nil)))
+	}
+	return g.extractEChartsConfig(bar3d)
 }
 
-func (g *ChartGenerator) generateSmoothLineChart(req ChartRequest) (map[string]interface{}, error) {
-	line := charts.NewLine()
-	line.SetGlobalOptions(
-		charts.WithTitleOpts(opts.Title{Title: req.Title}),
-		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
-		charts.WithLegendOpts(opts.Legend{Show: opts.Bool(true)}),
-	)
-	line.SetXAxis(req.XAxisData)
-	for _, series := range req.Series {
-		items := make([]opts.LineData, len(series.Data))
-		for i, v := range series.Data {
-			items[i] = opts.LineData{Value: v}
-		}
-		line.AddSeries(series.Name, items, charts.WithLineChartOpts(opts.LineChart{Smooth: opts.Bool(true)}))
+func (g *ChartGenerator) lineChart(req ChartRequest) (*charts.Line, error) {
+	var o LineOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
 	}
-	return g.extractEChartsConfig(line)
+	return g.newLineChart(req, o)
+}
+
+func (g *ChartGenerator) smoothLineChart(req ChartRequest) (*charts.Line, error) {
+	var o LineOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	o.Smooth = true
+	return g.newLineChart(req, o)
+}
+
+func (g *ChartGenerator) areaChart(req ChartRequest) (*charts.Line, error) {
+	var o LineOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	o.Area = true
+	return g.newLineChart(req, o)
 }
 
-func (g *ChartGenerator) generateAreaChart(req ChartRequest) (map[string]interface{}, error) {
+func (g *ChartGenerator) newLineChart(req ChartRequest, o LineOptions) (*charts.Line, error) {
+	values, err := seriesValues(req, o.Time)
+	if err != nil {
+		return nil, err
+	}
+	req, values = downsample(req, values, pointBudget(o.MaxPoints, defaultLinePoints), lttb, o.Time)
+
 	line := charts.NewLine()
 	line.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
-		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
-		charts.WithLegendOpts(opts.Legend{Show: opts.Bool(true)}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
 	)
-	line.SetXAxis(req.XAxisData)
-	for _, series := range req.Series {
-		items := make([]opts.LineData, len(series.Data))
-		for i, v := range series.Data {
+	line.SetGlobalOptions(o.globalOpts()...)
+	largeGlobal, largeSeries := largeDataOpts(values, false)
+	line.SetGlobalOptions(largeGlobal...)
+
+	category := opts.XAxis{Type: categoryAxisType(o.Time)}
+	if o.CategoryAxis != nil {
+		category.Name = o.CategoryAxis.Name
+	}
+	_, valueY := valueAxis(o.ValueAxis)
+	line.SetGlobalOptions(charts.WithXAxisOpts(category), charts.WithYAxisOpts(valueY))
+
+	lineOpts := opts.LineChart{Smooth: opts.Bool(o.Smooth)}
+	if o.Stack {
+		lineOpts.Stack = "total"
+	}
+	seriesOpts := []charts.SeriesOpts{
+		charts.WithLineChartOpts(lineOpts),
+		charts.WithLabelOpts(labelOpts(o.Labels)),
+	}
+	seriesOpts = append(seriesOpts, largeSeries...)
+	if o.Area {
+		seriesOpts = append(seriesOpts, charts.WithAreaStyleOpts(opts.AreaStyle{}))
+	}
+
+	for j, series := range req.Series {
+		items := make([]opts.LineData, len(values[j]))
+		for i, v := range values[j] {
 			items[i] = opts.LineData{Value: v}
 		}
-		line.AddSeries(series.Name, items, charts.WithAreaStyleOpts(opts.AreaStyle{}))
+		line.AddSeries(series.Name, items, seriesOpts...)
 	}
-	return g.extractEChartsConfig(line)
+	if err := addMarks(&line.RectChart, req, values, o.Time, false); err != nil {
+		return nil, err
+	}
+	labels, err := addOverlays(&line.RectChart, req, values, o.Overlays, o.Time, false)
+	if err != nil {
+		return nil, err
+	}
+	if o.Time == nil {
+		line.SetXAxis(labels)
+	}
+	return line, nil
 }
 
+// generateLine3DChart draws a path through the points given by the first
+// three series, in row order.
 func (g *ChartGenerator) generateLine3DChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CommonOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	points, err := points3D(req)
+	if err != nil {
+		return nil, err
+	}
 	line3d := charts.NewLine3D()
 	line3d.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
 	)
+	line3d.SetGlobalOptions(o.globalOpts()...)
+	line3d.SetGlobalOptions(chart3DAxes(req)...)
+	line3d.AddSeries(req.Series[2].Name, chart3DData(req, points))
 	return g.extractEChartsConfig(line3d)
 }
 
 func (g *ChartGenerator) generatePieChart(req ChartRequest) (map[string]interface{}, error) {
-	pie := charts.NewPie()
-	pie.SetGlobalOptions(
-		charts.WithTitleOpts(opts.Title{Title: req.Title}),
-		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
-		charts.WithLegendOpts(opts.Legend{Show: opts.Bool(true)}),
-	)
-	if len(req.Series) > 0 {
-		items := make([]opts.PieData, len(req.Series[0].Data))
-		for i, v := range req.Series[0].Data {
-			name := ""
-			if i < len(req.XAxisData) {
-				name = req.XAxisData[i]
-			}
-			items[i] = opts.PieData{Name: name, Value: v}
-		}
-		pie.AddSeries("pie", items)
+	var o PieOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
 	}
-	return g.extractEChartsConfig(pie)
+	return g.buildPieChart(req, o, o.radius(0, 75))
 }
 
 func (g *ChartGenerator) generateDoughnutChart(req ChartRequest) (map[string]interface{}, error) {
+	var o PieOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	return g.buildPieChart(req, o, o.radius(40, 75))
+}
+
+func (g *ChartGenerator) buildPieChart(req ChartRequest, o PieOptions, radius []string) (map[string]interface{}, error) {
 	pie := charts.NewPie()
 	pie.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
 		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
-		charts.WithLegendOpts(opts.Legend{Show: opts.Bool(true)}),
 	)
+	pie.SetGlobalOptions(o.globalOpts()...)
 	if len(req.Series) > 0 {
+		req.Series = req.Series[:1]
+		req = topCategories(req, pointBudget(o.MaxPoints, defaultCategories))
 		items := make([]opts.PieData, len(req.Series[0].Data))
 		for i, v := range req.Series[0].Data {
 			name := ""
@@ -290,90 +463,656 @@ func (g *ChartGenerator) generateDoughnutChart(req ChartRequest) (map[string]int
 			}
 			items[i] = opts.PieData{Name: name, Value: v}
 		}
-		pie.AddSeries("pie", items).SetSeriesOptions(charts.WithPieChartOpts(opts.PieChart{Radius: []string{"40%", "75%"}}))
+		pie.AddSeries("pie", items,
+			charts.WithPieChartOpts(opts.PieChart{Radius: radius, RoseType: o.RoseType}),
+			charts.WithLabelOpts(labelOpts(o.Labels)),
+		)
 	}
 	return g.extractEChartsConfig(pie)
 }
 
-func (g *ChartGenerator) generateScatterChart(req ChartRequest) (map[string]interface{}, error) {
+func (g *ChartGenerator) scatterChart(req ChartRequest) (*charts.Scatter, error) {
+	var o ScatterOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
 	scatter := charts.NewScatter()
 	scatter.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
 		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
-		charts.WithLegendOpts(opts.Legend{Show: opts.Bool(true)}),
 	)
-	scatter.SetXAxis(req.XAxisData)
-	for _, series := range req.Series {
-		items := make([]opts.ScatterData, len(series.Data))
-		for i, v := range series.Data {
+	scatter.SetGlobalOptions(o.globalOpts()...)
+	_, valueY := valueAxis(o.YAxis)
+	scatter.SetGlobalOptions(charts.WithYAxisOpts(valueY))
+	if o.Time != nil {
+		scatter.SetGlobalOptions(charts.WithXAxisOpts(opts.XAxis{Type: "time"}))
+	}
+
+	values, err := seriesValues(req, o.Time)
+	if err != nil {
+		return nil, err
+	}
+	req, values = downsample(req, values, pointBudget(o.MaxPoints, defaultScatterPoints), binPoints, o.Time)
+	largeGlobal, largeSeries := largeDataOpts(values, true)
+	scatter.SetGlobalOptions(largeGlobal...)
+	seriesOpts := append(o.seriesOpts(), largeSeries...)
+	for j, series := range req.Series {
+		items := make([]opts.ScatterData, len(values[j]))
+		for i, v := range values[j] {
 			items[i] = opts.ScatterData{Value: v}
 		}
-		scatter.AddSeries(series.Name, items)
+		scatter.AddSeries(series.Name, items, seriesOpts...)
 	}
-	return g.extractEChartsConfig(scatter)
+	if err := addMarks(&scatter.RectChart, req, values, o.Time, false); err != nil {
+		return nil, err
+	}
+	labels, err := addOverlays(&scatter.RectChart, req, values, o.Overlays, o.Time, false)
+	if err != nil {
+		return nil, err
+	}
+	if o.Time == nil {
+		scatter.SetXAxis(labels)
+	}
+	return scatter, nil
 }
 
 func (g *ChartGenerator) generateEffectScatterChart(req ChartRequest) (map[string]interface{}, error) {
+	var o ScatterOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
 	scatter := charts.NewEffectScatter()
 	scatter.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
 		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
 	)
+	scatter.SetGlobalOptions(o.globalOpts()...)
+	_, valueY := valueAxis(o.YAxis)
+	scatter.SetGlobalOptions(charts.WithYAxisOpts(valueY))
+
+	scatter.SetXAxis(req.XAxisData)
+	for _, series := range req.Series {
+		items := make([]opts.EffectScatterData, len(series.Data))
+		for i, v := range series.Data {
+			items[i] = opts.EffectScatterData{Value: v}
+		}
+		scatter.AddSeries(series.Name, items, o.seriesOpts()...)
+	}
 	return g.extractEChartsConfig(scatter)
 }
 
+// generateScatter3DChart plots the points given by the first three series.
 func (g *ChartGenerator) generateScatter3DChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CommonOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	points, err := points3D(req)
+	if err != nil {
+		return nil, err
+	}
 	scatter3d := charts.NewScatter3D()
 	scatter3d.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
 	)
+	scatter3d.SetGlobalOptions(o.globalOpts()...)
+	scatter3d.SetGlobalOptions(chart3DAxes(req)...)
+	scatter3d.AddSeries(req.Series[2].Name, chart3DData(req, points))
 	return g.extractEChartsConfig(scatter3d)
 }
 
+func (g *ChartGenerator) generatePolarBarChart(req ChartRequest) (map[string]interface{}, error) {
+	var o PolarBarOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	bar := charts.NewBar()
+	bar.EnablePolarType()
+	bar.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
+	)
+	bar.SetGlobalOptions(o.globalOpts()...)
+
+	category := opts.PolarAxisBase{Type: "category", Data: req.XAxisData}
+	value := opts.PolarAxisBase{Type: "value"}
+	if o.CategoryAxis == "radius" {
+		bar.SetGlobalOptions(
+			charts.WithRadiusAxisOps(opts.RadiusAxis{PolarAxisBase: category}),
+			charts.WithAngleAxisOps(opts.AngleAxis{PolarAxisBase: value}),
+		)
+	} else {
+		bar.SetGlobalOptions(
+			charts.WithAngleAxisOps(opts.AngleAxis{PolarAxisBase: category}),
+			charts.WithRadiusAxisOps(opts.RadiusAxis{PolarAxisBase: value}),
+		)
+	}
+	bar.SetGlobalOptions(charts.WithPolarOps(opts.Polar{Radius: []string{"10%", "75%"}}))
+
+	barOpts := opts.BarChart{CoordSystem: "polar", RoundCap: opts.Bool(o.RoundCap)}
+	if o.Stack {
+		barOpts.Stack = "total"
+	}
+	for _, series := range req.Series {
+		items := make([]opts.BarData, len(series.Data))
+		for i, v := range series.Data {
+			items[i] = opts.BarData{Value: v}
+		}
+		bar.AddSeries(series.Name, items, charts.WithBarChartOpts(barOpts))
+	}
+	return g.extractEChartsConfig(bar)
+}
+
+// generateComboChart overlaps bar, line and scatter series on one category
+// axis, each on the left or right value axis.
+func (g *ChartGenerator) generateComboChart(req ChartRequest) (map[string]interface{}, error) {
+	var o ComboOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	if len(o.Series) > len(req.Series) {
+		return nil, &OptionsError{ChartType: req.Type, Fields: []FieldError{{
+			Field:   "options.series",
+			Message: fmt.Sprintf("has %d entries but the chart has %d series", len(o.Series), len(req.Series)),
+		}}}
+	}
+
+	combo := charts.NewBar()
+	combo.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
+	)
+	combo.SetGlobalOptions(o.globalOpts()...)
+
+	category := opts.XAxis{Type: "category"}
+	if o.CategoryAxis != nil {
+		category.Name = o.CategoryAxis.Name
+	}
+	_, left := valueAxis(o.valueAxis(0))
+	combo.SetGlobalOptions(charts.WithXAxisOpts(category), charts.WithYAxisOpts(left))
+
+	rightAxis := len(o.ValueAxes) > 1
+	for _, s := range o.Series {
+		rightAxis = rightAxis || s.Axis == 1
+	}
+	if rightAxis {
+		_, right := valueAxis(o.valueAxis(1))
+		right.Position = "right"
+		right.SplitLine = &opts.SplitLine{Show: opts.Bool(false)}
+		combo.ExtendYAxis(right)
+	}
+
+	combo.SetXAxis(req.XAxisData)
+	parts := make([]charts.Overlaper, len(req.Series))
+	for i, series := range req.Series {
+		var so ComboSeriesOptions
+		if i < len(o.Series) {
+			so = o.Series[i]
+		}
+		parts[i] = comboSeries(series, so, charts.WithLabelOpts(labelOpts(o.Labels)))
+	}
+	combo.Overlap(parts...)
+	return g.extractEChartsConfig(combo)
+}
+
+// comboSeries builds a single-series chart to overlap onto a combo chart.
+func comboSeries(series SeriesData, o ComboSeriesOptions, label charts.SeriesOpts) charts.Overlaper {
+	switch o.Type {
+	case "line", "area":
+		items := make([]opts.LineData, len(series.Data))
+		for i, v := range series.Data {
+			items[i] = opts.LineData{Value: v}
+		}
+		seriesOpts := []charts.SeriesOpts{label, charts.WithLineChartOpts(opts.LineChart{
+			YAxisIndex: o.Axis,
+			Smooth:     opts.Bool(o.Smooth),
+			Stack:      o.Stack,
+		})}
+		if o.Type == "area" {
+			seriesOpts = append(seriesOpts, charts.WithAreaStyleOpts(opts.AreaStyle{}))
+		}
+		line := charts.NewLine()
+		line.AddSeries(series.Name, items, seriesOpts...)
+		return line
+	case "scatter":
+		items := make([]opts.ScatterData, len(series.Data))
+		for i, v := range series.Data {
+			items[i] = opts.ScatterData{Value: v}
+		}
+		scatter := charts.NewScatter()
+		scatter.AddSeries(series.Name, items, label, charts.WithScatterChartOpts(opts.ScatterChart{YAxisIndex: o.Axis}))
+		return scatter
+	default:
+		items := make([]opts.BarData, len(series.Data))
+		for i, v := range series.Data {
+			items[i] = opts.BarData{Value: v}
+		}
+		bar := charts.NewBar()
+		bar.AddSeries(series.Name, items, label, charts.WithBarChartOpts(opts.BarChart{YAxisIndex: o.Axis, Stack: o.Stack}))
+		return bar
+	}
+}
+
+// generateBubbleChart plots the first three series as x, y and bubble size,
+// with XAxisData naming the points. Bubble areas scale with size.
+func (g *ChartGenerator) generateBubbleChart(req ChartRequest) (map[string]interface{}, error) {
+	var o BubbleOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	if len(req.Series) < 3 {
+		return nil, inputError(req, "series", "needs x, y and size series, got %d", len(req.Series))
+	}
+	xs, ys, sizes := req.Series[0].Data, req.Series[1].Data, req.Series[2].Data
+	n := len(xs)
+	if len(ys) < n {
+		n = len(ys)
+	}
+	if len(sizes) < n {
+		n = len(sizes)
+	}
+
+	scatter := charts.NewScatter()
+	scatter.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Formatter: "{b}: {c}"}),
+	)
+	scatter.SetGlobalOptions(o.globalOpts()...)
+	valueX, _ := valueAxis(o.XAxis)
+	_, valueY := valueAxis(o.YAxis)
+	scatter.SetGlobalOptions(charts.WithXAxisOpts(valueX), charts.WithYAxisOpts(valueY))
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, s := range sizes[:n] {
+		lo, hi = math.Min(lo, s), math.Max(hi, s)
+	}
+	minSize, maxSize := o.sizes()
+	items := make([]opts.ScatterData, n)
+	for i := 0; i < n; i++ {
+		diameter := (minSize + maxSize) / 2
+		if hi > lo {
+			diameter = minSize + (maxSize-minSize)*math.Sqrt((sizes[i]-lo)/(hi-lo))
+		}
+		items[i] = opts.ScatterData{
+			Value:      []float64{xs[i], ys[i], sizes[i]},
+			SymbolSize: int(math.Round(diameter)),
+		}
+		if i < len(req.XAxisData) {
+			items[i].Name = req.XAxisData[i]
+		}
+	}
+	scatter.AddSeries(req.Series[2].Name, items, charts.WithLabelOpts(labelOpts(o.Labels)))
+	return g.extractEChartsConfig(scatter)
+}
+
 // STATISTICAL CHARTS
+
+// generateHeatmapChart shades a grid with one column per XAxisData label
+// and one row per series.
 func (g *ChartGenerator) generateHeatmapChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CommonOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
 	heatmap := charts.NewHeatMap()
 	heatmap.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
 		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
 	)
+	heatmap.SetGlobalOptions(o.globalOpts()...)
+	heatmap.SetGlobalOptions(
+		charts.WithXAxisOpts(opts.XAxis{Type: "category", Data: req.XAxisData}),
+		charts.WithYAxisOpts(opts.YAxis{Type: "category", Data: seriesNames(req.Series), AxisLabel: &opts.AxisLabel{Show: opts.Bool(true)}}),
+	)
+
+	var items []opts.HeatMapData
+	var values []float64
+	for j, series := range req.Series {
+		for i, v := range series.Data {
+			if i >= len(req.XAxisData) {
+				break
+			}
+			items = append(items, opts.HeatMapData{Value: [3]interface{}{i, j, v}})
+			values = append(values, v)
+		}
+	}
+	heatmap.AddSeries("values", items)
+	if len(values) > 0 {
+		heatmap.SetGlobalOptions(charts.WithVisualMapOpts(valueVisualMap(values, nil)))
+	}
 	return g.extractEChartsConfig(heatmap)
 }
 
+// generateBoxPlotChart draws one box per series, from the minimum through
+// the quartiles to the maximum of its values.
 func (g *ChartGenerator) generateBoxPlotChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CommonOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
 	boxplot := charts.NewBoxPlot()
 	boxplot.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
 		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
 	)
+	boxplot.SetGlobalOptions(o.globalOpts()...)
+	boxplot.SetXAxis(seriesNames(req.Series))
+
+	boxes := make([]opts.BoxPlotData, len(req.Series))
+	for i, series := range req.Series {
+		boxes[i].Name = series.Name
+		if len(series.Data) == 0 {
+			continue
+		}
+		sorted := sortedCopy(series.Data)
+		boxes[i].Value = []float64{
+			sorted[0], quantile(sorted, 0.25), quantile(sorted, 0.5), quantile(sorted, 0.75), sorted[len(sorted)-1],
+		}
+	}
+	boxplot.AddSeries("summary", boxes)
 	return g.extractEChartsConfig(boxplot)
 }
 
+// generateCandlestickChart reads XAxisData as trading periods and the
+// first four series as their open, close, low and high values.
 func (g *ChartGenerator) generateCandlestickChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CommonOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	if len(req.Series) < 4 {
+		return nil, inputError(req, "series", "needs open, close, low and high series, got %d", len(req.Series))
+	}
 	kline := charts.NewKLine()
 	kline.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
-		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
 	)
+	kline.SetGlobalOptions(o.globalOpts()...)
+
+	n := len(req.XAxisData)
+	for _, series := range req.Series[:4] {
+		if len(series.Data) < n {
+			n = len(series.Data)
+		}
+	}
+	kline.SetGlobalOptions(charts.WithYAxisOpts(opts.YAxis{Type: "value", Scale: opts.Bool(true), AxisLabel: &opts.AxisLabel{Show: opts.Bool(true)}}))
+	kline.SetXAxis(req.XAxisData[:n])
+	items := make([]opts.KlineData, n)
+	for i := range items {
+		items[i] = opts.KlineData{Value: []float64{
+			req.Series[0].Data[i], req.Series[1].Data[i], req.Series[2].Data[i], req.Series[3].Data[i],
+		}}
+	}
+	kline.AddSeries("candlestick", items)
 	return g.extractEChartsConfig(kline)
 }
 
+// generateHistogramChart bins every series over shared bins, so several
+// series can be compared side by side.
+func (g *ChartGenerator) generateHistogramChart(req ChartRequest) (map[string]interface{}, error) {
+	var o HistogramOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	var all []float64
+	for _, series := range req.Series {
+		all = append(all, series.Data...)
+	}
+	sorted := sortedCopy(all)
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
+	)
+	bar.SetGlobalOptions(o.globalOpts()...)
+	bar.SetGlobalOptions(
+		charts.WithXAxisOpts(opts.XAxis{Type: "category"}),
+		charts.WithYAxisOpts(opts.YAxis{Type: "value", Name: "Count", AxisLabel: &opts.AxisLabel{Show: opts.Bool(true)}}),
+	)
+	if len(sorted) == 0 {
+		return g.extractEChartsConfig(bar)
+	}
+
+	k := binCount(sorted, o.Method)
+	if o.Bins != nil {
+		k = *o.Bins
+	}
+	edges := histogramBins(sorted[0], sorted[len(sorted)-1], k)
+	bar.SetXAxis(binLabels(edges))
+
+	barOpts := opts.BarChart{}
+	if len(req.Series) == 1 {
+		barOpts.BarCategoryGap = "0%"
+	}
+	for _, series := range req.Series {
+		bins := append([]histogramBin(nil), edges...)
+		fillBins(bins, series.Data)
+		items := make([]opts.BarData, len(bins))
+		for i, b := range bins {
+			items[i] = opts.BarData{Value: b.Count}
+		}
+		bar.AddSeries(series.Name, items,
+			charts.WithBarChartOpts(barOpts),
+			charts.WithLabelOpts(labelOpts(o.Labels)),
+		)
+	}
+	return g.extractEChartsConfig(bar)
+}
+
+// binLabels formats bins as "lo–hi" with enough decimals to tell
+// neighbouring edges apart.
+func binLabels(bins []histogramBin) []string {
+	decimals := 0
+	if width := bins[0].Hi - bins[0].Lo; width > 0 {
+		decimals = int(math.Max(0, math.Min(6, math.Ceil(-math.Log10(width))+1)))
+	}
+	labels := make([]string, len(bins))
+	for i, b := range bins {
+		labels[i] = fmt.Sprintf("%.*f–%.*f", decimals, b.Lo, decimals, b.Hi)
+	}
+	return labels
+}
+
+// generateCalendarHeatmapChart reads XAxisData as dates and the first
+// series as their values, summing values that fall on the same day. Each
+// year gets its own calendar.
+func (g *ChartGenerator) generateCalendarHeatmapChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CalendarOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]float64)
+	var days []string
+	if len(req.Series) > 0 {
+		values := req.Series[0].Data
+		for i, s := range req.XAxisData {
+			if i >= len(values) {
+				break
+			}
+			t, ok := ParseDate(s)
+			if !ok {
+				return nil, inputError(req, fmt.Sprintf("xAxisData[%d]", i), "%q is not a date", s)
+			}
+			day := t.Format("2006-01-02")
+			if _, seen := totals[day]; !seen {
+				days = append(days, day)
+			}
+			totals[day] += values[i]
+		}
+	}
+	sort.Strings(days)
+
+	heatmap := charts.NewHeatMap()
+	heatmap.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
+	)
+	heatmap.SetGlobalOptions(o.globalOpts()...)
+
+	var years []string
+	byYear := make(map[string][]opts.HeatMapData)
+	values := make([]float64, 0, len(days))
+	for _, day := range days {
+		year := day[:4]
+		if _, ok := byYear[year]; !ok {
+			years = append(years, year)
+		}
+		byYear[year] = append(byYear[year], opts.HeatMapData{Value: []interface{}{day, totals[day]}})
+		values = append(values, totals[day])
+	}
+
+	for i, year := range years {
+		calendar := &opts.Calendar{Range: []string{year}, Orient: o.Orient, CellSize: "auto"}
+		if o.Orient == "vertical" {
+			calendar.Top, calendar.Bottom = "60", "60"
+			calendar.Left = fmt.Sprintf("%d", 60+i*220)
+			calendar.Width = "180"
+		} else {
+			calendar.Left, calendar.Right = "40", "40"
+			calendar.Top = fmt.Sprintf("%d", 60+i*170)
+			calendar.Height = "120"
+		}
+		heatmap.AddCalendar(calendar)
+		heatmap.AddSeries(year, byYear[year],
+			charts.WithCoordinateSystem("calendar"),
+			charts.WithCalendarIndex(i),
+		)
+	}
+
+	if len(values) > 0 {
+		visualMap := valueVisualMap(values, o.VisualMapColors)
+		visualMap.Orient, visualMap.Left, visualMap.Top, visualMap.Bottom = "horizontal", "center", "", "0"
+		heatmap.SetGlobalOptions(charts.WithVisualMapOpts(visualMap))
+	}
+	return g.extractEChartsConfig(heatmap)
+}
+
+// violinPoints is the number of density samples drawn per violin.
+const violinPoints = 64
+
+// violinWidth is the share of its category band the widest point of a
+// violin fills.
+const violinWidth = 0.9
+
+// generateViolinChart draws one horizontal violin per series from a kernel
+// density estimate, with a box plot of the same values inside it. Each
+// violin is a pair of stacked lines on a hidden value axis aligned with the
+// category axis: an invisible lower edge and the filled width above it, so
+// that the config needs no JavaScript.
+func (g *ChartGenerator) generateViolinChart(req ChartRequest) (map[string]interface{}, error) {
+	var o ViolinOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	var bandwidth float64
+	if o.Bandwidth != nil {
+		bandwidth = *o.Bandwidth
+	}
+
+	names := seriesNames(req.Series)
+	valueX, _ := valueAxis(o.ValueAxis)
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
+	)
+	line.SetGlobalOptions(o.globalOpts()...)
+	line.SetGlobalOptions(
+		charts.WithXAxisOpts(valueX),
+		charts.WithYAxisOpts(opts.YAxis{Type: "category", Data: names, AxisLabel: &opts.AxisLabel{Show: opts.Bool(true)}}),
+	)
+	// Category i is centered at i+0.5 on this axis, which keeps the lower
+	// edges positive: ECharts only stacks values of the same sign.
+	line.ExtendYAxis(opts.YAxis{Type: "value", Show: opts.Bool(false), Min: 0, Max: len(names)})
+
+	// Boxes are indexed by category, so empty series keep an empty box.
+	boxes := make([]opts.BoxPlotData, len(req.Series))
+	for i, series := range req.Series {
+		boxes[i].Name = series.Name
+		if len(series.Data) == 0 {
+			continue
+		}
+		sorted := sortedCopy(series.Data)
+		boxes[i].Value = []float64{
+			sorted[0], quantile(sorted, 0.25), quantile(sorted, 0.5), quantile(sorted, 0.75), sorted[len(sorted)-1],
+		}
+
+		xs, density := kde(sorted, violinPoints, bandwidth)
+		var peak float64
+		for _, d := range density {
+			peak = math.Max(peak, d)
+		}
+		lower := make([]opts.LineData, len(xs))
+		width := make([]opts.LineData, len(xs))
+		for j, x := range xs {
+			half := density[j] / peak * violinWidth / 2
+			lower[j] = opts.LineData{Value: []float64{x, float64(i) + 0.5 - half}}
+			width[j] = opts.LineData{Value: []float64{x, 2 * half}}
+		}
+		stack := fmt.Sprintf("violin%d", i)
+		chartOpts := opts.LineChart{YAxisIndex: 1, Stack: stack, Symbol: "none", Smooth: opts.Bool(true)}
+		line.AddSeries(series.Name, lower,
+			charts.WithLineChartOpts(chartOpts),
+			charts.WithLineStyleOpts(opts.LineStyle{Opacity: opts.Float(0)}),
+		)
+		line.AddSeries(series.Name, width,
+			charts.WithLineChartOpts(chartOpts),
+			charts.WithLineStyleOpts(opts.LineStyle{Width: 1}),
+			charts.WithAreaStyleOpts(opts.AreaStyle{Opacity: opts.Float(0.6)}),
+		)
+	}
+
+	boxplot := charts.NewBoxPlot()
+	boxplot.AddSeries("summary", boxes)
+	line.Overlap(boxplot)
+	return g.extractEChartsConfig(line)
+}
+
 // SPECIALIZED CHARTS
+
+// generateRadarChart draws one polygon per series over one axis per
+// XAxisData label.
 func (g *ChartGenerator) generateRadarChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CommonOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
 	radar := charts.NewRadar()
 	radar.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
 		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
 	)
+	radar.SetGlobalOptions(o.globalOpts()...)
+
+	indicators := make([]*opts.Indicator, len(req.XAxisData))
+	for i, name := range req.XAxisData {
+		indicators[i] = &opts.Indicator{Name: name}
+	}
+	radar.SetGlobalOptions(charts.WithRadarComponentOpts(opts.RadarComponent{Indicator: indicators}))
+	for _, series := range req.Series {
+		values := series.Data
+		if len(values) > len(indicators) {
+			values = values[:len(indicators)]
+		}
+		radar.AddSeries(series.Name, []opts.RadarData{{Name: series.Name, Value: values}})
+	}
 	return g.extractEChartsConfig(radar)
 }
 
 func (g *ChartGenerator) generateFunnelChart(req ChartRequest) (map[string]interface{}, error) {
+	var o FunnelOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
 	funnel := charts.NewFunnel()
 	funnel.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
 		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
 	)
+	funnel.SetGlobalOptions(o.globalOpts()...)
 	if len(req.Series) > 0 {
 		items := make([]opts.FunnelData, len(req.Series[0].Data))
 		for i, v := range req.Series[0].Data {
@@ -383,148 +1122,926 @@ func (g *ChartGenerator) generateFunnelChart(req ChartRequest) (map[string]inter
 			}
 			items[i] = opts.FunnelData{Name: name, Value: v}
 		}
-		funnel.AddSeries("funnel", items)
+		funnel.AddSeries("funnel", items,
+			charts.WithLabelOpts(labelOpts(o.Labels)),
+			charts.WithSeriesOpts(func(s *charts.SingleSeries) { s.Sort = o.Sort }),
+		)
 	}
 	return g.extractEChartsConfig(funnel)
 }
 
 func (g *ChartGenerator) generateGaugeChart(req ChartRequest) (map[string]interface{}, error) {
+	var o GaugeOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
 	gauge := charts.NewGauge()
 	gauge.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
 		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
 	)
+	gauge.SetGlobalOptions(o.globalOpts()...)
 	if len(req.Series) > 0 && len(req.Series[0].Data) > 0 {
 		items := []opts.GaugeData{{Value: req.Series[0].Data[0]}}
-		gauge.AddSeries("gauge", items)
+		gauge.AddSeries("gauge", items, charts.WithSeriesOpts(func(s *charts.SingleSeries) {
+			if o.Min != nil {
+				s.Min = *o.Min
+			}
+			if o.Max != nil {
+				s.Max = *o.Max
+			}
+		}))
 	}
 	return g.extractEChartsConfig(gauge)
 }
 
+// generateWordCloudChart sizes each XAxisData word by its value in the
+// first series.
 func (g *ChartGenerator) generateWordCloudChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CommonOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
 	wc := charts.NewWordCloud()
 	wc.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
 	)
+	wc.SetGlobalOptions(o.globalOpts()...)
+	if len(req.Series) > 0 {
+		var items []opts.WordCloudData
+		for i, v := range req.Series[0].Data {
+			if i >= len(req.XAxisData) {
+				break
+			}
+			items = append(items, opts.WordCloudData{Name: req.XAxisData[i], Value: v})
+		}
+		wc.AddSeries(req.Series[0].Name, items)
+	}
 	return g.extractEChartsConfig(wc)
 }
 
+// generateLiquidChart fills a bubble to the first value of the first
+// series, a ratio between 0 and 1.
 func (g *ChartGenerator) generateLiquidChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CommonOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
 	liquid := charts.NewLiquid()
 	liquid.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
 	)
+	liquid.SetGlobalOptions(o.globalOpts()...)
+	if len(req.Series) > 0 && len(req.Series[0].Data) > 0 {
+		liquid.AddSeries("liquid", []opts.LiquidData{{Value: req.Series[0].Data[0]}})
+	}
 	return g.extractEChartsConfig(liquid)
 }
 
+// generateThemeRiverChart reads XAxisData as dates and draws one stream per
+// series.
 func (g *ChartGenerator) generateThemeRiverChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CommonOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	dates := make([]string, len(req.XAxisData))
+	for i, s := range req.XAxisData {
+		t, ok := ParseDate(s)
+		if !ok {
+			return nil, inputError(req, fmt.Sprintf("xAxisData[%d]", i), "%q is not a date", s)
+		}
+		dates[i] = t.Format("2006-01-02")
+	}
+
 	tr := charts.NewThemeRiver()
 	tr.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
+		charts.WithSingleAxisOpts(opts.SingleAxis{Type: "time", Bottom: "10%"}),
 	)
+	tr.SetGlobalOptions(o.globalOpts()...)
+	var items []opts.ThemeRiverData
+	for _, series := range req.Series {
+		for i, v := range series.Data {
+			if i >= len(dates) {
+				break
+			}
+			items = append(items, opts.ThemeRiverData{Date: dates[i], Value: v, Name: series.Name})
+		}
+	}
+	tr.AddSeries("themeriver", items)
 	return g.extractEChartsConfig(tr)
 }
 
+// ganttSpan is a task of a Gantt chart.
+type ganttSpan struct {
+	start, end time.Time
+}
+
+// ganttLanes lays out the tasks of a group, by row, as lanes of layers:
+// lanes[l][k][row] is the k-th task of row in lane l, nil when there is
+// none. A row's tasks go in the first lane whose last task ends before they
+// start.
+func ganttLanes(rows map[int][]ganttSpan, n int) [][][]*ganttSpan {
+	var lanes [][][]*ganttSpan
+	for row, spans := range rows {
+		sort.Slice(spans, func(a, b int) bool { return spans[a].start.Before(spans[b].start) })
+		var ends []time.Time
+		var depth []int
+		for i := range spans {
+			span := &spans[i]
+			lane := 0
+			for lane < len(ends) && ends[lane].After(span.start) {
+				lane++
+			}
+			if lane == len(ends) {
+				ends = append(ends, time.Time{})
+				depth = append(depth, 0)
+			}
+			ends[lane] = span.end
+			if lane == len(lanes) {
+				lanes = append(lanes, nil)
+			}
+			if depth[lane] == len(lanes[lane]) {
+				lanes[lane] = append(lanes[lane], make([]*ganttSpan, n))
+			}
+			lanes[lane][depth[lane]][row] = span
+			depth[lane]++
+		}
+	}
+	return lanes
+}
+
+// generateGanttChart draws req.Tasks on a time axis, one row per task name
+// and one series name per group. Bars are stacked on an invisible bar
+// reaching from the previous task of their row, so that the config needs
+// no JavaScript; tasks of a row and group that overlap go in separate
+// stacks drawn over each other.
+func (g *ChartGenerator) generateGanttChart(req ChartRequest) (map[string]interface{}, error) {
+	var o GanttOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	barHeight := 0.6
+	if o.BarHeight != nil {
+		barHeight = *o.BarHeight
+	}
+
+	var names, groups []string
+	rows := make(map[string]int)
+	byGroup := make(map[string]map[int][]ganttSpan)
+	var first, last time.Time
+	for i, task := range req.Tasks {
+		start, ok := ParseDate(task.Start)
+		if !ok {
+			return nil, inputError(req, fmt.Sprintf("tasks[%d].start", i), "%q is not a date", task.Start)
+		}
+		end, ok := ParseDate(task.End)
+		if !ok {
+			return nil, inputError(req, fmt.Sprintf("tasks[%d].end", i), "%q is not a date", task.End)
+		}
+		if end.Before(start) {
+			return nil, inputError(req, fmt.Sprintf("tasks[%d].end", i), "is before the start of task %q", task.Name)
+		}
+		if first.IsZero() || start.Before(first) {
+			first = start
+		}
+		if end.After(last) {
+			last = end
+		}
+
+		row, ok := rows[task.Name]
+		if !ok {
+			row = len(names)
+			rows[task.Name] = row
+			names = append(names, task.Name)
+		}
+		group := task.Group
+		if group == "" {
+			group = "Tasks"
+		}
+		if _, ok := byGroup[group]; !ok {
+			groups = append(groups, group)
+			byGroup[group] = make(map[int][]ganttSpan)
+		}
+		byGroup[group][row] = append(byGroup[group][row], ganttSpan{start: start, end: end})
+	}
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
+	)
+	bar.SetGlobalOptions(o.globalOpts()...)
+	xAxis := opts.XAxis{Type: "time"}
+	if len(req.Tasks) > 0 {
+		xAxis.Min, xAxis.Max = first.UnixMilli(), last.UnixMilli()
+	}
+	bar.SetGlobalOptions(
+		charts.WithXAxisOpts(xAxis),
+		charts.WithYAxisOpts(opts.YAxis{
+			Type:      "category",
+			Data:      names,
+			Inverse:   opts.Bool(true),
+			AxisLabel: &opts.AxisLabel{Show: opts.Bool(true)},
+		}),
+	)
+
+	chartOpts := opts.BarChart{BarGap: "-100%", BarWidth: fmt.Sprintf("%g%%", barHeight*100)}
+	hidden := &opts.ItemStyle{Color: "transparent"}
+	for gi, group := range groups {
+		for lane, layers := range ganttLanes(byGroup[group], len(names)) {
+			chartOpts.Stack = fmt.Sprintf("gantt%d_%d", gi, lane)
+			for k, layer := range layers {
+				gaps := make([]opts.BarData, len(names))
+				tasks := make([]opts.BarData, len(names))
+				for row, span := range layer {
+					if span == nil {
+						gaps[row], tasks[row] = opts.BarData{Value: "-"}, opts.BarData{Value: "-"}
+						continue
+					}
+					offset := span.start.UnixMilli()
+					if k > 0 {
+						offset -= layers[k-1][row].end.UnixMilli()
+					}
+					gaps[row] = opts.BarData{
+						Value:     offset,
+						ItemStyle: hidden,
+						Tooltip:   &opts.Tooltip{Show: opts.Bool(false)},
+					}
+					tasks[row] = opts.BarData{
+						Name:  names[row],
+						Value: span.end.UnixMilli() - span.start.UnixMilli(),
+						Tooltip: &opts.Tooltip{Formatter: types.FuncStr(fmt.Sprintf("{a}<br/>{b}: %s → %s",
+							span.start.UTC().Format("2006-01-02"), span.end.UTC().Format("2006-01-02")))},
+					}
+				}
+				bar.AddSeries(group, gaps, charts.WithBarChartOpts(chartOpts))
+				bar.AddSeries(group, tasks, charts.WithBarChartOpts(chartOpts))
+			}
+		}
+	}
+	return g.extractEChartsConfig(bar)
+}
+
 // RELATIONSHIP CHARTS
+
+// linkNodes returns the node names of req.Links in the order they first
+// appear, requiring both ends of every link.
+func linkNodes(req ChartRequest) ([]string, error) {
+	var names []string
+	seen := make(map[string]bool)
+	for i, l := range req.Links {
+		if l.Source == "" || l.Target == "" {
+			return nil, inputError(req, fmt.Sprintf("links[%d]", i), "needs a source and a target")
+		}
+		for _, name := range []string{l.Source, l.Target} {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// generateGraphChart draws the nodes named by req.Links with a force
+// layout, one edge per link. Nodes are sized by the total weight of their
+// links.
 func (g *ChartGenerator) generateGraphChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CommonOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	names, err := linkNodes(req)
+	if err != nil {
+		return nil, err
+	}
+	weights := make(map[string]float64)
+	links := make([]opts.GraphLink, len(req.Links))
+	for i, l := range req.Links {
+		weights[l.Source] += l.Value
+		weights[l.Target] += l.Value
+		links[i] = opts.GraphLink{Source: l.Source, Target: l.Target, Value: float32(l.Value)}
+	}
+	nodes := make([]opts.GraphNode, len(names))
+	for i, name := range names {
+		nodes[i] = opts.GraphNode{Name: name, Value: float32(weights[name])}
+	}
+
 	graph := charts.NewGraph()
 	graph.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
+	)
+	graph.SetGlobalOptions(o.globalOpts()...)
+	graph.AddSeries("graph", nodes, links,
+		charts.WithGraphChartOpts(opts.GraphChart{
+			Layout:    "force",
+			Force:     &opts.GraphForce{Repulsion: 200, EdgeLength: 80},
+			Roam:      opts.Bool(true),
+			Draggable: opts.Bool(true),
+		}),
+		charts.WithLabelOpts(opts.Label{Show: opts.Bool(true), Position: "right"}),
 	)
 	return g.extractEChartsConfig(graph)
 }
 
+// generateSankeyChart draws req.Links as flows between their nodes. ECharts
+// cannot lay out a sankey with a cycle, so cycles are refused.
 func (g *ChartGenerator) generateSankeyChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CommonOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	names, err := linkNodes(req)
+	if err != nil {
+		return nil, err
+	}
+	next := make(map[string][]string)
+	links := make([]opts.SankeyLink, len(req.Links))
+	for i, l := range req.Links {
+		if l.Value <= 0 {
+			return nil, inputError(req, fmt.Sprintf("links[%d].value", i), "must be positive")
+		}
+		next[l.Source] = append(next[l.Source], l.Target)
+		links[i] = opts.SankeyLink{Source: l.Source, Target: l.Target, Value: float32(l.Value)}
+	}
+	if name, ok := findCycle(names, next); ok {
+		return nil, inputError(req, "links", "must not flow in a cycle, as they do through %q", name)
+	}
+	nodes := make([]opts.SankeyNode, len(names))
+	for i, name := range names {
+		nodes[i] = opts.SankeyNode{Name: name}
+	}
+
 	sankey := charts.NewSankey()
 	sankey.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
 	)
+	sankey.SetGlobalOptions(o.globalOpts()...)
+	sankey.AddSeries("sankey", nodes, links, charts.WithLabelOpts(opts.Label{Show: opts.Bool(true)}))
 	return g.extractEChartsConfig(sankey)
 }
 
+// findCycle returns a node on a cycle of the graph whose edges are next.
+func findCycle(names []string, next map[string][]string) (string, bool) {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var visit func(name string) (string, bool)
+	visit = func(name string) (string, bool) {
+		switch state[name] {
+		case visiting:
+			return name, true
+		case done:
+			return "", false
+		}
+		state[name] = visiting
+		for _, n := range next[name] {
+			if found, ok := visit(n); ok {
+				return found, true
+			}
+		}
+		state[name] = done
+		return "", false
+	}
+	for _, name := range names {
+		if found, ok := visit(name); ok {
+			return found, true
+		}
+	}
+	return "", false
+}
+
+// generateTreeChart draws the parent/child rows of req.Links as a tree
+// growing left to right. Several roots are gathered under one named after
+// the chart title.
 func (g *ChartGenerator) generateTreeChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CommonOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	var names []string
+	seen := make(map[string]bool)
+	parents := make(map[string]string)
+	values := make(map[string]float64)
+	children := make(map[string][]string)
+	for i, l := range req.Links {
+		if l.Target == "" {
+			return nil, inputError(req, fmt.Sprintf("links[%d].target", i), "is required")
+		}
+		if _, ok := parents[l.Target]; ok {
+			return nil, inputError(req, fmt.Sprintf("links[%d].target", i), "%q already has a parent", l.Target)
+		}
+		if l.Source == l.Target {
+			return nil, inputError(req, fmt.Sprintf("links[%d]", i), "cannot make %q its own parent", l.Target)
+		}
+		for _, name := range []string{l.Source, l.Target} {
+			if name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		parents[l.Target] = l.Source
+		values[l.Target] = l.Value
+		if l.Source != "" {
+			children[l.Source] = append(children[l.Source], l.Target)
+		}
+	}
+
+	visited := 0
+	var build func(name string) *opts.TreeData
+	build = func(name string) *opts.TreeData {
+		visited++
+		node := &opts.TreeData{Name: name}
+		if v := values[name]; v != 0 {
+			node.Value = v
+		}
+		for _, child := range children[name] {
+			node.Children = append(node.Children, build(child))
+		}
+		return node
+	}
+	var roots []*opts.TreeData
+	for _, name := range names {
+		if parents[name] == "" {
+			roots = append(roots, build(name))
+		}
+	}
+	if visited < len(names) {
+		return nil, inputError(req, "links", "must not form a cycle")
+	}
+	root := &opts.TreeData{Name: req.Title, Children: roots}
+	if len(roots) == 1 {
+		root = roots[0]
+	}
+
 	tree := charts.NewTree()
 	tree.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
 	)
+	tree.SetGlobalOptions(o.globalOpts()...)
+	var data []opts.TreeData
+	if len(names) > 0 {
+		data = []opts.TreeData{*root}
+	}
+	tree.AddSeries("tree", data, charts.WithTreeOpts(opts.TreeChart{
+		Layout:           "orthogonal",
+		Orient:           "LR",
+		Roam:             opts.Bool(true),
+		InitialTreeDepth: -1,
+	}))
 	return g.extractEChartsConfig(tree)
 }
 
+// generateTreemapChart draws one rectangle per XAxisData label, sized by
+// its value in the first series.
 func (g *ChartGenerator) generateTreemapChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CommonOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
 	treemap := charts.NewTreeMap()
 	treemap.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
 	)
+	treemap.SetGlobalOptions(o.globalOpts()...)
+	if len(req.Series) > 0 {
+		var nodes []opts.TreeMapNode
+		for i, v := range req.Series[0].Data {
+			if i >= len(req.XAxisData) {
+				break
+			}
+			nodes = append(nodes, opts.TreeMapNode{Name: req.XAxisData[i], Value: v})
+		}
+		treemap.AddSeries(req.Series[0].Name, nodes)
+	}
 	return g.extractEChartsConfig(treemap)
 }
 
+// generateSunburstChart draws one ring segment per XAxisData label, sized
+// by its value in the first series.
 func (g *ChartGenerator) generateSunburstChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CommonOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
 	sunburst := charts.NewSunburst()
 	sunburst.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
 	)
+	sunburst.SetGlobalOptions(o.globalOpts()...)
+	if len(req.Series) > 0 {
+		var items []opts.SunBurstData
+		for i, v := range req.Series[0].Data {
+			if i >= len(req.XAxisData) {
+				break
+			}
+			items = append(items, opts.SunBurstData{Name: req.XAxisData[i], Value: v})
+		}
+		sunburst.AddSeries(req.Series[0].Name, items)
+	}
 	return g.extractEChartsConfig(sunburst)
 }
 
 // MULTI-DIMENSIONAL
+
+// generateParallelChart draws one axis per series and one line per row
+// across them, named by XAxisData.
 func (g *ChartGenerator) generateParallelChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CommonOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
 	parallel := charts.NewParallel()
 	parallel.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
 	)
+	parallel.SetGlobalOptions(o.globalOpts()...)
+	if len(req.Series) == 0 {
+		return g.extractEChartsConfig(parallel)
+	}
+
+	axes := make([]opts.ParallelAxis, len(req.Series))
+	n := len(req.Series[0].Data)
+	for j, series := range req.Series {
+		axes[j] = opts.ParallelAxis{Dim: j, Name: series.Name, Type: "value"}
+		if len(series.Data) < n {
+			n = len(series.Data)
+		}
+	}
+	parallel.SetGlobalOptions(charts.WithParallelAxisList(axes))
+	items := make([]opts.ParallelData, n)
+	for i := range items {
+		row := make([]float64, len(req.Series))
+		for j, series := range req.Series {
+			row[j] = series.Data[i]
+		}
+		items[i].Value = row
+		if i < len(req.XAxisData) {
+			items[i].Name = req.XAxisData[i]
+		}
+	}
+	parallel.AddSeries("rows", items)
 	return g.extractEChartsConfig(parallel)
 }
 
 // GEOGRAPHIC
-func (g *ChartGenerator) generateGeoChart(req ChartRequest) (map[string]interface{}, error) {
+func (g *ChartGenerator) generateGeoChart(req ChartRequest, seriesType string) (map[string]interface{}, []string, error) {
+	var o MapOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, nil, err
+	}
+	mapType := mapTypeOrDefault(req.MapType)
 	geo := charts.NewGeo()
 	geo.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
+		charts.WithGeoComponentOpts(opts.GeoComponent{Map: mapType}),
 	)
-	return g.extractEChartsConfig(geo)
+	geo.SetGlobalOptions(o.globalOpts()...)
+
+	points, unmatched := g.geoPoints(req)
+	items := make([]opts.GeoData, 0, len(points))
+	values := make([]float64, 0, len(points))
+	for _, p := range points {
+		if p.Lat < -90 || p.Lat > 90 || p.Lng < -180 || p.Lng > 180 {
+			return nil, nil, inputError(req, "points", "point %q has invalid coordinates (%g, %g)", p.Name, p.Lat, p.Lng)
+		}
+		items = append(items, opts.GeoData{Name: p.Name, Value: []float64{p.Lng, p.Lat, p.Value}})
+		values = append(values, p.Value)
+	}
+	if len(items) > 0 {
+		geo.AddSeries("points", seriesType, items)
+		geo.SetGlobalOptions(charts.WithVisualMapOpts(valueVisualMap(values, o.VisualMapColors)))
+	}
+
+	config, err := g.extractEChartsConfig(geo)
+	return config, unmatched, err
+}
+
+// geoPoints returns the explicit points of req, or geocodes XAxisData when
+// none were given.
+func (g *ChartGenerator) geoPoints(req ChartRequest) ([]GeoPoint, []string) {
+	if len(req.Points) > 0 || g.geocoder == nil {
+		return req.Points, nil
+	}
+
+	var values []float64
+	if len(req.Series) > 0 {
+		values = req.Series[0].Data
+	}
+	locations, unmatched := g.geocoder.Resolve(req.XAxisData)
+	points := make([]GeoPoint, 0, len(locations))
+	for i, loc := range locations {
+		if !loc.Found {
+			continue
+		}
+		p := GeoPoint{Name: loc.Name, Lat: loc.Lat, Lng: loc.Lng}
+		if i < len(values) {
+			p.Value = values[i]
+		}
+		points = append(points, p)
+	}
+	return points, unmatched
 }
 
-func (g *ChartGenerator) generateMapChart(req ChartRequest) (map[string]interface{}, error) {
+func (g *ChartGenerator) generateMapChart(req ChartRequest) (map[string]interface{}, []string, error) {
+	var o MapOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, nil, err
+	}
+	mapType := mapTypeOrDefault(req.MapType)
 	m := charts.NewMap()
+	m.RegisterMapType(mapType)
 	m.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "item"}),
 	)
-	return g.extractEChartsConfig(m)
+	m.SetGlobalOptions(o.globalOpts()...)
+
+	var unmatched []string
+	seen := make(map[string]bool)
+	var values []float64
+	for _, series := range req.Series {
+		// Several spellings can resolve to the same feature ("USA", "US"),
+		// so accumulate per feature while keeping first-seen order.
+		totals := make(map[string]float64)
+		var order []string
+		for i, v := range series.Data {
+			if i >= len(req.XAxisData) {
+				break
+			}
+			name, ok := MatchRegion(mapType, req.XAxisData[i])
+			if !ok {
+				if raw := req.XAxisData[i]; !seen[raw] {
+					seen[raw] = true
+					unmatched = append(unmatched, raw)
+				}
+				continue
+			}
+			if _, exists := totals[name]; !exists {
+				order = append(order, name)
+			}
+			totals[name] += v
+		}
+
+		items := make([]opts.MapData, len(order))
+		for i, name := range order {
+			items[i] = opts.MapData{Name: name, Value: totals[name]}
+			values = append(values, totals[name])
+		}
+		m.AddSeries(series.Name, items)
+	}
+	if len(values) > 0 {
+		m.SetGlobalOptions(charts.WithVisualMapOpts(valueVisualMap(values, o.VisualMapColors)))
+	}
+
+	config, err := g.extractEChartsConfig(m)
+	return config, unmatched, err
+}
+
+func mapTypeOrDefault(mapType string) string {
+	if mapType == "" {
+		return "world"
+	}
+	return strings.ToLower(mapType)
+}
+
+// valueVisualMap builds a continuous visual map spanning values, colored
+// from the first to the last of colors.
+func valueVisualMap(values []float64, colors []string) opts.VisualMap {
+	if len(colors) == 0 {
+		colors = []string{"#e0f3f8", "#4575b4"}
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values[1:] {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	return opts.VisualMap{
+		Calculable: opts.Bool(true),
+		Min:        float32(lo),
+		Max:        float32(hi),
+		Left:       "left",
+		Top:        "bottom",
+		InRange:    &opts.VisualMapInRange{Color: colors},
+	}
+}
+
+// seriesNames returns the names of series, in order.
+func seriesNames(series []SeriesData) []string {
+	names := make([]string, len(series))
+	for i, s := range series {
+		names[i] = s.Name
+	}
+	return names
 }
 
 // 3D CHARTS
+
+// generateSurface3DChart draws a surface through the points given by the
+// first three series, which must hold one z for every pair of their
+// distinct x and y values. ECharts reads the surface row by row, so the
+// points are sorted by y, then x.
 func (g *ChartGenerator) generateSurface3DChart(req ChartRequest) (map[string]interface{}, error) {
+	var o CommonOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, err
+	}
+	points, err := points3D(req)
+	if err != nil {
+		return nil, err
+	}
+	xs, ys := make(map[float64]bool), make(map[float64]bool)
+	for _, p := range points {
+		xs[p[0]], ys[p[1]] = true, true
+	}
+	sort.Slice(points, func(a, b int) bool {
+		if points[a][1] != points[b][1] {
+			return points[a][1] < points[b][1]
+		}
+		return points[a][0] < points[b][0]
+	})
+	for i := 1; i < len(points); i++ {
+		if points[i][0] == points[i-1][0] && points[i][1] == points[i-1][1] {
+			return nil, inputError(req, "series", "has two points at x %g, y %g", points[i][0], points[i][1])
+		}
+	}
+	if len(points) != len(xs)*len(ys) {
+		return nil, inputError(req, "series", "needs a point for each of the %d × %d x and y values, got %d", len(xs), len(ys), len(points))
+	}
+
 	surface := charts.NewSurface3D()
 	surface.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{Title: req.Title}),
 	)
+	surface.SetGlobalOptions(o.globalOpts()...)
+	surface.SetGlobalOptions(chart3DAxes(req)...)
+	items := make([]opts.Chart3DData, len(points))
+	for i, p := range points {
+		items[i].Value = []interface{}{p[0], p[1], p[2]}
+	}
+	surface.AddSeries(req.Series[2].Name, items)
 	return g.extractEChartsConfig(surface)
 }
 
-func (g *ChartGenerator) generateGlobeChart(req ChartRequest) (map[string]interface{}, error) {
-	// Globe is not in standard go-echarts, skip for now
-	return map[string]interface{}{
-		"title": map[string]interface{}{"text": req.Title},
-	}, nil
+// points3D reads the first three series of req as x, y and z coordinates.
+func points3D(req ChartRequest) ([][3]float64, error) {
+	if len(req.Series) < 3 {
+		return nil, inputError(req, "series", "needs x, y and z series, got %d", len(req.Series))
+	}
+	xs, ys, zs := req.Series[0].Data, req.Series[1].Data, req.Series[2].Data
+	n := len(xs)
+	if len(ys) < n {
+		n = len(ys)
+	}
+	if len(zs) < n {
+		n = len(zs)
+	}
+	points := make([][3]float64, n)
+	for i := range points {
+		points[i] = [3]float64{xs[i], ys[i], zs[i]}
+	}
+	return points, nil
 }
 
-func (g *ChartGenerator) extractEChartsConfig(chart interface{}) (map[string]interface{}, error) {
-	var buf bytes.Buffer
-	type renderer interface {
-		Render(w io.Writer) error
+// chart3DData converts points to 3D chart items, named by XAxisData.
+func chart3DData(req ChartRequest, points [][3]float64) []opts.Chart3DData {
+	items := make([]opts.Chart3DData, len(points))
+	for i, p := range points {
+		items[i].Value = []interface{}{p[0], p[1], p[2]}
+		if i < len(req.XAxisData) {
+			items[i].Name = req.XAxisData[i]
+		}
 	}
-	if r, ok := chart.(renderer); ok {
-		if err := r.Render(&buf); err != nil {
-			return nil, err
+	return items
+}
+
+// chart3DAxes names the axes of a 3D chart after the x, y and z series.
+func chart3DAxes(req ChartRequest) []charts.GlobalOpts {
+	return []charts.GlobalOpts{
+		charts.WithXAxis3DOpts(opts.XAxis3D{Type: "value", Name: req.Series[0].Name}),
+		charts.WithYAxis3DOpts(opts.YAxis3D{Type: "value", Name: req.Series[1].Name}),
+		charts.WithZAxis3DOpts(opts.ZAxis3D{Type: "value", Name: req.Series[2].Name}),
+	}
+}
+
+func (g *ChartGenerator) generateGlobeChart(req ChartRequest) (map[string]interface{}, []string, error) {
+	var o MapOptions
+	if err := decodeOptions(req, &o); err != nil {
+		return nil, nil, err
+	}
+	globe := charts.NewGlobe()
+	globe.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{Title: req.Title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
+		charts.WithGlobeOpts(opts.GlobeComponent{
+			Shading:     "lambert",
+			Light:       &opts.GlobeLight{Main: &opts.GlobeMainLight{Intensity: 1.5}},
+			ViewControl: &opts.ViewControl{AutoRotate: opts.Bool(true)},
+		}),
+	)
+	globe.SetGlobalOptions(o.globalOpts()...)
+
+	points, unmatched := g.geoPoints(req)
+	if len(points) > 0 {
+		items := make([]opts.Chart3DData, len(points))
+		for i, p := range points {
+			items[i] = opts.Chart3DData{Name: p.Name, Value: []interface{}{p.Lng, p.Lat, p.Value}}
 		}
-	} else {
-		return nil, fmt.Errorf("chart does not implement Render method")
+		globe.AddScatter3D("points", items)
+	}
+
+	if len(req.Flows) > 0 {
+		seen := make(map[string]bool, len(unmatched))
+		for _, name := range unmatched {
+			seen[name] = true
+		}
+		items := make([]opts.Lines3DData, 0, len(req.Flows))
+		for _, f := range req.Flows {
+			from, fromOK := g.flowEndpoint(f.From, f.FromLat, f.FromLng)
+			to, toOK := g.flowEndpoint(f.To, f.ToLat, f.ToLng)
+			for _, end := range []struct {
+				name string
+				ok   bool
+			}{{f.From, fromOK}, {f.To, toOK}} {
+				if !end.ok && !seen[end.name] {
+					seen[end.name] = true
+					unmatched = append(unmatched, end.name)
+				}
+			}
+			if !fromOK || !toOK {
+				continue
+			}
+			items = append(items, opts.Lines3DData{
+				Name:   f.From + " → " + f.To,
+				Coords: [][]float32{from, to},
+				Value:  f.Value,
+			})
+		}
+		globe.AddLines3D("flows", items,
+			charts.WithLines3DEffectOpts(opts.Lines3DEffect{Show: opts.Bool(true), TrailWidth: 2, TrailLength: 0.2}),
+			charts.WithLineStyleOpts(opts.LineStyle{Width: 1, Opacity: opts.Float(0.4)}),
+		)
+	}
+
+	config, err := g.extractEChartsConfig(globe)
+	return config, unmatched, err
+}
+
+// flowEndpoint returns [lng, lat] for a flow endpoint, geocoding name when no
+// coordinates were given.
+func (g *ChartGenerator) flowEndpoint(name string, lat, lng float64) ([]float32, bool) {
+	if lat == 0 && lng == 0 && name != "" {
+		if g.geocoder == nil {
+			return nil, false
+		}
+		loc, ok := g.geocoder.Lookup(name)
+		if !ok {
+			return nil, false
+		}
+		lat, lng = loc.Lat, loc.Lng
+	}
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return nil, false
+	}
+	return []float32{float32(lng), float32(lat)}, true
+}
+
+func (g *ChartGenerator) extractEChartsConfig(chart interface{}) (map[string]interface{}, error) {
+	type configurable interface {
+		Validate()
+		JSON() map[string]interface{}
 	}
+	c, ok := chart.(configurable)
+	if !ok {
+		return nil, fmt.Errorf("chart does not expose its ECharts options")
+	}
+	c.Validate()
 
-	config := map[string]interface{}{
-		"animation":         true,
-		"animationDuration": 1000,
-		"animationEasing":   "cubicOut",
+	// Round-trip through JSON so the response holds plain values rather than
+	// go-echarts option structs.
+	raw, err := json.Marshal(c.JSON())
+	if err != nil {
+		return nil, err
+	}
+	config := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil, err
 	}
+
+	config["animation"] = true
+	config["animationDuration"] = 1000
+	config["animationEasing"] = "cubicOut"
 	return config, nil
 }
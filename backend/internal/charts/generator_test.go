@@ -0,0 +1,204 @@
+package charts
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestInputErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       ChartRequest
+		wantField string
+	}{
+		{"unknown type", ChartRequest{Type: "pictogram"}, "type"},
+		{"bubble series", ChartRequest{Type: "bubble", Series: []SeriesData{{Name: "x"}, {Name: "y"}}}, "series"},
+		{"candlestick series", ChartRequest{Type: "candlestick", Series: []SeriesData{{Name: "open"}}}, "series"},
+		{"3D series", ChartRequest{Type: "scatter3d", Series: []SeriesData{{Name: "x"}}}, "series"},
+		{"surface grid", ChartRequest{Type: "surface3d", Series: []SeriesData{
+			{Name: "x", Data: []float64{0, 1, 0}}, {Name: "y", Data: []float64{0, 0, 1}}, {Name: "z", Data: []float64{1, 2, 3}},
+		}}, "series"},
+		{"calendar date", ChartRequest{Type: "calendar_heatmap", XAxisData: []string{"2024-01-01", "soon"}, Series: []SeriesData{{Name: "s", Data: []float64{1, 2}}}}, "xAxisData[1]"},
+		{"theme river date", ChartRequest{Type: "themeriver", XAxisData: []string{"soon"}, Series: []SeriesData{{Name: "s", Data: []float64{1}}}}, "xAxisData[0]"},
+		{"gantt start", ChartRequest{Type: "gantt", Tasks: []GanttTask{{Name: "a", Start: "soon", End: "2024-01-02"}}}, "tasks[0].start"},
+		{"gantt order", ChartRequest{Type: "gantt", Tasks: []GanttTask{
+			{Name: "a", Start: "2024-01-01", End: "2024-01-02"}, {Name: "b", Start: "2024-01-03", End: "2024-01-02"},
+		}}, "tasks[1].end"},
+		{"geo coordinates", ChartRequest{Type: "geo", Points: []GeoPoint{{Name: "x", Lat: 91, Lng: 0}}}, "points"},
+		{"graph link", ChartRequest{Type: "graph", Links: []ChartLink{{Source: "a"}}}, "links[0]"},
+		{"sankey value", ChartRequest{Type: "sankey", Links: []ChartLink{{Source: "a", Target: "b"}}}, "links[0].value"},
+		{"sankey cycle", ChartRequest{Type: "sankey", Links: []ChartLink{
+			{Source: "a", Target: "b", Value: 1}, {Source: "b", Target: "c", Value: 1}, {Source: "c", Target: "a", Value: 1},
+		}}, "links"},
+		{"tree parents", ChartRequest{Type: "tree", Links: []ChartLink{{Source: "a", Target: "c"}, {Source: "b", Target: "c"}}}, "links[1].target"},
+		{"tree self", ChartRequest{Type: "tree", Links: []ChartLink{{Source: "a", Target: "a"}}}, "links[0]"},
+		{"tree cycle", ChartRequest{Type: "tree", Links: []ChartLink{{Source: "r", Target: "x"}, {Source: "a", Target: "b"}, {Source: "b", Target: "a"}}}, "links"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewChartGenerator(nil, nil).GenerateChart(context.Background(), tt.req)
+			var optsErr *OptionsError
+			if !errors.As(err, &optsErr) {
+				t.Fatalf("GenerateChart() error = %v, want an OptionsError", err)
+			}
+			if len(optsErr.Fields) != 1 || optsErr.Fields[0].Field != tt.wantField {
+				t.Errorf("GenerateChart() fields = %+v, want one on %s", optsErr.Fields, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestTreeRoots(t *testing.T) {
+	tests := []struct {
+		name     string
+		links    []ChartLink
+		wantRoot string
+		wantKids int
+	}{
+		{"one root", []ChartLink{{Source: "a", Target: "b"}, {Source: "a", Target: "c"}}, "a", 2},
+		{"root row", []ChartLink{{Target: "a"}, {Source: "a", Target: "b"}}, "a", 1},
+		{"several roots", []ChartLink{{Source: "a", Target: "b"}, {Source: "c", Target: "d"}}, "Org", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewChartGenerator(nil, nil).GenerateChart(context.Background(), ChartRequest{Type: "tree", Title: "Org", Links: tt.links})
+			if err != nil {
+				t.Fatal(err)
+			}
+			series := resp.ChartConfig["series"].([]interface{})
+			root := series[0].(map[string]interface{})["data"].([]interface{})[0].(map[string]interface{})
+			if root["name"] != tt.wantRoot || len(root["children"].([]interface{})) != tt.wantKids {
+				t.Errorf("tree root = %v, want %s with %d children", root, tt.wantRoot, tt.wantKids)
+			}
+		})
+	}
+}
+
+func TestOptionTypeErrors(t *testing.T) {
+	tests := []struct {
+		options   string
+		wantField string
+		wantMsg   string
+	}{
+		{`{"maxPoints": "ten"}`, "options.maxPoints", "must be an integer"},
+		{`{"maxPoints": 1.5}`, "options.maxPoints", "must be an integer"},
+		{`{"palette": 3}`, "options.palette", "must be a string"},
+		{`{"colors": "red"}`, "options.colors", "must be an array"},
+		{`{"legend": {"show": "yes"}}`, "options.legend.show", "must be a boolean"},
+		{`{"legend": []}`, "options.legend", "must be an object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.options, func(t *testing.T) {
+			req := ChartRequest{Type: "pie", XAxisData: []string{"a"}, Series: []SeriesData{{Name: "s", Data: []float64{1}}}, Options: []byte(tt.options)}
+			_, err := NewChartGenerator(nil, nil).GenerateChart(context.Background(), req)
+			var optsErr *OptionsError
+			if !errors.As(err, &optsErr) {
+				t.Fatalf("GenerateChart() error = %v, want an OptionsError", err)
+			}
+			want := FieldError{Field: tt.wantField, Message: tt.wantMsg}
+			if len(optsErr.Fields) != 1 || optsErr.Fields[0] != want {
+				t.Errorf("GenerateChart() fields = %+v, want %+v", optsErr.Fields, want)
+			}
+		})
+	}
+}
+
+func TestGaugeRange(t *testing.T) {
+	req := ChartRequest{Type: "gauge", Series: []SeriesData{{Name: "s", Data: []float64{0.25}}}, Options: []byte(`{"min": -0.5, "max": 0}`)}
+	resp, err := NewChartGenerator(nil, nil).GenerateChart(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	series := resp.ChartConfig["series"].([]interface{})[0].(map[string]interface{})
+	if series["min"] != -0.5 || series["max"] != 0.0 {
+		t.Errorf("gauge range = [%v, %v], want [-0.5, 0]", series["min"], series["max"])
+	}
+}
+
+func TestComboAxes(t *testing.T) {
+	req := ChartRequest{
+		Type:      "combo",
+		XAxisData: []string{"Q1", "Q2"},
+		Series: []SeriesData{
+			{Name: "revenue", Data: []float64{10, 20}},
+			{Name: "margin", Data: []float64{0.1, 0.2}},
+			{Name: "target", Data: []float64{15, 15}},
+			{Name: "costs", Data: []float64{5, 8}},
+		},
+	}
+	tests := []struct {
+		name      string
+		options   string
+		wantTypes []string
+		wantAxes  []float64
+		wantRight bool
+	}{
+		{"defaults", ``, []string{"bar", "bar", "bar", "bar"}, []float64{0, 0, 0, 0}, false},
+		{
+			"right axis",
+			`{"series": [{}, {"type": "line", "axis": 1}, {"type": "scatter"}, {"type": "area", "axis": 1}]}`,
+			[]string{"bar", "line", "scatter", "line"}, []float64{0, 1, 0, 1}, true,
+		},
+		{"right axis options", `{"valueAxes": [{"name": "EUR"}, {"name": "%"}]}`, []string{"bar", "bar", "bar", "bar"}, []float64{0, 0, 0, 0}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := req
+			req.Options = []byte(tt.options)
+			resp, err := NewChartGenerator(nil, nil).GenerateChart(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var types []string
+			var axes []float64
+			for _, s := range resp.ChartConfig["series"].([]interface{}) {
+				s := s.(map[string]interface{})
+				types = append(types, s["type"].(string))
+				axis, _ := s["yAxisIndex"].(float64)
+				axes = append(axes, axis)
+			}
+			if !reflect.DeepEqual(types, tt.wantTypes) || !reflect.DeepEqual(axes, tt.wantAxes) {
+				t.Errorf("series types %v on axes %v, want %v on %v", types, axes, tt.wantTypes, tt.wantAxes)
+			}
+			yAxes := resp.ChartConfig["yAxis"].([]interface{})
+			if right := len(yAxes) == 2; right != tt.wantRight {
+				t.Fatalf("got %d value axes, want a right axis: %v", len(yAxes), tt.wantRight)
+			}
+			if tt.wantRight && yAxes[1].(map[string]interface{})["position"] != "right" {
+				t.Errorf("second value axis = %v, want it on the right", yAxes[1])
+			}
+		})
+	}
+}
+
+func TestComboErrors(t *testing.T) {
+	tests := []struct {
+		options   string
+		wantField string
+	}{
+		{`{"series": [{"axis": 2}]}`, "options.series[0].axis"},
+		{`{"series": [{}, {"type": "scatter", "stack": "a"}]}`, "options.series[1].stack"},
+		{`{"series": [{}, {}, {}]}`, "options.series"},
+		{`{"valueAxes": [{}, {}, {}]}`, "options.valueAxes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.options, func(t *testing.T) {
+			req := ChartRequest{
+				Type:      "combo",
+				XAxisData: []string{"Q1"},
+				Series:    []SeriesData{{Name: "a", Data: []float64{1}}, {Name: "b", Data: []float64{2}}},
+				Options:   []byte(tt.options),
+			}
+			_, err := NewChartGenerator(nil, nil).GenerateChart(context.Background(), req)
+			var optsErr *OptionsError
+			if !errors.As(err, &optsErr) {
+				t.Fatalf("GenerateChart() error = %v, want an OptionsError", err)
+			}
+			if len(optsErr.Fields) != 1 || optsErr.Fields[0].Field != tt.wantField {
+				t.Errorf("GenerateChart() fields = %+v, want one on %s", optsErr.Fields, tt.wantField)
+			}
+		})
+	}
+}
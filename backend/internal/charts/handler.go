@@ -1,18 +1,21 @@
 package charts
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mjrtuhin/loomis-backend/internal/geocode"
+	"github.com/mjrtuhin/loomis-backend/internal/themes"
 )
 
 type Handler struct {
 	generator *ChartGenerator
 }
 
-func NewHandler() *Handler {
+func NewHandler(geocoder *geocode.Geocoder, brandThemes themes.Store) *Handler {
 	return &Handler{
-		generator: NewChartGenerator(),
+		generator: NewChartGenerator(geocoder, brandThemes),
 	}
 }
 
@@ -23,8 +26,16 @@ func (h *Handler) GenerateChart(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.generator.GenerateChart(req)
+	resp, err := h.generator.GenerateChart(c.Request.Context(), req)
 	if err != nil {
+		var optsErr *OptionsError
+		if errors.As(err, &optsErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "Invalid chart request",
+				"fields": optsErr.Fields,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -33,56 +44,12 @@ func (h *Handler) GenerateChart(c *gin.Context) {
 }
 
 func (h *Handler) GetChartTypes(c *gin.Context) {
-	chartTypes := []map[string]interface{}{
-		{
-			"type":        "bar",
-			"name":        "Bar Chart",
-			"category":    "basic",
-			"description": "Compare values across categories",
-		},
-		{
-			"type":        "bar_horizontal",
-			"name":        "Horizontal Bar",
-			"category":    "basic",
-			"description": "Bar chart with horizontal orientation",
-		},
-		{
-			"type":        "bar_stacked",
-			"name":        "Stacked Bar",
-			"category":    "basic",
-			"description": "Compare multiple series stacked",
-		},
-		{
-			"type":        "line",
-			"name":        "Line Chart",
-			"category":    "basic",
-			"description": "Show trends over time",
-		},
-		{
-			"type":        "line_smooth",
-			"name":        "Smooth Line",
-			"category":    "basic",
-			"description": "Line chart with smooth curves",
-		},
-		{
-			"type":        "area",
-			"name":        "Area Chart",
-			"category":    "basic",
-			"description": "Line chart with filled area",
-		},
-		{
-			"type":        "pie",
-			"name":        "Pie Chart",
-			"category":    "basic",
-			"description": "Show proportions of a whole",
-		},
-		{
-			"type":        "scatter",
-			"name":        "Scatter Plot",
-			"category":    "basic",
-			"description": "Show relationship between variables",
-		},
-	}
+	c.JSON(http.StatusOK, gin.H{"charts": h.generator.ChartTypes()})
+}
 
-	c.JSON(http.StatusOK, gin.H{"charts": chartTypes})
+func (h *Handler) GetPalettes(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"palettes": h.generator.Palettes(),
+		"themes":   h.generator.Themes(),
+	})
 }
@@ -0,0 +1,232 @@
+package charts
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// Marks are reference lines, shaded bands and annotated points drawn over
+// bar, line and scatter charts. Each mark belongs to a series, by default
+// the first one.
+type Marks struct {
+	Lines  []MarkLine  `json:"lines"`
+	Areas  []MarkArea  `json:"areas"`
+	Points []MarkPoint `json:"points"`
+}
+
+// MarkLine is a line across the value axis.
+type MarkLine struct {
+	// Type is "mean" or "median" of the series, "value" at Value, or
+	// "column" at the first value of the series named Column, such as a
+	// target repeated on every row.
+	Type   string   `json:"type"`
+	Value  *float64 `json:"value"`
+	Column string   `json:"column"`
+	Series string   `json:"series"`
+	Label  string   `json:"label"`
+}
+
+// MarkArea shades the categories, or dates on a time axis, from From to
+// To.
+type MarkArea struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Series string `json:"series"`
+	Label  string `json:"label"`
+}
+
+// MarkPoint pins the maximum or minimum of a series, or its value at one
+// row. Rows not on the chart, e.g. in another facet, are skipped.
+type MarkPoint struct {
+	// Type is "max", "min" or "row".
+	Type string `json:"type"`
+	// Row is the category, or date on a time axis, of a "row" point.
+	Row    string `json:"row"`
+	Series string `json:"series"`
+	Label  string `json:"label"`
+}
+
+// addMarks draws req.Marks on the series of chart, which must hold the
+// request's series first, in order. values is the output of seriesValues
+// and horizontal tells that the category axis is the y axis. Invalid marks
+// are reported together as an OptionsError.
+func addMarks(chart *charts.RectChart, req ChartRequest, values [][]interface{}, t *TimeOptions, horizontal bool) error {
+	m := req.Marks
+	if m == nil {
+		return nil
+	}
+	var errs []FieldError
+	fail := func(field string, err error) {
+		errs = append(errs, FieldError{Field: field, Message: err.Error()})
+	}
+
+	// coord places a mark at category on the category axis and v on the
+	// value axis.
+	coord := func(category, v interface{}) []interface{} {
+		if horizontal {
+			return []interface{}{v, category}
+		}
+		return []interface{}{category, v}
+	}
+
+	for i, l := range m.Lines {
+		field := fmt.Sprintf("marks.lines[%d]", i)
+		j, err := markSeries(req, l.Series)
+		if err != nil {
+			fail(field+".series", err)
+			continue
+		}
+		var opt charts.SeriesOpts
+		switch l.Type {
+		case "mean", "median":
+			typ := l.Type
+			if typ == "mean" {
+				typ = "average"
+			}
+			opt = charts.WithMarkLineNameTypeItemOpts(opts.MarkLineNameTypeItem{Name: l.Label, Type: typ})
+		case "value", "column":
+			var v float64
+			if l.Type == "value" {
+				if l.Value == nil {
+					fail(field+".value", errors.New("is required"))
+					continue
+				}
+				v = *l.Value
+			} else if v, err = columnValue(req, l.Column); err != nil {
+				fail(field+".column", err)
+				continue
+			}
+			if horizontal {
+				opt = charts.WithMarkLineNameXAxisItemOpts(opts.MarkLineNameXAxisItem{Name: l.Label, XAxis: v})
+			} else {
+				opt = charts.WithMarkLineNameYAxisItemOpts(opts.MarkLineNameYAxisItem{Name: l.Label, YAxis: v})
+			}
+		default:
+			fail(field+".type", errors.New("must be one of mean, median, value, column"))
+			continue
+		}
+		opt(&chart.MultiSeries[j])
+	}
+
+	for i, a := range m.Areas {
+		field := fmt.Sprintf("marks.areas[%d]", i)
+		j, err := markSeries(req, a.Series)
+		if err != nil {
+			fail(field+".series", err)
+		}
+		from, fromErr := markCategory(a.From, t)
+		if fromErr != nil {
+			fail(field+".from", fromErr)
+		}
+		to, toErr := markCategory(a.To, t)
+		if toErr != nil {
+			fail(field+".to", toErr)
+		}
+		if err != nil || fromErr != nil || toErr != nil {
+			continue
+		}
+		start, end := opts.MarkAreaData{Name: a.Label, XAxis: from}, opts.MarkAreaData{XAxis: to}
+		if horizontal {
+			start, end = opts.MarkAreaData{Name: a.Label, YAxis: from}, opts.MarkAreaData{YAxis: to}
+		}
+		charts.WithMarkAreaData([]opts.MarkAreaData{start, end})(&chart.MultiSeries[j])
+	}
+
+	for i, p := range m.Points {
+		field := fmt.Sprintf("marks.points[%d]", i)
+		j, err := markSeries(req, p.Series)
+		if err != nil {
+			fail(field+".series", err)
+			continue
+		}
+		switch p.Type {
+		case "max", "min":
+			charts.WithMarkPointNameTypeItemOpts(opts.MarkPointNameTypeItem{Name: p.Label, Type: p.Type})(&chart.MultiSeries[j])
+		case "row":
+			category, err := markCategory(p.Row, t)
+			if err != nil {
+				fail(field+".row", err)
+				continue
+			}
+			v, ok := rowValue(req, values[j], category, t)
+			if !ok {
+				continue
+			}
+			charts.WithMarkPointNameCoordItemOpts(opts.MarkPointNameCoordItem{
+				Name:       p.Label,
+				Coordinate: coord(category, v),
+				Value:      p.Label,
+			})(&chart.MultiSeries[j])
+		default:
+			fail(field+".type", errors.New("must be one of max, min, row"))
+		}
+	}
+
+	if len(errs) > 0 {
+		return &OptionsError{ChartType: req.Type, Fields: errs}
+	}
+	return nil
+}
+
+// markSeries returns the index of the series a mark belongs to.
+func markSeries(req ChartRequest, name string) (int, error) {
+	if len(req.Series) == 0 {
+		return 0, errors.New("chart has no series")
+	}
+	if name == "" {
+		return 0, nil
+	}
+	for j, s := range req.Series {
+		if s.Name == name {
+			return j, nil
+		}
+	}
+	return 0, fmt.Errorf("series %q not found", name)
+}
+
+// columnValue returns the first value of the series named column.
+func columnValue(req ChartRequest, column string) (float64, error) {
+	for _, s := range req.Series {
+		if s.Name != column {
+			continue
+		}
+		if len(s.Data) == 0 {
+			return 0, fmt.Errorf("column %q is empty", column)
+		}
+		return s.Data[0], nil
+	}
+	return 0, fmt.Errorf("column %q not found", column)
+}
+
+// markCategory returns the position of a category on the category axis:
+// the category itself, or its Unix millis on a time axis.
+func markCategory(category string, t *TimeOptions) (interface{}, error) {
+	if t == nil {
+		return category, nil
+	}
+	at, ok := ParseDate(category)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a date", category)
+	}
+	return bucketStart(at, t.Resample).UnixMilli(), nil
+}
+
+// rowValue finds the value of a series at a category position.
+func rowValue(req ChartRequest, values []interface{}, category interface{}, t *TimeOptions) (float64, bool) {
+	for i, v := range values {
+		if t == nil {
+			if i < len(req.XAxisData) && req.XAxisData[i] == category {
+				return v.(float64), true
+			}
+			continue
+		}
+		if pair := v.([]interface{}); pair[0] == category {
+			y, ok := pair[1].(float64)
+			return y, ok
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,59 @@
+package charts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMarkErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		chartType  string
+		options    string
+		marks      string
+		wantFields []string
+	}{
+		{"line type", "bar", ``, `{"lines": [{"type": "target"}]}`, []string{"marks.lines[0].type"}},
+		{"line value", "bar", ``, `{"lines": [{"type": "value"}]}`, []string{"marks.lines[0].value"}},
+		{"line column", "bar", ``, `{"lines": [{"type": "column", "column": "target"}]}`, []string{"marks.lines[0].column"}},
+		{"series", "line", ``, `{"points": [{"type": "max", "series": "cost"}]}`, []string{"marks.points[0].series"}},
+		{"dates", "line", `{"time": {}}`, `{"areas": [{"from": "soon", "to": "later"}]}`,
+			[]string{"marks.areas[0].from", "marks.areas[0].to"}},
+		{"point row", "line", `{"time": {}}`, `{"points": [{"type": "row", "row": "soon"}]}`, []string{"marks.points[0].row"}},
+		{"several", "bar", ``, `{"lines": [{"type": "value"}], "points": [{"type": "top"}]}`,
+			[]string{"marks.lines[0].value", "marks.points[0].type"}},
+		{"chart type", "pie", ``, `{"lines": [{"type": "mean"}]}`, []string{"marks"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var marks Marks
+			if err := json.Unmarshal([]byte(tt.marks), &marks); err != nil {
+				t.Fatal(err)
+			}
+			req := ChartRequest{
+				Type:      tt.chartType,
+				XAxisData: []string{"2024-01-01", "2024-01-02"},
+				Series:    []SeriesData{{Name: "sales", Data: []float64{1, 2}}},
+				Marks:     &marks,
+			}
+			if tt.options != "" {
+				req.Options = json.RawMessage(tt.options)
+			}
+			_, err := NewChartGenerator(nil, nil).GenerateChart(context.Background(), req)
+			var optsErr *OptionsError
+			if !errors.As(err, &optsErr) {
+				t.Fatalf("GenerateChart() error = %v, want an OptionsError", err)
+			}
+			var fields []string
+			for _, f := range optsErr.Fields {
+				fields = append(fields, f.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("GenerateChart() fields = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
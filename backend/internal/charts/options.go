@@ -0,0 +1,713 @@
+package charts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/mjrtuhin/loomis-backend/internal/themes"
+)
+
+// FieldError describes one invalid chart option.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// OptionsError is returned when a request's options or data do not fit
+// its chart type. Fields outside the options are named by their request
+// field, e.g. "series" or "xAxisData[2]".
+type OptionsError struct {
+	ChartType string
+	Fields    []FieldError
+}
+
+func (e *OptionsError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + ": " + f.Message
+	}
+	return fmt.Sprintf("invalid options for %s chart: %s", e.ChartType, strings.Join(msgs, "; "))
+}
+
+// chartOptions is implemented by each chart type's options struct.
+type chartOptions interface {
+	validate(v *optionsValidator)
+}
+
+// decodeOptions strictly decodes req.Options into dst and validates it.
+// Missing or null options leave dst at its zero value.
+func decodeOptions(req ChartRequest, dst chartOptions) error {
+	v := &optionsValidator{}
+
+	raw := bytes.TrimSpace(req.Options)
+	if len(raw) > 0 && !bytes.Equal(raw, []byte("null")) {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(dst); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			switch {
+			case errors.As(err, &typeErr):
+				v.fail(typeErr.Field, "must be "+jsonType(typeErr.Type))
+			case strings.HasPrefix(err.Error(), "json: unknown field "):
+				field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+				v.fail(field, "is not supported by this chart type")
+			default:
+				v.fail("", err.Error())
+			}
+			return &OptionsError{ChartType: req.Type, Fields: v.errs}
+		}
+	}
+
+	checkEnums(v, "", reflect.ValueOf(dst))
+	dst.validate(v)
+	if len(v.errs) > 0 {
+		return &OptionsError{ChartType: req.Type, Fields: v.errs}
+	}
+	return nil
+}
+
+// jsonType names the JSON value that decodes into t, with its article.
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return jsonType(t.Elem())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+type optionsValidator struct {
+	errs []FieldError
+}
+
+func (v *optionsValidator) fail(field, message string) {
+	path := "options"
+	if field != "" {
+		path += "." + field
+	}
+	v.errs = append(v.errs, FieldError{Field: path, Message: message})
+}
+
+func (v *optionsValidator) oneOf(field, value string, allowed ...string) {
+	if value == "" {
+		return
+	}
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.fail(field, fmt.Sprintf("must be one of %s", strings.Join(allowed, ", ")))
+}
+
+// checkEnums checks string fields tagged with `enum:"a,b,c"` against their
+// allowed values. The same tags drive the schema served by the chart type
+// registry.
+func checkEnums(v *optionsValidator, prefix string, val reflect.Value) {
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+	if val.Kind() == reflect.Slice {
+		for i := 0; i < val.Len(); i++ {
+			checkEnums(v, fmt.Sprintf("%s[%d].", strings.TrimSuffix(prefix, "."), i), val.Index(i))
+		}
+		return
+	}
+	if val.Kind() != reflect.Struct {
+		return
+	}
+	t := val.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			checkEnums(v, prefix, val.Field(i))
+			continue
+		}
+		name := prefix + strings.Split(f.Tag.Get("json"), ",")[0]
+		if enum := f.Tag.Get("enum"); enum != "" && f.Type.Kind() == reflect.String {
+			v.oneOf(name, val.Field(i).String(), strings.Split(enum, ",")...)
+			continue
+		}
+		checkEnums(v, name+".", val.Field(i))
+	}
+}
+
+func (v *optionsValidator) colors(field string, colors []string) {
+	for i, c := range colors {
+		if !themes.ValidColor(c) {
+			v.fail(fmt.Sprintf("%s[%d]", field, i), fmt.Sprintf("%q is not a valid color", c))
+		}
+	}
+}
+
+// LegendOptions controls the legend.
+type LegendOptions struct {
+	Show     *bool  `json:"show"`
+	Position string `json:"position" enum:"top,bottom,left,right"`
+}
+
+// AxisOptions controls a value axis.
+type AxisOptions struct {
+	Name string   `json:"name"`
+	Min  *float64 `json:"min"`
+	Max  *float64 `json:"max"`
+	Log  bool     `json:"log"`
+}
+
+// LabelOptions controls data labels.
+type LabelOptions struct {
+	Show     bool   `json:"show"`
+	Position string `json:"position"`
+}
+
+// CommonOptions are accepted by every chart type.
+type CommonOptions struct {
+	Legend *LegendOptions `json:"legend"`
+	// Colors overrides the series color palette.
+	Colors []string `json:"colors"`
+	// Palette picks one of the built-in palettes by name; Colors wins when
+	// both are set.
+	Palette string `json:"palette" enum:"default,tableau10,category10,okabe-ito,tol-bright,viridis,blues,red-blue,orange-purple"`
+	// Theme is an ECharts theme; its palette applies unless Colors or
+	// Palette is set.
+	Theme string `json:"theme" enum:"white,dark,chalk,essos,infographic,macarons,purple-passion,roma,romantic,shine,vintage,walden,westeros,wonderland"`
+	// CategoryColors pins the color of series and categories by name, so
+	// that e.g. "North" is drawn in the same color on every chart.
+	CategoryColors map[string]string `json:"categoryColors"`
+	// BrandTheme applies a custom theme saved through /api/themes by ID,
+	// at BrandThemeVersion or its latest version when that is 0. Its
+	// palette applies unless Colors or Palette is set.
+	BrandTheme        string `json:"brandTheme"`
+	BrandThemeVersion int    `json:"brandThemeVersion"`
+}
+
+func (o *CommonOptions) validate(v *optionsValidator) {
+	v.colors("colors", o.Colors)
+	if o.BrandThemeVersion < 0 {
+		v.fail("brandThemeVersion", "must not be negative")
+	}
+	names := make([]string, 0, len(o.CategoryColors))
+	for name := range o.CategoryColors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if c := o.CategoryColors[name]; !themes.ValidColor(c) {
+			v.fail("categoryColors."+name, fmt.Sprintf("%q is not a valid color", c))
+		}
+	}
+}
+
+func (o *CommonOptions) globalOpts() []charts.GlobalOpts {
+	legend := opts.Legend{Show: opts.Bool(true)}
+	if o.Legend != nil {
+		if o.Legend.Show != nil {
+			legend.Show = opts.Bool(*o.Legend.Show)
+		}
+		switch o.Legend.Position {
+		case "bottom":
+			legend.Bottom = "0"
+		case "left":
+			legend.Left, legend.Top, legend.Orient = "left", "middle", "vertical"
+		case "right":
+			legend.Right, legend.Top, legend.Orient = "0", "middle", "vertical"
+		}
+	}
+
+	result := []charts.GlobalOpts{charts.WithLegendOpts(legend)}
+	if o.Theme != "" {
+		result = append(result, charts.WithInitializationOpts(opts.Initialization{Theme: o.Theme}))
+	}
+	if p, ok := lookupPalette(o.Palette); ok && len(o.Colors) == 0 {
+		result = append(result, charts.WithColorsOpts(opts.Colors(p.Colors)))
+	}
+	if len(o.Colors) > 0 {
+		result = append(result, charts.WithColorsOpts(opts.Colors(o.Colors)))
+	}
+	return result
+}
+
+func validateAxis(v *optionsValidator, field string, a *AxisOptions) {
+	if a == nil {
+		return
+	}
+	if a.Min != nil && a.Max != nil && *a.Min >= *a.Max {
+		v.fail(field+".max", "must be greater than min")
+	}
+	if a.Log && a.Min != nil && *a.Min <= 0 {
+		v.fail(field+".min", "must be positive on a log axis")
+	}
+}
+
+// valueAxis builds the value axis for a, defaulting to a linear axis.
+func valueAxis(a *AxisOptions) (x opts.XAxis, y opts.YAxis) {
+	y = opts.YAxis{Type: "value", AxisLabel: &opts.AxisLabel{Show: opts.Bool(true)}}
+	if a != nil {
+		y.Name = a.Name
+		if a.Min != nil {
+			y.Min = *a.Min
+		}
+		if a.Max != nil {
+			y.Max = *a.Max
+		}
+		if a.Log {
+			y.Type = "log"
+		}
+	}
+	x = opts.XAxis{Type: y.Type, Name: y.Name, Min: y.Min, Max: y.Max, AxisLabel: y.AxisLabel}
+	return x, y
+}
+
+// TimeOptions turns the category axis into a time axis: XAxisData is
+// parsed as dates and the points are sorted by time.
+type TimeOptions struct {
+	// Resample buckets the points by day, week, month or quarter.
+	Resample string `json:"resample" enum:"day,week,month,quarter"`
+	// Aggregate combines points sharing a timestamp or bucket; sum by
+	// default.
+	Aggregate string `json:"aggregate" enum:"sum,mean,min,max,count,first,last"`
+	// Gaps decides what happens between points that are further apart than
+	// usual, or at empty buckets when resampling: "break" the line (the
+	// default when resampling), fill with "zero", or "connect" across it.
+	Gaps string `json:"gaps" enum:"break,zero,connect"`
+}
+
+func validateTime(v *optionsValidator, t *TimeOptions) {
+	if t != nil && t.Gaps == "zero" && t.Resample == "" {
+		v.fail("time.gaps", "zero needs resample to know where points are missing")
+	}
+}
+
+// validateMaxPoints checks the maxPoints option shared by the charts that
+// downsample large data.
+func validateMaxPoints(v *optionsValidator, n *int) {
+	if n != nil && *n != 0 && *n < 3 {
+		v.fail("maxPoints", "must be 0 to turn downsampling off, or at least 3")
+	}
+}
+
+// OverlayOptions adds a series computed from one of the chart's series,
+// drawn as a dashed line over the data.
+type OverlayOptions struct {
+	// Type is a regression ("linear", "polynomial" or "exponential"), a
+	// trailing "moving-average", or a Holt-Winters "forecast".
+	Type string `json:"type" enum:"linear,polynomial,exponential,moving-average,forecast"`
+	// Series names the series to compute from; every series when empty.
+	Series string `json:"series"`
+	// Degree of a polynomial regression, 2 by default.
+	Degree *int `json:"degree"`
+	// Window is the number of points of a moving average, 3 by default.
+	Window *int `json:"window"`
+	// Horizon is the number of points a forecast adds past the data, 6 by
+	// default.
+	Horizon *int `json:"horizon"`
+	// Season is the season length of a forecast in points; 0 forecasts
+	// the trend only.
+	Season int `json:"season"`
+}
+
+func validateOverlays(v *optionsValidator, overlays []OverlayOptions) {
+	for i, o := range overlays {
+		field := fmt.Sprintf("overlays[%d]", i)
+		if o.Type == "" {
+			v.fail(field+".type", "is required")
+		}
+		if o.Degree != nil && (o.Type != "polynomial" || *o.Degree < 2 || *o.Degree > maxPolynomialDegree) {
+			v.fail(field+".degree", fmt.Sprintf("must be between 2 and %d on a polynomial overlay", maxPolynomialDegree))
+		}
+		if o.Window != nil && (o.Type != "moving-average" || *o.Window < 2) {
+			v.fail(field+".window", "must be at least 2 on a moving-average overlay")
+		}
+		if o.Horizon != nil && (o.Type != "forecast" || *o.Horizon < 1 || *o.Horizon > maxForecastHorizon) {
+			v.fail(field+".horizon", fmt.Sprintf("must be between 1 and %d on a forecast overlay", maxForecastHorizon))
+		}
+		if o.Season != 0 && (o.Type != "forecast" || o.Season < 2) {
+			v.fail(field+".season", "must be at least 2 on a forecast overlay")
+		}
+	}
+}
+
+func labelOpts(l *LabelOptions) opts.Label {
+	if l == nil {
+		return opts.Label{}
+	}
+	return opts.Label{Show: opts.Bool(l.Show), Position: l.Position}
+}
+
+// BarOptions are the options of bar charts.
+type BarOptions struct {
+	CommonOptions
+	Stack bool `json:"stack"`
+	// Orientation is "vertical" (default) or "horizontal".
+	Orientation  string           `json:"orientation" enum:"vertical,horizontal"`
+	Labels       *LabelOptions    `json:"labels"`
+	CategoryAxis *AxisOptions     `json:"categoryAxis"`
+	ValueAxis    *AxisOptions     `json:"valueAxis"`
+	Time         *TimeOptions     `json:"time"`
+	Overlays     []OverlayOptions `json:"overlays"`
+	// MaxPoints caps the number of categories of a category axis; the
+	// smallest are summed into "Other".
+	MaxPoints *int `json:"maxPoints"`
+}
+
+func (o *BarOptions) validate(v *optionsValidator) {
+	o.CommonOptions.validate(v)
+	validateMaxPoints(v, o.MaxPoints)
+	validateTime(v, o.Time)
+	validateOverlays(v, o.Overlays)
+	if o.Labels != nil {
+		v.oneOf("labels.position", o.Labels.Position, "top", "inside", "insideTop", "insideBottom", "left", "right", "bottom")
+	}
+	if a := o.CategoryAxis; a != nil && (a.Min != nil || a.Max != nil || a.Log) {
+		v.fail("categoryAxis", "only name can be set on a category axis")
+	}
+	validateAxis(v, "valueAxis", o.ValueAxis)
+}
+
+// LineOptions are the options of line and area charts.
+type LineOptions struct {
+	CommonOptions
+	Stack        bool             `json:"stack"`
+	Smooth       bool             `json:"smooth"`
+	Area         bool             `json:"area"`
+	Labels       *LabelOptions    `json:"labels"`
+	CategoryAxis *AxisOptions     `json:"categoryAxis"`
+	ValueAxis    *AxisOptions     `json:"valueAxis"`
+	Time         *TimeOptions     `json:"time"`
+	Overlays     []OverlayOptions `json:"overlays"`
+	// MaxPoints caps the points of each series, downsampled with LTTB.
+	MaxPoints *int `json:"maxPoints"`
+}
+
+func (o *LineOptions) validate(v *optionsValidator) {
+	o.CommonOptions.validate(v)
+	validateMaxPoints(v, o.MaxPoints)
+	validateTime(v, o.Time)
+	validateOverlays(v, o.Overlays)
+	if o.Labels != nil {
+		v.oneOf("labels.position", o.Labels.Position, "top", "bottom", "left", "right", "inside")
+	}
+	if a := o.CategoryAxis; a != nil && (a.Min != nil || a.Max != nil || a.Log) {
+		v.fail("categoryAxis", "only name can be set on a category axis")
+	}
+	validateAxis(v, "valueAxis", o.ValueAxis)
+}
+
+// PieOptions are the options of pie and doughnut charts.
+type PieOptions struct {
+	CommonOptions
+	Labels *LabelOptions `json:"labels"`
+	// InnerRadius and OuterRadius are percentages of the chart size; a
+	// non-zero InnerRadius makes a doughnut.
+	InnerRadius *float64 `json:"innerRadius"`
+	OuterRadius *float64 `json:"outerRadius"`
+	// RoseType is "radius" or "area" for a Nightingale chart.
+	RoseType string `json:"roseType" enum:"radius,area"`
+	// MaxPoints caps the number of slices; the smallest are summed into
+	// "Other".
+	MaxPoints *int `json:"maxPoints"`
+}
+
+func (o *PieOptions) validate(v *optionsValidator) {
+	o.CommonOptions.validate(v)
+	validateMaxPoints(v, o.MaxPoints)
+	if o.Labels != nil {
+		v.oneOf("labels.position", o.Labels.Position, "outside", "inside", "center")
+	}
+	if o.InnerRadius != nil && (*o.InnerRadius < 0 || *o.InnerRadius >= 100) {
+		v.fail("innerRadius", "must be between 0 and 100")
+	}
+	if o.OuterRadius != nil && (*o.OuterRadius <= 0 || *o.OuterRadius > 100) {
+		v.fail("outerRadius", "must be between 0 and 100")
+	}
+	if o.InnerRadius != nil && o.OuterRadius != nil && *o.InnerRadius >= *o.OuterRadius {
+		v.fail("innerRadius", "must be smaller than outerRadius")
+	}
+}
+
+// radius returns the pie radius, using defaultInner/defaultOuter for unset
+// bounds.
+func (o *PieOptions) radius(defaultInner, defaultOuter float64) []string {
+	inner, outer := defaultInner, defaultOuter
+	if o.InnerRadius != nil {
+		inner = *o.InnerRadius
+	}
+	if o.OuterRadius != nil {
+		outer = *o.OuterRadius
+	}
+	return []string{fmt.Sprintf("%g%%", inner), fmt.Sprintf("%g%%", outer)}
+}
+
+// ScatterOptions are the options of scatter charts.
+type ScatterOptions struct {
+	CommonOptions
+	SymbolSize *float64         `json:"symbolSize"`
+	Labels     *LabelOptions    `json:"labels"`
+	YAxis      *AxisOptions     `json:"yAxis"`
+	Time       *TimeOptions     `json:"time"`
+	Overlays   []OverlayOptions `json:"overlays"`
+	// MaxPoints caps the points of each series, thinned on a grid.
+	MaxPoints *int `json:"maxPoints"`
+}
+
+func (o *ScatterOptions) validate(v *optionsValidator) {
+	o.CommonOptions.validate(v)
+	validateMaxPoints(v, o.MaxPoints)
+	validateTime(v, o.Time)
+	validateOverlays(v, o.Overlays)
+	if o.SymbolSize != nil && *o.SymbolSize <= 0 {
+		v.fail("symbolSize", "must be positive")
+	}
+	if o.Labels != nil {
+		v.oneOf("labels.position", o.Labels.Position, "top", "bottom", "left", "right", "inside")
+	}
+	validateAxis(v, "yAxis", o.YAxis)
+}
+
+func (o *ScatterOptions) seriesOpts() []charts.SeriesOpts {
+	result := []charts.SeriesOpts{charts.WithLabelOpts(labelOpts(o.Labels))}
+	if o.SymbolSize != nil {
+		size := *o.SymbolSize
+		result = append(result, charts.WithSeriesOpts(func(s *charts.SingleSeries) { s.SymbolSize = size }))
+	}
+	return result
+}
+
+// FunnelOptions are the options of funnel charts.
+type FunnelOptions struct {
+	CommonOptions
+	Labels *LabelOptions `json:"labels"`
+	// Sort is "descending" (default), "ascending" or "none".
+	Sort string `json:"sort" enum:"descending,ascending,none"`
+}
+
+func (o *FunnelOptions) validate(v *optionsValidator) {
+	o.CommonOptions.validate(v)
+	if o.Labels != nil {
+		v.oneOf("labels.position", o.Labels.Position, "left", "right", "inside", "outside")
+	}
+}
+
+// GaugeOptions are the options of gauge charts.
+type GaugeOptions struct {
+	CommonOptions
+	Min *float64 `json:"min"`
+	Max *float64 `json:"max"`
+}
+
+func (o *GaugeOptions) validate(v *optionsValidator) {
+	o.CommonOptions.validate(v)
+	if o.Min != nil && o.Max != nil && *o.Min >= *o.Max {
+		v.fail("max", "must be greater than min")
+	}
+}
+
+// MapOptions are the options of map, geo and globe charts.
+type MapOptions struct {
+	CommonOptions
+	// VisualMapColors is the low-to-high color range of the value scale.
+	VisualMapColors []string `json:"visualMapColors"`
+}
+
+func (o *MapOptions) validate(v *optionsValidator) {
+	o.CommonOptions.validate(v)
+	v.colors("visualMapColors", o.VisualMapColors)
+	if n := len(o.VisualMapColors); n == 1 {
+		v.fail("visualMapColors", "needs at least two colors")
+	}
+}
+
+// HistogramOptions are the options of histograms.
+type HistogramOptions struct {
+	CommonOptions
+	// Bins fixes the number of bins; otherwise Method picks it.
+	Bins   *int          `json:"bins"`
+	Method string        `json:"method" enum:"freedman-diaconis,sturges"`
+	Labels *LabelOptions `json:"labels"`
+}
+
+func (o *HistogramOptions) validate(v *optionsValidator) {
+	o.CommonOptions.validate(v)
+	if o.Bins != nil && (*o.Bins < 1 || *o.Bins > maxHistogramBins) {
+		v.fail("bins", fmt.Sprintf("must be between 1 and %d", maxHistogramBins))
+	}
+	if o.Labels != nil {
+		v.oneOf("labels.position", o.Labels.Position, "top", "inside", "insideTop")
+	}
+}
+
+// CalendarOptions are the options of calendar heatmaps.
+type CalendarOptions struct {
+	CommonOptions
+	VisualMapColors []string `json:"visualMapColors"`
+	Orient          string   `json:"orient" enum:"horizontal,vertical"`
+}
+
+func (o *CalendarOptions) validate(v *optionsValidator) {
+	o.CommonOptions.validate(v)
+	v.colors("visualMapColors", o.VisualMapColors)
+	if len(o.VisualMapColors) == 1 {
+		v.fail("visualMapColors", "needs at least two colors")
+	}
+}
+
+// PolarBarOptions are the options of polar bar charts.
+type PolarBarOptions struct {
+	CommonOptions
+	Stack    bool `json:"stack"`
+	RoundCap bool `json:"roundCap"`
+	// CategoryAxis puts the categories around the circle ("angle", the
+	// default) or along the radius ("radius").
+	CategoryAxis string `json:"categoryAxis" enum:"angle,radius"`
+}
+
+func (o *PolarBarOptions) validate(v *optionsValidator) {
+	o.CommonOptions.validate(v)
+}
+
+// BubbleOptions are the options of bubble charts.
+type BubbleOptions struct {
+	CommonOptions
+	// MinSize and MaxSize bound the bubble diameter in pixels.
+	MinSize *float64      `json:"minSize"`
+	MaxSize *float64      `json:"maxSize"`
+	Labels  *LabelOptions `json:"labels"`
+	XAxis   *AxisOptions  `json:"xAxis"`
+	YAxis   *AxisOptions  `json:"yAxis"`
+}
+
+func (o *BubbleOptions) validate(v *optionsValidator) {
+	o.CommonOptions.validate(v)
+	if o.MinSize != nil && *o.MinSize <= 0 {
+		v.fail("minSize", "must be positive")
+	}
+	if o.MaxSize != nil && *o.MaxSize <= 0 {
+		v.fail("maxSize", "must be positive")
+	}
+	if o.MinSize != nil && o.MaxSize != nil && *o.MinSize > *o.MaxSize {
+		v.fail("maxSize", "must not be smaller than minSize")
+	}
+	if o.Labels != nil {
+		v.oneOf("labels.position", o.Labels.Position, "top", "bottom", "left", "right", "inside")
+	}
+	validateAxis(v, "xAxis", o.XAxis)
+	validateAxis(v, "yAxis", o.YAxis)
+}
+
+// sizes returns the bubble diameter bounds in pixels.
+func (o *BubbleOptions) sizes() (min, max float64) {
+	min, max = 8, 60
+	if o.MinSize != nil {
+		min = *o.MinSize
+	}
+	if o.MaxSize != nil {
+		max = *o.MaxSize
+	}
+	return min, max
+}
+
+// GanttOptions are the options of Gantt charts.
+type GanttOptions struct {
+	CommonOptions
+	// BarHeight is the share of each row taken by its bar.
+	BarHeight *float64 `json:"barHeight"`
+}
+
+func (o *GanttOptions) validate(v *optionsValidator) {
+	o.CommonOptions.validate(v)
+	if o.BarHeight != nil && (*o.BarHeight <= 0 || *o.BarHeight > 1) {
+		v.fail("barHeight", "must be greater than 0 and at most 1")
+	}
+}
+
+// ViolinOptions are the options of violin plots.
+type ViolinOptions struct {
+	CommonOptions
+	// Bandwidth of the Gaussian kernel; Silverman's rule when unset.
+	Bandwidth *float64     `json:"bandwidth"`
+	ValueAxis *AxisOptions `json:"valueAxis"`
+}
+
+func (o *ViolinOptions) validate(v *optionsValidator) {
+	o.CommonOptions.validate(v)
+	if o.Bandwidth != nil && *o.Bandwidth <= 0 {
+		v.fail("bandwidth", "must be positive")
+	}
+	validateAxis(v, "valueAxis", o.ValueAxis)
+}
+
+// ComboSeriesOptions sets how one series of a combo chart is drawn.
+type ComboSeriesOptions struct {
+	// Type is "bar" (default), "line", "area" or "scatter".
+	Type string `json:"type" enum:"bar,line,area,scatter"`
+	// Axis picks the value axis: 0 is the left axis, 1 the right one.
+	Axis   int    `json:"axis"`
+	Smooth bool   `json:"smooth"`
+	Stack  string `json:"stack"`
+}
+
+// ComboOptions are the options of combo charts, which mix series types and
+// value axes on one category axis.
+type ComboOptions struct {
+	CommonOptions
+	// Series is matched to the request's series by position; series
+	// without an entry are drawn as bars on the left axis.
+	Series       []ComboSeriesOptions `json:"series"`
+	Labels       *LabelOptions        `json:"labels"`
+	CategoryAxis *AxisOptions         `json:"categoryAxis"`
+	// ValueAxes are the left and right value axes.
+	ValueAxes []AxisOptions `json:"valueAxes"`
+}
+
+func (o *ComboOptions) validate(v *optionsValidator) {
+	o.CommonOptions.validate(v)
+	for i, s := range o.Series {
+		if s.Axis != 0 && s.Axis != 1 {
+			v.fail(fmt.Sprintf("series[%d].axis", i), "must be 0 or 1")
+		}
+		if s.Type == "scatter" && s.Stack != "" {
+			v.fail(fmt.Sprintf("series[%d].stack", i), "cannot be set on a scatter series")
+		}
+	}
+	if o.Labels != nil {
+		v.oneOf("labels.position", o.Labels.Position, "top", "bottom", "left", "right", "inside")
+	}
+	if a := o.CategoryAxis; a != nil && (a.Min != nil || a.Max != nil || a.Log) {
+		v.fail("categoryAxis", "only name can be set on a category axis")
+	}
+	if len(o.ValueAxes) > 2 {
+		v.fail("valueAxes", "can have at most two axes")
+	}
+	for i := range o.ValueAxes {
+		validateAxis(v, fmt.Sprintf("valueAxes[%d]", i), &o.ValueAxes[i])
+	}
+}
+
+// valueAxis returns the options of value axis i, if any.
+func (o *ComboOptions) valueAxis(i int) *AxisOptions {
+	if i < len(o.ValueAxes) {
+		return &o.ValueAxes[i]
+	}
+	return nil
+}
@@ -0,0 +1,426 @@
+package charts
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// maxPolynomialDegree keeps polynomial regressions well conditioned.
+const maxPolynomialDegree = 6
+
+// maxForecastHorizon bounds how far a forecast runs past the data.
+const maxForecastHorizon = 100
+
+// overlayPoint is a point of a series: its position in the series values,
+// its place on the category axis (index or Unix millis), and its value.
+type overlayPoint struct {
+	i    int
+	x, y float64
+}
+
+// addOverlays draws overlays over chart as dashed lines computed from
+// values, the output of seriesValues, with the value axis horizontal when
+// horizontal is set. It returns the category axis labels, extended with
+// "+1", "+2"... when a forecast runs past the data on a category axis.
+func addOverlays(chart *charts.RectChart, req ChartRequest, values [][]interface{}, overlays []OverlayOptions, t *TimeOptions, horizontal bool) ([]string, error) {
+	labels := req.XAxisData
+	for k, o := range overlays {
+		field := fmt.Sprintf("options.overlays[%d]", k)
+		matched := false
+		for j, series := range req.Series {
+			if o.Series != "" && o.Series != series.Name {
+				continue
+			}
+			matched = true
+
+			overlay, err := computeOverlay(o, series.Name, values[j], len(req.XAxisData), t)
+			if err != nil {
+				return nil, inputError(req, field, "%v", err)
+			}
+			if t == nil {
+				for len(labels) < len(overlay.items) {
+					labels = append(labels[:len(labels):len(labels)], fmt.Sprintf("+%d", len(labels)-len(req.XAxisData)+1))
+				}
+			}
+
+			lineOpts := []charts.SeriesOpts{
+				charts.WithLineChartOpts(opts.LineChart{ShowSymbol: opts.Bool(false), Smooth: opts.Bool(overlay.smooth)}),
+				charts.WithLineStyleOpts(opts.LineStyle{Type: "dashed"}),
+			}
+			items := overlay.items
+			x, y := 0, 1
+			if horizontal {
+				x, y = 1, 0
+			}
+			switch {
+			case overlay.r2 != nil:
+				// R² rides along as a third value of every point, named so
+				// that tooltips list it under the trend.
+				items = withR2(items, *overlay.r2)
+				lineOpts = append(lineOpts,
+					charts.WithDimensionsOpts("x", "value", "R²"),
+					charts.WithEncodeOpts(opts.Encode{X: x, Y: y, Tooltip: []int{1, 2}}),
+					charts.WithEndLabelOpts(opts.Label{Show: opts.Bool(true), Formatter: fmt.Sprintf("R² %.3f", *overlay.r2)}),
+				)
+			case horizontal && t != nil:
+				// Time values are [millis, value] pairs, with the time on the
+				// y axis.
+				lineOpts = append(lineOpts, charts.WithEncodeOpts(opts.Encode{X: x, Y: y}))
+			}
+
+			data := make([]opts.LineData, len(items))
+			for i, v := range items {
+				data[i] = opts.LineData{Value: v}
+			}
+			line := charts.NewLine()
+			line.AddSeries(overlay.name, data, lineOpts...)
+			chart.Overlap(line)
+		}
+		if !matched {
+			return nil, inputError(req, field+".series", "%q is not a series of the chart", o.Series)
+		}
+	}
+	return labels, nil
+}
+
+// withR2 appends r2, rounded to three decimals, to every item of an
+// overlay. Values on a category axis are paired with their category index
+// first.
+func withR2(items []interface{}, r2 float64) []interface{} {
+	r2 = math.Round(r2*1000) / 1000
+	out := make([]interface{}, len(items))
+	for i, item := range items {
+		if pair, ok := item.([]interface{}); ok {
+			out[i] = []interface{}{pair[0], pair[1], r2}
+		} else {
+			out[i] = []interface{}{i, item, r2}
+		}
+	}
+	return out
+}
+
+// overlaySeries is an overlay computed for one series.
+type overlaySeries struct {
+	name string
+	// items is parallel to the axis labels on a category axis, with
+	// forecasts continuing past the last of the categories; on a time axis
+	// it holds [millis, value] pairs.
+	items  []interface{}
+	smooth bool
+	// r2 is the coefficient of determination of a trend.
+	r2 *float64
+}
+
+// computeOverlay computes overlay o of one series.
+func computeOverlay(o OverlayOptions, series string, values []interface{}, categories int, t *TimeOptions) (*overlaySeries, error) {
+	points := overlayPoints(values)
+	item := func(x, y float64) interface{} {
+		if t == nil {
+			return y
+		}
+		return []interface{}{int64(x), y}
+	}
+	items := make([]interface{}, len(values))
+	for i := range items {
+		items[i] = missingValue
+		if t != nil {
+			items[i] = []interface{}{int64(axisPosition(values, i)), missingValue}
+		}
+	}
+
+	switch o.Type {
+	case "moving-average":
+		window := 3
+		if o.Window != nil {
+			window = *o.Window
+		}
+		var sum float64
+		for k, p := range points {
+			sum += p.y
+			if k >= window {
+				sum -= points[k-window].y
+			}
+			if k >= window-1 {
+				items[p.i] = item(p.x, sum/float64(window))
+			}
+		}
+		return &overlaySeries{name: fmt.Sprintf("%s %d-point moving average", series, window), items: items}, nil
+
+	case "forecast":
+		horizon := 6
+		if o.Horizon != nil {
+			horizon = *o.Horizon
+		}
+		if len(points) < 2 || (o.Season > 0 && len(points) < 2*o.Season) {
+			return nil, fmt.Errorf("forecast of %s needs at least two points, or two seasons when seasonal", series)
+		}
+		ys := make([]float64, len(points))
+		for k, p := range points {
+			ys[k] = p.y
+		}
+		forecast := holtWinters(ys, o.Season, horizon)
+
+		last := points[len(points)-1]
+		items[last.i] = item(last.x, last.y)
+		if t == nil {
+			for len(items) < categories {
+				items = append(items, missingValue)
+			}
+			for _, y := range forecast {
+				items = append(items, y)
+			}
+		} else {
+			at := time.UnixMilli(int64(last.x)).UTC()
+			step := medianStep(points)
+			for _, y := range forecast {
+				if t.Resample != "" {
+					at = nextBucket(at, t.Resample)
+				} else {
+					at = at.Add(step)
+				}
+				items = append(items, item(float64(at.UnixMilli()), y))
+			}
+		}
+		return &overlaySeries{name: series + " forecast", items: items}, nil
+
+	default:
+		fit, r2, err := regression(o, points)
+		if err != nil {
+			return nil, fmt.Errorf("%s trend of %s: %w", o.Type, series, err)
+		}
+		for i := range values {
+			x := axisPosition(values, i)
+			items[i] = item(x, fit(x))
+		}
+		label := "trend"
+		if o.Type != "linear" {
+			label = o.Type + " trend"
+		}
+		return &overlaySeries{
+			name:   series + " " + label,
+			items:  items,
+			smooth: o.Type != "linear",
+			r2:     &r2,
+		}, nil
+	}
+}
+
+// overlayPoints returns the points of values that are not missing.
+func overlayPoints(values []interface{}) []overlayPoint {
+	points := make([]overlayPoint, 0, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case float64:
+			points = append(points, overlayPoint{i: i, x: float64(i), y: v})
+		case []interface{}:
+			if y, ok := v[1].(float64); ok {
+				points = append(points, overlayPoint{i: i, x: axisPosition(values, i), y: y})
+			}
+		}
+	}
+	return points
+}
+
+// axisPosition is the place of values[i] on the category axis.
+func axisPosition(values []interface{}, i int) float64 {
+	if pair, ok := values[i].([]interface{}); ok {
+		return float64(pair[0].(int64))
+	}
+	return float64(i)
+}
+
+// medianStep is the median interval between points on a time axis.
+func medianStep(points []overlayPoint) time.Duration {
+	steps := make([]float64, len(points)-1)
+	for k := 1; k < len(points); k++ {
+		steps[k-1] = points[k].x - points[k-1].x
+	}
+	return time.Duration(quantile(sortedCopy(steps), 0.5)) * time.Millisecond
+}
+
+// regression fits o's model to points by least squares and returns the
+// fitted curve and its coefficient of determination. x is rescaled to
+// [0, 1] so that fits over Unix millis stay well conditioned.
+func regression(o OverlayOptions, points []overlayPoint) (func(float64) float64, float64, error) {
+	degree := 1
+	if o.Type == "polynomial" {
+		degree = 2
+		if o.Degree != nil {
+			degree = *o.Degree
+		}
+	}
+	if len(points) <= degree {
+		return nil, 0, fmt.Errorf("needs at least %d points", degree+1)
+	}
+
+	lo, hi := points[0].x, points[len(points)-1].x
+	scale := func(x float64) float64 {
+		if hi == lo {
+			return 0
+		}
+		return (x - lo) / (hi - lo)
+	}
+	us := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for k, p := range points {
+		us[k], ys[k] = scale(p.x), p.y
+		if o.Type == "exponential" {
+			if p.y <= 0 {
+				return nil, 0, fmt.Errorf("needs positive values")
+			}
+			ys[k] = math.Log(p.y)
+		}
+	}
+
+	coeffs, ok := polyFit(us, ys, degree)
+	if !ok {
+		return nil, 0, fmt.Errorf("needs at least %d distinct points", degree+1)
+	}
+	fit := func(x float64) float64 {
+		u, y := scale(x), 0.0
+		for k := len(coeffs) - 1; k >= 0; k-- {
+			y = y*u + coeffs[k]
+		}
+		if o.Type == "exponential" {
+			return math.Exp(y)
+		}
+		return y
+	}
+
+	var mean, ssRes, ssTot float64
+	for _, p := range points {
+		mean += p.y
+	}
+	mean /= float64(len(points))
+	for _, p := range points {
+		ssRes += (p.y - fit(p.x)) * (p.y - fit(p.x))
+		ssTot += (p.y - mean) * (p.y - mean)
+	}
+	r2 := 1.0
+	if ssTot > 0 {
+		r2 = 1 - ssRes/ssTot
+	}
+	return fit, r2, nil
+}
+
+// polyFit returns the coefficients, lowest power first, of the polynomial
+// of the given degree closest to (xs, ys), solving the normal equations by
+// Gaussian elimination. It reports false when they are singular.
+func polyFit(xs, ys []float64, degree int) ([]float64, bool) {
+	n := degree + 1
+	a := make([][]float64, n)
+	for r := range a {
+		a[r] = make([]float64, n+1)
+	}
+	for k, x := range xs {
+		powers := make([]float64, 2*n-1)
+		powers[0] = 1
+		for p := 1; p < len(powers); p++ {
+			powers[p] = powers[p-1] * x
+		}
+		for r := 0; r < n; r++ {
+			for c := 0; c < n; c++ {
+				a[r][c] += powers[r+c]
+			}
+			a[r][n] += powers[r] * ys[k]
+		}
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		for r := 0; r < n; r++ {
+			if r == col {
+				continue
+			}
+			f := a[r][col] / a[col][col]
+			for c := col; c <= n; c++ {
+				a[r][c] -= f * a[col][c]
+			}
+		}
+	}
+
+	coeffs := make([]float64, n)
+	for r := range coeffs {
+		coeffs[r] = a[r][n] / a[r][r]
+	}
+	return coeffs, true
+}
+
+// smoothingGrid holds the smoothing parameters tried by holtWinters.
+var smoothingGrid = []float64{0.1, 0.3, 0.5, 0.7, 0.9}
+
+// holtWinters forecasts horizon steps past ys with additive Holt-Winters
+// smoothing, picking the parameters with the lowest one-step-ahead squared
+// error from smoothingGrid. A season of 0 drops the seasonal component,
+// leaving Holt's linear trend. Seasonal forecasts need two full seasons.
+func holtWinters(ys []float64, season, horizon int) []float64 {
+	gammas := []float64{0}
+	if season > 0 {
+		gammas = smoothingGrid
+	}
+
+	best, bestSSE := []float64(nil), math.Inf(1)
+	for _, alpha := range smoothingGrid {
+		for _, beta := range smoothingGrid {
+			for _, gamma := range gammas {
+				forecast, sse := holtWintersRun(ys, season, horizon, alpha, beta, gamma)
+				if sse < bestSSE {
+					best, bestSSE = forecast, sse
+				}
+			}
+		}
+	}
+	return best
+}
+
+func holtWintersRun(ys []float64, season, horizon int, alpha, beta, gamma float64) ([]float64, float64) {
+	var level, trend float64
+	seasonal := []float64{0}
+	start := 1
+	if season > 0 {
+		first, _ := meanStdDev(ys[:season])
+		second, _ := meanStdDev(ys[season : 2*season])
+		level, trend = first, (second-first)/float64(season)
+		seasonal = make([]float64, season)
+		for i := range seasonal {
+			seasonal[i] = ys[i] - first
+		}
+		start = season
+	} else {
+		level, trend = ys[0], ys[1]-ys[0]
+	}
+	at := func(i int) *float64 { return &seasonal[i%len(seasonal)] }
+
+	var sse float64
+	for i := start; i < len(ys); i++ {
+		s := at(i)
+		err := ys[i] - (level + trend + *s)
+		sse += err * err
+
+		prev := level
+		level = alpha*(ys[i]-*s) + (1-alpha)*(level+trend)
+		trend = beta*(level-prev) + (1-beta)*trend
+		if season > 0 {
+			*s = gamma*(ys[i]-level) + (1-gamma)*(*s)
+		}
+	}
+
+	forecast := make([]float64, horizon)
+	for h := range forecast {
+		forecast[h] = level + float64(h+1)*trend + *at(len(ys) + h)
+	}
+	return forecast, sse
+}
@@ -0,0 +1,161 @@
+package charts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTrendR2(t *testing.T) {
+	tests := []struct {
+		name    string
+		options string
+		wantX   float64
+	}{
+		{"category axis", `{"overlays": [{"type": "linear"}]}`, 0},
+		{"horizontal bars", `{"orientation": "horizontal", "overlays": [{"type": "linear"}]}`, 0},
+		{"time axis", `{"time": {}, "overlays": [{"type": "linear"}]}`, 1704067200000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := ChartRequest{
+				Type:      "bar",
+				XAxisData: []string{"2024-01-01", "2024-01-02", "2024-01-03", "2024-01-04"},
+				Series:    []SeriesData{{Name: "sales", Data: []float64{1, 3, 5, 7}}},
+				Options:   json.RawMessage(tt.options),
+			}
+			resp, err := NewChartGenerator(nil, nil).GenerateChart(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := json.Marshal(resp.ChartConfig["series"])
+			if err != nil {
+				t.Fatal(err)
+			}
+			var series []struct {
+				Name       string   `json:"name"`
+				Dimensions []string `json:"dimensions"`
+				Encode     *struct {
+					X, Y    int
+					Tooltip []int
+				} `json:"encode"`
+				Data []struct {
+					Value json.RawMessage `json:"value"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(b, &series); err != nil {
+				t.Fatal(err)
+			}
+			if len(series) != 2 {
+				t.Fatalf("got %d series, want the data and its trend", len(series))
+			}
+			trend := series[1]
+			if trend.Name != "sales trend" {
+				t.Errorf("trend name = %q, want %q", trend.Name, "sales trend")
+			}
+			if len(trend.Dimensions) != 3 || trend.Dimensions[2] != "R²" || trend.Encode == nil || !reflect.DeepEqual(trend.Encode.Tooltip, []int{1, 2}) {
+				t.Errorf("trend dimensions = %v, encode = %+v, want R² listed in the tooltip", trend.Dimensions, trend.Encode)
+			}
+			var first []float64
+			if len(trend.Data) != 4 || json.Unmarshal(trend.Data[0].Value, &first) != nil || len(first) != 3 {
+				t.Fatalf("trend data = %s, want [x, value, R²] points", b)
+			}
+			if first[0] != tt.wantX || first[2] != 1 {
+				t.Errorf("first trend point = %v, want x %v and R² 1", first, tt.wantX)
+			}
+		})
+	}
+}
+
+func TestOverlayErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []float64
+		overlays  string
+		wantField string
+	}{
+		{"unknown series", []float64{1, 2, 3}, `[{"type": "linear", "series": "cost"}]`, "options.overlays[0].series"},
+		{"short forecast", []float64{1}, `[{"type": "forecast"}]`, "options.overlays[0]"},
+		{"short season", []float64{1, 2, 3}, `[{"type": "forecast", "season": 2}]`, "options.overlays[0]"},
+		{"too few points", []float64{1, 2}, `[{"type": "moving-average"}, {"type": "polynomial"}]`, "options.overlays[1]"},
+		{"exponential of negatives", []float64{1, -2, 3}, `[{"type": "exponential"}]`, "options.overlays[0]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			xs := []string{"a", "b", "c"}[:len(tt.data)]
+			req := ChartRequest{
+				Type:      "line",
+				XAxisData: xs,
+				Series:    []SeriesData{{Name: "sales", Data: tt.data}},
+				Options:   json.RawMessage(`{"overlays": ` + tt.overlays + `}`),
+			}
+			_, err := NewChartGenerator(nil, nil).GenerateChart(context.Background(), req)
+			var optsErr *OptionsError
+			if !errors.As(err, &optsErr) {
+				t.Fatalf("GenerateChart() error = %v, want an OptionsError", err)
+			}
+			if len(optsErr.Fields) != 1 || optsErr.Fields[0].Field != tt.wantField {
+				t.Errorf("GenerateChart() fields = %+v, want one on %s", optsErr.Fields, tt.wantField)
+			}
+		})
+	}
+}
+
+// closeTo reports whether got and want hold the same values to within 1e-9.
+func closeTo(got, want []float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPolyFit(t *testing.T) {
+	tests := []struct {
+		name   string
+		xs, ys []float64
+		degree int
+		want   []float64
+		wantOK bool
+	}{
+		{"line", []float64{0, 1, 2, 3}, []float64{1, 3, 5, 7}, 1, []float64{1, 2}, true},
+		{"least squares", []float64{0, 1, 2}, []float64{0, 1, 1}, 1, []float64{1.0 / 6, 0.5}, true},
+		{"parabola", []float64{0, 1, 2, 3, 4}, []float64{1, 0.5, 1, 2.5, 5}, 2, []float64{1, -1, 0.5}, true},
+		{"singular", []float64{1, 1, 1}, []float64{1, 2, 3}, 1, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := polyFit(tt.xs, tt.ys, tt.degree)
+			if ok != tt.wantOK || !closeTo(got, tt.want) {
+				t.Errorf("polyFit() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestHoltWinters(t *testing.T) {
+	tests := []struct {
+		name            string
+		ys              []float64
+		season, horizon int
+		want            []float64
+	}{
+		{"trend", []float64{1, 2, 3, 4, 5, 6}, 0, 3, []float64{7, 8, 9}},
+		{"flat", []float64{4, 4, 4}, 0, 2, []float64{4, 4}},
+		{"season", []float64{1, 3, 1, 3, 1, 3}, 2, 4, []float64{1, 3, 1, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := holtWinters(tt.ys, tt.season, tt.horizon); !closeTo(got, tt.want) {
+				t.Errorf("holtWinters(%v, %d, %d) = %v, want %v", tt.ys, tt.season, tt.horizon, got, tt.want)
+			}
+		})
+	}
+}
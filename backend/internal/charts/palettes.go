@@ -0,0 +1,183 @@
+package charts
+
+import (
+	"encoding/json"
+
+	"github.com/go-echarts/go-echarts/v2/types"
+)
+
+// Palette is a named list of colors selectable with the palette option.
+type Palette struct {
+	Name string `json:"name"`
+	// Kind is "categorical" for unordered series and categories,
+	// "sequential" for low-to-high values, or "diverging" for values around
+	// a midpoint.
+	Kind           string   `json:"kind"`
+	ColorblindSafe bool     `json:"colorblindSafe"`
+	Colors         []string `json:"colors"`
+}
+
+// palettes lists the built-in palettes, the ECharts defaults first.
+var palettes = []Palette{
+	{Name: "default", Kind: "categorical", Colors: []string{
+		"#5470c6", "#91cc75", "#fac858", "#ee6666", "#73c0de",
+		"#3ba272", "#fc8452", "#9a60b4", "#ea7ccc",
+	}},
+	{Name: "tableau10", Kind: "categorical", Colors: []string{
+		"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f",
+		"#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac",
+	}},
+	{Name: "category10", Kind: "categorical", Colors: []string{
+		"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd",
+		"#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf",
+	}},
+	{Name: "okabe-ito", Kind: "categorical", ColorblindSafe: true, Colors: []string{
+		"#e69f00", "#56b4e9", "#009e73", "#f0e442", "#0072b2",
+		"#d55e00", "#cc79a7", "#000000",
+	}},
+	{Name: "tol-bright", Kind: "categorical", ColorblindSafe: true, Colors: []string{
+		"#4477aa", "#ee6677", "#228833", "#ccbb44", "#66ccee",
+		"#aa3377", "#bbbbbb",
+	}},
+	{Name: "viridis", Kind: "sequential", ColorblindSafe: true, Colors: []string{
+		"#440154", "#482878", "#3e4989", "#31688e", "#26828e",
+		"#1f9e89", "#35b779", "#6ece58", "#b5de2b", "#fde725",
+	}},
+	{Name: "blues", Kind: "sequential", ColorblindSafe: true, Colors: []string{
+		"#f7fbff", "#deebf7", "#c6dbef", "#9ecae1", "#6baed6",
+		"#4292c6", "#2171b5", "#08519c", "#08306b",
+	}},
+	{Name: "red-blue", Kind: "diverging", ColorblindSafe: true, Colors: []string{
+		"#b2182b", "#d6604d", "#f4a582", "#fddbc7", "#f7f7f7",
+		"#d1e5f0", "#92c5de", "#4393c3", "#2166ac",
+	}},
+	{Name: "orange-purple", Kind: "diverging", ColorblindSafe: true, Colors: []string{
+		"#b35806", "#e08214", "#fdb863", "#fee0b6", "#f7f7f7",
+		"#d8daeb", "#b2abd2", "#8073ac", "#542788",
+	}},
+}
+
+// themeNames lists the themes selectable with the theme option: the ECharts
+// built-ins and the go-echarts presets. The frontend registers them with
+// ECharts and initializes the chart with ChartResponse.Theme.
+var themeNames = []string{
+	"white", "dark",
+	types.ThemeChalk, types.ThemeEssos, types.ThemeInfographic, types.ThemeMacarons,
+	types.ThemePurplePassion, types.ThemeRoma, types.ThemeRomantic, types.ThemeShine,
+	types.ThemeVintage, types.ThemeWalden, types.ThemeWesteros, types.ThemeWonderland,
+}
+
+func lookupPalette(name string) (Palette, bool) {
+	for _, p := range palettes {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Palette{}, false
+}
+
+// styleOptions are the options of CommonOptions that apply to the
+// generated config as a whole rather than through go-echarts.
+type styleOptions struct {
+	Colors            []string          `json:"colors"`
+	Palette           string            `json:"palette"`
+	Theme             string            `json:"theme"`
+	CategoryColors    map[string]string `json:"categoryColors"`
+	BrandTheme        string            `json:"brandTheme"`
+	BrandThemeVersion int               `json:"brandThemeVersion"`
+}
+
+// readStyle reads the style options of a request whose options were
+// already validated while generating its chart.
+func readStyle(req ChartRequest) styleOptions {
+	var s styleOptions
+	_ = json.Unmarshal(req.Options, &s)
+	return s
+}
+
+// pinCategoryColors gives every series, and every named data item, whose
+// name has a color in colors that color, so that a category keeps its
+// color across charts whatever its position.
+func pinCategoryColors(config map[string]interface{}, colors map[string]string) {
+	if len(colors) == 0 {
+		return
+	}
+	categories := categoryLabels(config)
+	series, _ := config["series"].([]interface{})
+	for _, s := range series {
+		s, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if s["type"] == "bar" && len(series) == 1 {
+			// Bar items are unnamed; their category is the axis label at
+			// the same index. With several series, the series colors
+			// tell them apart instead.
+			items, _ := s["data"].([]interface{})
+			for i, item := range items {
+				item, ok := item.(map[string]interface{})
+				if ok && i < len(categories) && colors[categories[i]] != "" {
+					setItemColor(item, colors[categories[i]])
+				}
+			}
+		}
+		if name, _ := s["name"].(string); colors[name] != "" {
+			setItemColor(s, colors[name])
+			if s["type"] == "line" {
+				lineStyle, _ := s["lineStyle"].(map[string]interface{})
+				if lineStyle == nil {
+					lineStyle = map[string]interface{}{}
+				}
+				lineStyle["color"] = colors[name]
+				s["lineStyle"] = lineStyle
+			}
+		}
+		pinItemColors(s["data"], colors)
+	}
+}
+
+// categoryLabels returns the labels of the first category axis of config.
+func categoryLabels(config map[string]interface{}) []string {
+	for _, key := range []string{"xAxis", "yAxis"} {
+		axes, _ := config[key].([]interface{})
+		if len(axes) == 0 {
+			continue
+		}
+		axis, _ := axes[0].(map[string]interface{})
+		data, _ := axis["data"].([]interface{})
+		if len(data) == 0 {
+			continue
+		}
+		labels := make([]string, len(data))
+		for i, d := range data {
+			labels[i], _ = d.(string)
+		}
+		return labels
+	}
+	return nil
+}
+
+// pinItemColors colors the named items of series data, descending into
+// the children of hierarchical data.
+func pinItemColors(data interface{}, colors map[string]string) {
+	items, _ := data.([]interface{})
+	for _, item := range items {
+		item, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _ := item["name"].(string); colors[name] != "" {
+			setItemColor(item, colors[name])
+		}
+		pinItemColors(item["children"], colors)
+	}
+}
+
+func setItemColor(obj map[string]interface{}, color string) {
+	style, _ := obj["itemStyle"].(map[string]interface{})
+	if style == nil {
+		style = map[string]interface{}{}
+	}
+	style["color"] = color
+	obj["itemStyle"] = style
+}
@@ -0,0 +1,194 @@
+package charts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPaletteEnums(t *testing.T) {
+	field, _ := reflect.TypeOf(CommonOptions{}).FieldByName("Palette")
+	var names []string
+	for _, p := range palettes {
+		names = append(names, p.Name)
+	}
+	if enum := strings.Split(field.Tag.Get("enum"), ","); !reflect.DeepEqual(enum, names) {
+		t.Errorf("palette enum = %v, want the built-in palettes %v", enum, names)
+	}
+	field, _ = reflect.TypeOf(CommonOptions{}).FieldByName("Theme")
+	if enum := strings.Split(field.Tag.Get("enum"), ","); !reflect.DeepEqual(enum, themeNames) {
+		t.Errorf("theme enum = %v, want %v", enum, themeNames)
+	}
+}
+
+func TestPaletteSelection(t *testing.T) {
+	okabeIto, _ := lookupPalette("okabe-ito")
+	tests := []struct {
+		name       string
+		options    string
+		wantColors []string
+		wantTheme  string
+	}{
+		{"palette", `{"palette": "okabe-ito"}`, okabeIto.Colors, ""},
+		{"colors win", `{"palette": "okabe-ito", "colors": ["#111111", "#222222"]}`, []string{"#111111", "#222222"}, ""},
+		{"theme", `{"theme": "dark", "palette": "okabe-ito"}`, okabeIto.Colors, "dark"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := ChartRequest{
+				Type:      "bar",
+				XAxisData: []string{"a", "b"},
+				Series:    []SeriesData{{Name: "s", Data: []float64{1, 2}}},
+				Options:   json.RawMessage(tt.options),
+			}
+			resp, err := NewChartGenerator(nil, nil).GenerateChart(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var colors []string
+			for _, c := range resp.ChartConfig["color"].([]interface{}) {
+				colors = append(colors, c.(string))
+			}
+			if !reflect.DeepEqual(colors, tt.wantColors) {
+				t.Errorf("colors = %v, want %v", colors, tt.wantColors)
+			}
+			if resp.Theme != tt.wantTheme {
+				t.Errorf("theme = %q, want %q", resp.Theme, tt.wantTheme)
+			}
+		})
+	}
+}
+
+func TestStyleOptionErrors(t *testing.T) {
+	tests := []struct {
+		options   string
+		wantField string
+	}{
+		{`{"palette": "rainbow"}`, "options.palette"},
+		{`{"theme": "neon"}`, "options.theme"},
+		{`{"colors": ["#111111", "#12345"]}`, "options.colors[1]"},
+		{`{"categoryColors": {"North": "#ff0000", "South": "rgb(1;2;3)"}}`, "options.categoryColors.South"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.options, func(t *testing.T) {
+			req := ChartRequest{Type: "pie", XAxisData: []string{"a"}, Series: []SeriesData{{Name: "s", Data: []float64{1}}}, Options: json.RawMessage(tt.options)}
+			_, err := NewChartGenerator(nil, nil).GenerateChart(context.Background(), req)
+			var optsErr *OptionsError
+			if !errors.As(err, &optsErr) {
+				t.Fatalf("GenerateChart() error = %v, want an OptionsError", err)
+			}
+			if len(optsErr.Fields) != 1 || optsErr.Fields[0].Field != tt.wantField {
+				t.Errorf("GenerateChart() fields = %+v, want one on %s", optsErr.Fields, tt.wantField)
+			}
+		})
+	}
+}
+
+// itemColor returns the itemStyle color of a config object, if any.
+func itemColor(obj interface{}) interface{} {
+	style, _ := obj.(map[string]interface{})["itemStyle"].(map[string]interface{})
+	return style["color"]
+}
+
+func TestPinCategoryColors(t *testing.T) {
+	colors := map[string]string{"North": "#ff0000", "revenue": "#00ff00", "leaf": "#0000ff"}
+	decode := func(s string) map[string]interface{} {
+		var config map[string]interface{}
+		if err := json.Unmarshal([]byte(s), &config); err != nil {
+			t.Fatal(err)
+		}
+		return config
+	}
+
+	t.Run("single bar series", func(t *testing.T) {
+		config := decode(`{
+			"xAxis": [{"data": ["South", "North"]}],
+			"series": [{"type": "bar", "name": "sales", "data": [{"value": 1}, {"value": 2}]}]
+		}`)
+		pinCategoryColors(config, colors)
+		items := config["series"].([]interface{})[0].(map[string]interface{})["data"].([]interface{})
+		if itemColor(items[0]) != nil || itemColor(items[1]) != "#ff0000" {
+			t.Errorf("bar items = %v, want North pinned", items)
+		}
+	})
+
+	t.Run("horizontal bars", func(t *testing.T) {
+		config := decode(`{
+			"xAxis": [{"type": "value"}],
+			"yAxis": [{"data": ["North", "South"]}],
+			"series": [{"type": "bar", "name": "sales", "data": [{"value": 1}, {"value": 2}]}]
+		}`)
+		pinCategoryColors(config, colors)
+		items := config["series"].([]interface{})[0].(map[string]interface{})["data"].([]interface{})
+		if itemColor(items[0]) != "#ff0000" || itemColor(items[1]) != nil {
+			t.Errorf("bar items = %v, want North pinned", items)
+		}
+	})
+
+	t.Run("several series", func(t *testing.T) {
+		config := decode(`{
+			"xAxis": [{"data": ["North", "South"]}],
+			"series": [
+				{"type": "bar", "name": "revenue", "data": [{"value": 1}, {"value": 2}]},
+				{"type": "line", "name": "North", "data": [{"value": 3}, {"value": 4}]},
+				{"type": "bar", "name": "costs", "data": [{"value": 5}, {"value": 6}]}
+			]
+		}`)
+		pinCategoryColors(config, colors)
+		series := config["series"].([]interface{})
+		if itemColor(series[0]) != "#00ff00" || itemColor(series[2]) != nil {
+			t.Errorf("bar series colors = %v, %v, want revenue pinned only", itemColor(series[0]), itemColor(series[2]))
+		}
+		line := series[1].(map[string]interface{})
+		if itemColor(line) != "#ff0000" || line["lineStyle"].(map[string]interface{})["color"] != "#ff0000" {
+			t.Errorf("line series = %v, want its points and line pinned", line)
+		}
+		if items := series[0].(map[string]interface{})["data"].([]interface{}); itemColor(items[0]) != nil {
+			t.Errorf("bar item = %v, want series colors rather than category colors", items[0])
+		}
+	})
+
+	t.Run("named items", func(t *testing.T) {
+		config := decode(`{
+			"series": [{"type": "treemap", "data": [
+				{"name": "North", "value": 1, "itemStyle": {"borderWidth": 2}},
+				{"name": "South", "children": [{"name": "leaf", "value": 2}]}
+			]}]
+		}`)
+		pinCategoryColors(config, colors)
+		items := config["series"].([]interface{})[0].(map[string]interface{})["data"].([]interface{})
+		north := items[0].(map[string]interface{})
+		if itemColor(north) != "#ff0000" || north["itemStyle"].(map[string]interface{})["borderWidth"] != 2.0 {
+			t.Errorf("North = %v, want its color pinned and its style kept", north)
+		}
+		leaf := items[1].(map[string]interface{})["children"].([]interface{})[0]
+		if itemColor(items[1]) != nil || itemColor(leaf) != "#0000ff" {
+			t.Errorf("South = %v, want only its child pinned", items[1])
+		}
+	})
+}
+
+func TestCategoryColorsOption(t *testing.T) {
+	req := ChartRequest{
+		Type:      "pie",
+		XAxisData: []string{"North", "South"},
+		Series:    []SeriesData{{Name: "s", Data: []float64{1, 2}}},
+		Options:   json.RawMessage(`{"categoryColors": {"South": "#123456"}}`),
+	}
+	resp, err := NewChartGenerator(nil, nil).GenerateChart(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range resp.ChartConfig["series"].([]interface{})[0].(map[string]interface{})["data"].([]interface{}) {
+		want := interface{}(nil)
+		if item.(map[string]interface{})["name"] == "South" {
+			want = "#123456"
+		}
+		if got := itemColor(item); got != want {
+			t.Errorf("pie item %v color = %v, want %v", item, got, want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package charts
+
+import (
+	"strings"
+	"unicode"
+)
+
+// region is a single feature of a map, keyed by the name used in its GeoJSON.
+type region struct {
+	Name    string
+	Aliases []string
+}
+
+// RegionMatcher resolves user supplied region names to map feature names.
+type RegionMatcher struct {
+	lookup map[string]string
+	keys   []string
+}
+
+var regionMatchers = map[string]*RegionMatcher{
+	"world":   newRegionMatcher(worldRegions),
+	"usa":     newRegionMatcher(usaRegions),
+	"china":   newRegionMatcher(chinaRegions),
+	"india":   newRegionMatcher(indiaRegions),
+	"germany": newRegionMatcher(germanyRegions),
+}
+
+func newRegionMatcher(regions []region) *RegionMatcher {
+	m := &RegionMatcher{lookup: make(map[string]string)}
+	for _, r := range regions {
+		key := normalizeRegionName(r.Name)
+		m.lookup[key] = r.Name
+		m.keys = append(m.keys, key)
+		for _, alias := range r.Aliases {
+			m.lookup[normalizeRegionName(alias)] = r.Name
+		}
+	}
+	return m
+}
+
+// MatchRegion resolves name against the regions of mapType. Maps without a
+// region table (e.g. "uk") pass names through unchanged.
+func MatchRegion(mapType, name string) (string, bool) {
+	m, ok := regionMatchers[mapType]
+	if !ok {
+		return strings.TrimSpace(name), strings.TrimSpace(name) != ""
+	}
+	return m.Match(name)
+}
+
+// Match returns the feature name for name, trying exact names, aliases and
+// ISO codes first and falling back to a small edit distance on full names.
+func (m *RegionMatcher) Match(name string) (string, bool) {
+	key := normalizeRegionName(name)
+	if key == "" {
+		return "", false
+	}
+	if canonical, ok := m.lookup[key]; ok {
+		return canonical, true
+	}
+
+	// Short strings are almost always codes; guessing at them does more harm
+	// than good.
+	n := len([]rune(key))
+	if n < 5 {
+		return "", false
+	}
+	maxDist := 1
+	if n > 8 {
+		maxDist = 2
+	}
+
+	best, bestDist, ties := "", maxDist+1, 0
+	for _, candidate := range m.keys {
+		d := editDistance(key, candidate)
+		if d < bestDist {
+			best, bestDist, ties = candidate, d, 0
+		} else if d == bestDist {
+			ties++
+		}
+	}
+	if best == "" || ties > 0 {
+		return "", false
+	}
+	return m.lookup[best], true
+}
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n", "ß", "ss",
+	"&", " and ",
+)
+
+// normalizeRegionName lowercases name, folds common accents and strips
+// punctuation so that "Côte d'Ivoire" and "cote divoire" compare equal.
+func normalizeRegionName(name string) string {
+	name = accentReplacer.Replace(strings.ToLower(strings.TrimSpace(name)))
+
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-' || r == '_' || r == '/':
+			b.WriteRune(' ')
+		}
+	}
+
+	key := strings.Join(strings.Fields(b.String()), " ")
+	return strings.TrimPrefix(key, "the ")
+}
+
+// editDistance is the optimal string alignment distance between a and b, so
+// a swapped pair of letters ("Frnace") costs one edit.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
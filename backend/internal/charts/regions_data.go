@@ -0,0 +1,340 @@
+package charts
+
+// Region names follow the feature names of the GeoJSON/TopoJSON sources the
+// frontend registers for each map (see frontend/src/utils/mapRegistry.ts).
+// Aliases cover ISO codes and the spellings people commonly type in sheets.
+
+var worldRegions = []region{
+	{Name: "Afghanistan", Aliases: []string{"AF", "AFG"}},
+	{Name: "Albania", Aliases: []string{"AL", "ALB"}},
+	{Name: "Algeria", Aliases: []string{"DZ", "DZA"}},
+	{Name: "Angola", Aliases: []string{"AO", "AGO"}},
+	{Name: "Antarctica", Aliases: []string{"AQ", "ATA"}},
+	{Name: "Argentina", Aliases: []string{"AR", "ARG"}},
+	{Name: "Armenia", Aliases: []string{"AM", "ARM"}},
+	{Name: "Australia", Aliases: []string{"AU", "AUS"}},
+	{Name: "Austria", Aliases: []string{"AT", "AUT"}},
+	{Name: "Azerbaijan", Aliases: []string{"AZ", "AZE"}},
+	{Name: "Bahamas", Aliases: []string{"BS", "BHS", "The Bahamas"}},
+	{Name: "Bangladesh", Aliases: []string{"BD", "BGD"}},
+	{Name: "Belarus", Aliases: []string{"BY", "BLR"}},
+	{Name: "Belgium", Aliases: []string{"BE", "BEL"}},
+	{Name: "Belize", Aliases: []string{"BZ", "BLZ"}},
+	{Name: "Benin", Aliases: []string{"BJ", "BEN"}},
+	{Name: "Bhutan", Aliases: []string{"BT", "BTN"}},
+	{Name: "Bolivia", Aliases: []string{"BO", "BOL", "Plurinational State of Bolivia"}},
+	{Name: "Bosnia and Herz.", Aliases: []string{"BA", "BIH", "Bosnia and Herzegovina", "Bosnia"}},
+	{Name: "Botswana", Aliases: []string{"BW", "BWA"}},
+	{Name: "Brazil", Aliases: []string{"BR", "BRA", "Brasil"}},
+	{Name: "Brunei", Aliases: []string{"BN", "BRN", "Brunei Darussalam"}},
+	{Name: "Bulgaria", Aliases: []string{"BG", "BGR"}},
+	{Name: "Burkina Faso", Aliases: []string{"BF", "BFA"}},
+	{Name: "Burundi", Aliases: []string{"BI", "BDI"}},
+	{Name: "Cambodia", Aliases: []string{"KH", "KHM"}},
+	{Name: "Cameroon", Aliases: []string{"CM", "CMR"}},
+	{Name: "Canada", Aliases: []string{"CA", "CAN"}},
+	{Name: "Central African Rep.", Aliases: []string{"CF", "CAF", "Central African Republic", "CAR"}},
+	{Name: "Chad", Aliases: []string{"TD", "TCD"}},
+	{Name: "Chile", Aliases: []string{"CL", "CHL"}},
+	{Name: "China", Aliases: []string{"CN", "CHN", "PRC", "People's Republic of China", "Mainland China"}},
+	{Name: "Colombia", Aliases: []string{"CO", "COL"}},
+	{Name: "Congo", Aliases: []string{"CG", "COG", "Republic of the Congo", "Congo-Brazzaville", "Congo Republic"}},
+	{Name: "Costa Rica", Aliases: []string{"CR", "CRI"}},
+	{Name: "Côte d'Ivoire", Aliases: []string{"CI", "CIV", "Ivory Coast", "Cote d'Ivoire"}},
+	{Name: "Croatia", Aliases: []string{"HR", "HRV", "Hrvatska"}},
+	{Name: "Cuba", Aliases: []string{"CU", "CUB"}},
+	{Name: "Cyprus", Aliases: []string{"CY", "CYP"}},
+	{Name: "Czechia", Aliases: []string{"CZ", "CZE", "Czech Republic"}},
+	{Name: "Dem. Rep. Congo", Aliases: []string{"CD", "COD", "Democratic Republic of the Congo", "DR Congo", "DRC", "Congo-Kinshasa", "Zaire"}},
+	{Name: "Denmark", Aliases: []string{"DK", "DNK"}},
+	{Name: "Djibouti", Aliases: []string{"DJ", "DJI"}},
+	{Name: "Dominican Rep.", Aliases: []string{"DO", "DOM", "Dominican Republic"}},
+	{Name: "Ecuador", Aliases: []string{"EC", "ECU"}},
+	{Name: "Egypt", Aliases: []string{"EG", "EGY"}},
+	{Name: "El Salvador", Aliases: []string{"SV", "SLV", "Salvador"}},
+	{Name: "Eq. Guinea", Aliases: []string{"GQ", "GNQ", "Equatorial Guinea"}},
+	{Name: "Eritrea", Aliases: []string{"ER", "ERI"}},
+	{Name: "Estonia", Aliases: []string{"EE", "EST"}},
+	{Name: "eSwatini", Aliases: []string{"SZ", "SWZ", "Eswatini", "Swaziland"}},
+	{Name: "Ethiopia", Aliases: []string{"ET", "ETH"}},
+	{Name: "Falkland Is.", Aliases: []string{"FK", "FLK", "Falkland Islands", "Falklands", "Malvinas"}},
+	{Name: "Fiji", Aliases: []string{"FJ", "FJI"}},
+	{Name: "Finland", Aliases: []string{"FI", "FIN", "Suomi"}},
+	{Name: "Fr. S. Antarctic Lands", Aliases: []string{"TF", "ATF", "French Southern and Antarctic Lands", "French Southern Territories"}},
+	{Name: "France", Aliases: []string{"FR", "FRA"}},
+	{Name: "Gabon", Aliases: []string{"GA", "GAB"}},
+	{Name: "Gambia", Aliases: []string{"GM", "GMB", "The Gambia"}},
+	{Name: "Georgia", Aliases: []string{"GE", "GEO"}},
+	{Name: "Germany", Aliases: []string{"DE", "DEU", "Deutschland"}},
+	{Name: "Ghana", Aliases: []string{"GH", "GHA"}},
+	{Name: "Greece", Aliases: []string{"GR", "GRC", "Hellas"}},
+	{Name: "Greenland", Aliases: []string{"GL", "GRL"}},
+	{Name: "Guatemala", Aliases: []string{"GT", "GTM"}},
+	{Name: "Guinea", Aliases: []string{"GN", "GIN"}},
+	{Name: "Guinea-Bissau", Aliases: []string{"GW", "GNB"}},
+	{Name: "Guyana", Aliases: []string{"GY", "GUY"}},
+	{Name: "Haiti", Aliases: []string{"HT", "HTI"}},
+	{Name: "Honduras", Aliases: []string{"HN", "HND"}},
+	{Name: "Hungary", Aliases: []string{"HU", "HUN"}},
+	{Name: "Iceland", Aliases: []string{"IS", "ISL"}},
+	{Name: "India", Aliases: []string{"IN", "IND", "Bharat"}},
+	{Name: "Indonesia", Aliases: []string{"ID", "IDN"}},
+	{Name: "Iran", Aliases: []string{"IR", "IRN", "Islamic Republic of Iran", "Persia"}},
+	{Name: "Iraq", Aliases: []string{"IQ", "IRQ"}},
+	{Name: "Ireland", Aliases: []string{"IE", "IRL", "Republic of Ireland", "Eire"}},
+	{Name: "Israel", Aliases: []string{"IL", "ISR"}},
+	{Name: "Italy", Aliases: []string{"IT", "ITA", "Italia"}},
+	{Name: "Jamaica", Aliases: []string{"JM", "JAM"}},
+	{Name: "Japan", Aliases: []string{"JP", "JPN", "Nippon"}},
+	{Name: "Jordan", Aliases: []string{"JO", "JOR"}},
+	{Name: "Kazakhstan", Aliases: []string{"KZ", "KAZ"}},
+	{Name: "Kenya", Aliases: []string{"KE", "KEN"}},
+	{Name: "Kosovo", Aliases: []string{"XK", "XKX"}},
+	{Name: "Kuwait", Aliases: []string{"KW", "KWT"}},
+	{Name: "Kyrgyzstan", Aliases: []string{"KG", "KGZ", "Kyrgyz Republic"}},
+	{Name: "Laos", Aliases: []string{"LA", "LAO", "Lao PDR", "Lao People's Democratic Republic"}},
+	{Name: "Latvia", Aliases: []string{"LV", "LVA"}},
+	{Name: "Lebanon", Aliases: []string{"LB", "LBN"}},
+	{Name: "Lesotho", Aliases: []string{"LS", "LSO"}},
+	{Name: "Liberia", Aliases: []string{"LR", "LBR"}},
+	{Name: "Libya", Aliases: []string{"LY", "LBY"}},
+	{Name: "Lithuania", Aliases: []string{"LT", "LTU"}},
+	{Name: "Luxembourg", Aliases: []string{"LU", "LUX"}},
+	{Name: "Macedonia", Aliases: []string{"MK", "MKD", "North Macedonia"}},
+	{Name: "Madagascar", Aliases: []string{"MG", "MDG"}},
+	{Name: "Malawi", Aliases: []string{"MW", "MWI"}},
+	{Name: "Malaysia", Aliases: []string{"MY", "MYS"}},
+	{Name: "Mali", Aliases: []string{"ML", "MLI"}},
+	{Name: "Mauritania", Aliases: []string{"MR", "MRT"}},
+	{Name: "Mexico", Aliases: []string{"MX", "MEX", "México"}},
+	{Name: "Moldova", Aliases: []string{"MD", "MDA", "Republic of Moldova"}},
+	{Name: "Mongolia", Aliases: []string{"MN", "MNG"}},
+	{Name: "Montenegro", Aliases: []string{"ME", "MNE"}},
+	{Name: "Morocco", Aliases: []string{"MA", "MAR"}},
+	{Name: "Mozambique", Aliases: []string{"MZ", "MOZ"}},
+	{Name: "Myanmar", Aliases: []string{"MM", "MMR", "Burma"}},
+	{Name: "N. Cyprus", Aliases: []string{"Northern Cyprus", "North Cyprus"}},
+	{Name: "Namibia", Aliases: []string{"NA", "NAM"}},
+	{Name: "Nepal", Aliases: []string{"NP", "NPL"}},
+	{Name: "Netherlands", Aliases: []string{"NL", "NLD", "Holland", "The Netherlands"}},
+	{Name: "New Caledonia", Aliases: []string{"NC", "NCL"}},
+	{Name: "New Zealand", Aliases: []string{"NZ", "NZL", "Aotearoa"}},
+	{Name: "Nicaragua", Aliases: []string{"NI", "NIC"}},
+	{Name: "Niger", Aliases: []string{"NE", "NER"}},
+	{Name: "Nigeria", Aliases: []string{"NG", "NGA"}},
+	{Name: "North Korea", Aliases: []string{"KP", "PRK", "DPRK", "Democratic People's Republic of Korea"}},
+	{Name: "Norway", Aliases: []string{"NO", "NOR", "Norge"}},
+	{Name: "Oman", Aliases: []string{"OM", "OMN"}},
+	{Name: "Pakistan", Aliases: []string{"PK", "PAK"}},
+	{Name: "Palestine", Aliases: []string{"PS", "PSE", "State of Palestine", "Palestinian Territories"}},
+	{Name: "Panama", Aliases: []string{"PA", "PAN"}},
+	{Name: "Papua New Guinea", Aliases: []string{"PG", "PNG"}},
+	{Name: "Paraguay", Aliases: []string{"PY", "PRY"}},
+	{Name: "Peru", Aliases: []string{"PE", "PER"}},
+	{Name: "Philippines", Aliases: []string{"PH", "PHL"}},
+	{Name: "Poland", Aliases: []string{"PL", "POL", "Polska"}},
+	{Name: "Portugal", Aliases: []string{"PT", "PRT"}},
+	{Name: "Puerto Rico", Aliases: []string{"PR", "PRI"}},
+	{Name: "Qatar", Aliases: []string{"QA", "QAT"}},
+	{Name: "Romania", Aliases: []string{"RO", "ROU"}},
+	{Name: "Russia", Aliases: []string{"RU", "RUS", "Russian Federation"}},
+	{Name: "Rwanda", Aliases: []string{"RW", "RWA"}},
+	{Name: "S. Sudan", Aliases: []string{"SS", "SSD", "South Sudan"}},
+	{Name: "Saudi Arabia", Aliases: []string{"SA", "SAU", "KSA"}},
+	{Name: "Senegal", Aliases: []string{"SN", "SEN"}},
+	{Name: "Serbia", Aliases: []string{"RS", "SRB"}},
+	{Name: "Sierra Leone", Aliases: []string{"SL", "SLE"}},
+	{Name: "Slovakia", Aliases: []string{"SK", "SVK", "Slovak Republic"}},
+	{Name: "Slovenia", Aliases: []string{"SI", "SVN"}},
+	{Name: "Solomon Is.", Aliases: []string{"SB", "SLB", "Solomon Islands"}},
+	{Name: "Somalia", Aliases: []string{"SO", "SOM"}},
+	{Name: "Somaliland"},
+	{Name: "South Africa", Aliases: []string{"ZA", "ZAF", "RSA"}},
+	{Name: "South Korea", Aliases: []string{"KR", "KOR", "Korea", "Republic of Korea"}},
+	{Name: "Spain", Aliases: []string{"ES", "ESP", "España"}},
+	{Name: "Sri Lanka", Aliases: []string{"LK", "LKA", "Ceylon"}},
+	{Name: "Sudan", Aliases: []string{"SD", "SDN"}},
+	{Name: "Suriname", Aliases: []string{"SR", "SUR"}},
+	{Name: "Sweden", Aliases: []string{"SE", "SWE", "Sverige"}},
+	{Name: "Switzerland", Aliases: []string{"CH", "CHE", "Schweiz", "Suisse"}},
+	{Name: "Syria", Aliases: []string{"SY", "SYR", "Syrian Arab Republic"}},
+	{Name: "Taiwan", Aliases: []string{"TW", "TWN"}},
+	{Name: "Tajikistan", Aliases: []string{"TJ", "TJK"}},
+	{Name: "Tanzania", Aliases: []string{"TZ", "TZA", "United Republic of Tanzania"}},
+	{Name: "Thailand", Aliases: []string{"TH", "THA"}},
+	{Name: "Timor-Leste", Aliases: []string{"TL", "TLS", "East Timor"}},
+	{Name: "Togo", Aliases: []string{"TG", "TGO"}},
+	{Name: "Trinidad and Tobago", Aliases: []string{"TT", "TTO", "Trinidad"}},
+	{Name: "Tunisia", Aliases: []string{"TN", "TUN"}},
+	{Name: "Turkey", Aliases: []string{"TR", "TUR", "Türkiye", "Turkiye"}},
+	{Name: "Turkmenistan", Aliases: []string{"TM", "TKM"}},
+	{Name: "Uganda", Aliases: []string{"UG", "UGA"}},
+	{Name: "Ukraine", Aliases: []string{"UA", "UKR"}},
+	{Name: "United Arab Emirates", Aliases: []string{"AE", "ARE", "UAE", "Emirates"}},
+	{Name: "United Kingdom", Aliases: []string{"GB", "GBR", "UK", "Great Britain", "Britain", "United Kingdom of Great Britain and Northern Ireland"}},
+	{Name: "United States of America", Aliases: []string{"US", "USA", "United States", "America", "U.S.", "U.S.A."}},
+	{Name: "Uruguay", Aliases: []string{"UY", "URY"}},
+	{Name: "Uzbekistan", Aliases: []string{"UZ", "UZB"}},
+	{Name: "Vanuatu", Aliases: []string{"VU", "VUT"}},
+	{Name: "Venezuela", Aliases: []string{"VE", "VEN", "Bolivarian Republic of Venezuela"}},
+	{Name: "Vietnam", Aliases: []string{"VN", "VNM", "Viet Nam"}},
+	{Name: "W. Sahara", Aliases: []string{"EH", "ESH", "Western Sahara"}},
+	{Name: "Yemen", Aliases: []string{"YE", "YEM"}},
+	{Name: "Zambia", Aliases: []string{"ZM", "ZMB"}},
+	{Name: "Zimbabwe", Aliases: []string{"ZW", "ZWE"}},
+}
+
+var usaRegions = []region{
+	{Name: "Alabama", Aliases: []string{"AL", "US-AL"}},
+	{Name: "Alaska", Aliases: []string{"AK", "US-AK"}},
+	{Name: "Arizona", Aliases: []string{"AZ", "US-AZ"}},
+	{Name: "Arkansas", Aliases: []string{"AR", "US-AR"}},
+	{Name: "California", Aliases: []string{"CA", "US-CA"}},
+	{Name: "Colorado", Aliases: []string{"CO", "US-CO"}},
+	{Name: "Connecticut", Aliases: []string{"CT", "US-CT"}},
+	{Name: "Delaware", Aliases: []string{"DE", "US-DE"}},
+	{Name: "District of Columbia", Aliases: []string{"DC", "US-DC", "Washington DC", "Washington D.C."}},
+	{Name: "Florida", Aliases: []string{"FL", "US-FL"}},
+	{Name: "Georgia", Aliases: []string{"GA", "US-GA"}},
+	{Name: "Hawaii", Aliases: []string{"HI", "US-HI", "Hawai'i"}},
+	{Name: "Idaho", Aliases: []string{"ID", "US-ID"}},
+	{Name: "Illinois", Aliases: []string{"IL", "US-IL"}},
+	{Name: "Indiana", Aliases: []string{"IN", "US-IN"}},
+	{Name: "Iowa", Aliases: []string{"IA", "US-IA"}},
+	{Name: "Kansas", Aliases: []string{"KS", "US-KS"}},
+	{Name: "Kentucky", Aliases: []string{"KY", "US-KY"}},
+	{Name: "Louisiana", Aliases: []string{"LA", "US-LA"}},
+	{Name: "Maine", Aliases: []string{"ME", "US-ME"}},
+	{Name: "Maryland", Aliases: []string{"MD", "US-MD"}},
+	{Name: "Massachusetts", Aliases: []string{"MA", "US-MA"}},
+	{Name: "Michigan", Aliases: []string{"MI", "US-MI"}},
+	{Name: "Minnesota", Aliases: []string{"MN", "US-MN"}},
+	{Name: "Mississippi", Aliases: []string{"MS", "US-MS"}},
+	{Name: "Missouri", Aliases: []string{"MO", "US-MO"}},
+	{Name: "Montana", Aliases: []string{"MT", "US-MT"}},
+	{Name: "Nebraska", Aliases: []string{"NE", "US-NE"}},
+	{Name: "Nevada", Aliases: []string{"NV", "US-NV"}},
+	{Name: "New Hampshire", Aliases: []string{"NH", "US-NH"}},
+	{Name: "New Jersey", Aliases: []string{"NJ", "US-NJ"}},
+	{Name: "New Mexico", Aliases: []string{"NM", "US-NM"}},
+	{Name: "New York", Aliases: []string{"NY", "US-NY", "New York State"}},
+	{Name: "North Carolina", Aliases: []string{"NC", "US-NC"}},
+	{Name: "North Dakota", Aliases: []string{"ND", "US-ND"}},
+	{Name: "Ohio", Aliases: []string{"OH", "US-OH"}},
+	{Name: "Oklahoma", Aliases: []string{"OK", "US-OK"}},
+	{Name: "Oregon", Aliases: []string{"OR", "US-OR"}},
+	{Name: "Pennsylvania", Aliases: []string{"PA", "US-PA"}},
+	{Name: "Rhode Island", Aliases: []string{"RI", "US-RI"}},
+	{Name: "South Carolina", Aliases: []string{"SC", "US-SC"}},
+	{Name: "South Dakota", Aliases: []string{"SD", "US-SD"}},
+	{Name: "Tennessee", Aliases: []string{"TN", "US-TN"}},
+	{Name: "Texas", Aliases: []string{"TX", "US-TX"}},
+	{Name: "Utah", Aliases: []string{"UT", "US-UT"}},
+	{Name: "Vermont", Aliases: []string{"VT", "US-VT"}},
+	{Name: "Virginia", Aliases: []string{"VA", "US-VA"}},
+	{Name: "Washington", Aliases: []string{"WA", "US-WA", "Washington State"}},
+	{Name: "West Virginia", Aliases: []string{"WV", "US-WV"}},
+	{Name: "Wisconsin", Aliases: []string{"WI", "US-WI"}},
+	{Name: "Wyoming", Aliases: []string{"WY", "US-WY"}},
+	{Name: "Puerto Rico", Aliases: []string{"PR", "US-PR"}},
+	{Name: "Guam", Aliases: []string{"GU", "US-GU"}},
+	{Name: "American Samoa", Aliases: []string{"AS", "US-AS"}},
+	{Name: "Commonwealth of the Northern Mariana Islands", Aliases: []string{"MP", "US-MP", "Northern Mariana Islands"}},
+	{Name: "United States Virgin Islands", Aliases: []string{"VI", "US-VI", "US Virgin Islands", "Virgin Islands"}},
+}
+
+var chinaRegions = []region{
+	{Name: "北京市", Aliases: []string{"北京", "Beijing", "Peking", "CN-BJ"}},
+	{Name: "天津市", Aliases: []string{"天津", "Tianjin", "CN-TJ"}},
+	{Name: "河北省", Aliases: []string{"河北", "Hebei", "CN-HE"}},
+	{Name: "山西省", Aliases: []string{"山西", "Shanxi", "CN-SX"}},
+	{Name: "内蒙古自治区", Aliases: []string{"内蒙古", "Inner Mongolia", "Nei Mongol", "Neimenggu", "CN-NM"}},
+	{Name: "辽宁省", Aliases: []string{"辽宁", "Liaoning", "CN-LN"}},
+	{Name: "吉林省", Aliases: []string{"吉林", "Jilin", "CN-JL"}},
+	{Name: "黑龙江省", Aliases: []string{"黑龙江", "Heilongjiang", "CN-HL"}},
+	{Name: "上海市", Aliases: []string{"上海", "Shanghai", "CN-SH"}},
+	{Name: "江苏省", Aliases: []string{"江苏", "Jiangsu", "CN-JS"}},
+	{Name: "浙江省", Aliases: []string{"浙江", "Zhejiang", "CN-ZJ"}},
+	{Name: "安徽省", Aliases: []string{"安徽", "Anhui", "CN-AH"}},
+	{Name: "福建省", Aliases: []string{"福建", "Fujian", "CN-FJ"}},
+	{Name: "江西省", Aliases: []string{"江西", "Jiangxi", "CN-JX"}},
+	{Name: "山东省", Aliases: []string{"山东", "Shandong", "CN-SD"}},
+	{Name: "河南省", Aliases: []string{"河南", "Henan", "CN-HA"}},
+	{Name: "湖北省", Aliases: []string{"湖北", "Hubei", "CN-HB"}},
+	{Name: "湖南省", Aliases: []string{"湖南", "Hunan", "CN-HN"}},
+	{Name: "广东省", Aliases: []string{"广东", "Guangdong", "Canton", "CN-GD"}},
+	{Name: "广西壮族自治区", Aliases: []string{"广西", "Guangxi", "CN-GX"}},
+	{Name: "海南省", Aliases: []string{"海南", "Hainan", "CN-HI"}},
+	{Name: "重庆市", Aliases: []string{"重庆", "Chongqing", "CN-CQ"}},
+	{Name: "四川省", Aliases: []string{"四川", "Sichuan", "Szechuan", "CN-SC"}},
+	{Name: "贵州省", Aliases: []string{"贵州", "Guizhou", "CN-GZ"}},
+	{Name: "云南省", Aliases: []string{"云南", "Yunnan", "CN-YN"}},
+	{Name: "西藏自治区", Aliases: []string{"西藏", "Tibet", "Xizang", "CN-XZ"}},
+	{Name: "陕西省", Aliases: []string{"陕西", "Shaanxi", "CN-SN"}},
+	{Name: "甘肃省", Aliases: []string{"甘肃", "Gansu", "CN-GS"}},
+	{Name: "青海省", Aliases: []string{"青海", "Qinghai", "CN-QH"}},
+	{Name: "宁夏回族自治区", Aliases: []string{"宁夏", "Ningxia", "CN-NX"}},
+	{Name: "新疆维吾尔自治区", Aliases: []string{"新疆", "Xinjiang", "CN-XJ"}},
+	{Name: "台湾省", Aliases: []string{"台湾", "Taiwan", "CN-TW"}},
+	{Name: "香港特别行政区", Aliases: []string{"香港", "Hong Kong", "HK", "CN-HK"}},
+	{Name: "澳门特别行政区", Aliases: []string{"澳门", "Macau", "Macao", "CN-MO"}},
+}
+
+var indiaRegions = []region{
+	{Name: "Andaman and Nicobar", Aliases: []string{"IN-AN", "Andaman and Nicobar Islands"}},
+	{Name: "Andhra Pradesh", Aliases: []string{"IN-AP"}},
+	{Name: "Arunachal Pradesh", Aliases: []string{"IN-AR"}},
+	{Name: "Assam", Aliases: []string{"IN-AS"}},
+	{Name: "Bihar", Aliases: []string{"IN-BR"}},
+	{Name: "Chandigarh", Aliases: []string{"IN-CH"}},
+	{Name: "Chhattisgarh", Aliases: []string{"IN-CT", "IN-CG", "Chattisgarh"}},
+	{Name: "Dadra and Nagar Haveli", Aliases: []string{"IN-DN"}},
+	{Name: "Daman and Diu", Aliases: []string{"IN-DD"}},
+	{Name: "Delhi", Aliases: []string{"IN-DL", "NCT of Delhi", "New Delhi", "National Capital Territory of Delhi"}},
+	{Name: "Goa", Aliases: []string{"IN-GA"}},
+	{Name: "Gujarat", Aliases: []string{"IN-GJ"}},
+	{Name: "Haryana", Aliases: []string{"IN-HR"}},
+	{Name: "Himachal Pradesh", Aliases: []string{"IN-HP"}},
+	{Name: "Jammu and Kashmir", Aliases: []string{"IN-JK", "J&K"}},
+	{Name: "Jharkhand", Aliases: []string{"IN-JH"}},
+	{Name: "Karnataka", Aliases: []string{"IN-KA"}},
+	{Name: "Kerala", Aliases: []string{"IN-KL"}},
+	{Name: "Ladakh", Aliases: []string{"IN-LA"}},
+	{Name: "Lakshadweep", Aliases: []string{"IN-LD"}},
+	{Name: "Madhya Pradesh", Aliases: []string{"IN-MP"}},
+	{Name: "Maharashtra", Aliases: []string{"IN-MH"}},
+	{Name: "Manipur", Aliases: []string{"IN-MN"}},
+	{Name: "Meghalaya", Aliases: []string{"IN-ML"}},
+	{Name: "Mizoram", Aliases: []string{"IN-MZ"}},
+	{Name: "Nagaland", Aliases: []string{"IN-NL"}},
+	{Name: "Odisha", Aliases: []string{"IN-OR", "IN-OD", "Orissa"}},
+	{Name: "Puducherry", Aliases: []string{"IN-PY", "Pondicherry"}},
+	{Name: "Punjab", Aliases: []string{"IN-PB"}},
+	{Name: "Rajasthan", Aliases: []string{"IN-RJ"}},
+	{Name: "Sikkim", Aliases: []string{"IN-SK"}},
+	{Name: "Tamil Nadu", Aliases: []string{"IN-TN"}},
+	{Name: "Telangana", Aliases: []string{"IN-TG", "IN-TS"}},
+	{Name: "Tripura", Aliases: []string{"IN-TR"}},
+	{Name: "Uttar Pradesh", Aliases: []string{"IN-UP"}},
+	{Name: "Uttarakhand", Aliases: []string{"IN-UT", "IN-UK", "Uttaranchal"}},
+	{Name: "West Bengal", Aliases: []string{"IN-WB"}},
+}
+
+var germanyRegions = []region{
+	{Name: "Baden-Württemberg", Aliases: []string{"BW", "DE-BW", "Baden-Wuerttemberg"}},
+	{Name: "Bayern", Aliases: []string{"BY", "DE-BY", "Bavaria"}},
+	{Name: "Berlin", Aliases: []string{"BE", "DE-BE"}},
+	{Name: "Brandenburg", Aliases: []string{"BB", "DE-BB"}},
+	{Name: "Bremen", Aliases: []string{"HB", "DE-HB"}},
+	{Name: "Hamburg", Aliases: []string{"HH", "DE-HH"}},
+	{Name: "Hessen", Aliases: []string{"HE", "DE-HE", "Hesse"}},
+	{Name: "Mecklenburg-Vorpommern", Aliases: []string{"MV", "DE-MV", "Mecklenburg-Western Pomerania"}},
+	{Name: "Niedersachsen", Aliases: []string{"NI", "DE-NI", "Lower Saxony"}},
+	{Name: "Nordrhein-Westfalen", Aliases: []string{"NW", "NRW", "DE-NW", "North Rhine-Westphalia"}},
+	{Name: "Rheinland-Pfalz", Aliases: []string{"RP", "DE-RP", "Rhineland-Palatinate"}},
+	{Name: "Saarland", Aliases: []string{"SL", "DE-SL"}},
+	{Name: "Sachsen", Aliases: []string{"SN", "DE-SN", "Saxony"}},
+	{Name: "Sachsen-Anhalt", Aliases: []string{"ST", "DE-ST", "Saxony-Anhalt"}},
+	{Name: "Schleswig-Holstein", Aliases: []string{"SH", "DE-SH"}},
+	{Name: "Thüringen", Aliases: []string{"TH", "DE-TH", "Thuringia", "Thueringen"}},
+}
@@ -0,0 +1,69 @@
+package charts
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestMatchRegion(t *testing.T) {
+	tests := []struct {
+		mapType, name string
+		want          string
+		ok            bool
+	}{
+		{"world", "France", "France", true},
+		{"world", "  france ", "France", true},
+		{"world", "USA", "United States of America", true},
+		{"world", "u.s.a.", "United States of America", true},
+		{"world", "CAN", "Canada", true},
+		{"world", "Cote d'Ivoire", "Côte d'Ivoire", true},
+		{"world", "Ivory Coast", "Côte d'Ivoire", true},
+		{"world", "The Bahamas", "Bahamas", true},
+		{"world", "Bosnia & Herzegovina", "Bosnia and Herz.", true},
+		{"world", "Frnace", "France", true},
+		{"world", "Argentinia", "Argentina", true},
+		{"usa", "CA", "California", true},
+		{"usa", "US-CA", "California", true},
+		{"china", "Guangdong", "广东省", true},
+		{"germany", "Bavaria", "Bayern", true},
+		{"uk", " Wales ", "Wales", true},
+		{"world", "XX", "", false},
+		{"world", "Atlantis", "", false},
+		{"world", "", "", false},
+		{"uk", "  ", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := MatchRegion(tt.mapType, tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("MatchRegion(%q, %q) = %q, %v, want %q, %v", tt.mapType, tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMapUnmatchedRegions(t *testing.T) {
+	req := ChartRequest{
+		Type:      "map",
+		MapType:   "world",
+		XAxisData: []string{"USA", "Atlantis", "US", "France", "Atlantis", "Narnia"},
+		Series:    []SeriesData{{Name: "sales", Data: []float64{1, 2, 3, 4, 5, 6}}},
+	}
+	resp, err := NewChartGenerator(nil, nil).GenerateChart(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Atlantis", "Narnia"}; !reflect.DeepEqual(resp.UnmatchedRegions, want) {
+		t.Errorf("UnmatchedRegions = %v, want %v", resp.UnmatchedRegions, want)
+	}
+
+	series := resp.ChartConfig["series"].([]interface{})[0].(map[string]interface{})
+	var got []interface{}
+	for _, item := range series["data"].([]interface{}) {
+		d := item.(map[string]interface{})
+		got = append(got, d["name"], d["value"])
+	}
+	want := []interface{}{"United States of America", 4.0, "France", 4.0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("map data = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,542 @@
+package charts
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-echarts/go-echarts/v2/types"
+)
+
+// ColumnRole describes a column a chart type reads from the sheet.
+type ColumnRole struct {
+	Role string `json:"role"`
+	// DataType is "string", "number" or "date".
+	DataType string `json:"dataType"`
+	// Multiple is set when the role accepts several columns, e.g. one per
+	// series.
+	Multiple    bool   `json:"multiple,omitempty"`
+	Optional    bool   `json:"optional,omitempty"`
+	Description string `json:"description"`
+}
+
+// OptionField describes one field of a chart type's options.
+type OptionField struct {
+	Name   string        `json:"name"`
+	Type   string        `json:"type"`
+	Enum   []string      `json:"enum,omitempty"`
+	Items  string        `json:"items,omitempty"`
+	Fields []OptionField `json:"fields,omitempty"`
+}
+
+// generateFunc builds the ECharts option of a chart and reports names in
+// the request that could not be resolved.
+type generateFunc func(g *ChartGenerator, req ChartRequest) (map[string]interface{}, []string, error)
+
+// ChartType is a registered chart type.
+type ChartType struct {
+	Type        string       `json:"type"`
+	Name        string       `json:"name"`
+	Category    string       `json:"category"`
+	Description string       `json:"description"`
+	Aliases     []string     `json:"aliases,omitempty"`
+	Columns     []ColumnRole `json:"columns"`
+	// Options is derived from the type's options struct on registration.
+	Options []OptionField `json:"options"`
+	// Facetable is set for types that can be split into small multiples.
+	Facetable bool `json:"facetable"`
+
+	newOptions func() chartOptions
+	generate   generateFunc
+	// panel builds the chart as one grid of a faceted chart. Types with a
+	// panel builder need no generate function.
+	panel panelFunc
+}
+
+// Registry holds the chart types the generator can build, in registration
+// order.
+type Registry struct {
+	types  []*ChartType
+	lookup map[string]*ChartType
+}
+
+// NewRegistry returns a registry with every built-in chart type.
+func NewRegistry() *Registry {
+	r := &Registry{lookup: make(map[string]*ChartType)}
+	for _, t := range builtinChartTypes() {
+		r.Register(t)
+	}
+	return r
+}
+
+// Register adds t to the registry. It panics if t's type or one of its
+// aliases is already registered.
+func (r *Registry) Register(t ChartType) {
+	t.Options = optionsSchema(reflect.TypeOf(t.newOptions()))
+	if t.panel != nil {
+		t.Facetable = true
+		if t.generate == nil {
+			t.generate = singlePanel(t.panel)
+		}
+	}
+	p := &t
+	for _, name := range append([]string{t.Type}, t.Aliases...) {
+		if _, ok := r.lookup[name]; ok {
+			panic(fmt.Sprintf("charts: chart type %q registered twice", name))
+		}
+		r.lookup[name] = p
+	}
+	r.types = append(r.types, p)
+}
+
+// Lookup returns the chart type registered under name or one of its aliases.
+func (r *Registry) Lookup(name string) (*ChartType, bool) {
+	t, ok := r.lookup[name]
+	return t, ok
+}
+
+// Types returns every registered chart type in registration order.
+func (r *Registry) Types() []*ChartType {
+	return r.types
+}
+
+// configOnly adapts a generator that never reports unmatched names.
+func configOnly(fn func(*ChartGenerator, ChartRequest) (map[string]interface{}, error)) generateFunc {
+	return func(g *ChartGenerator, req ChartRequest) (map[string]interface{}, []string, error) {
+		config, err := fn(g, req)
+		return config, nil, err
+	}
+}
+
+// singlePanel generates an unfaceted chart from a panel builder.
+func singlePanel(panel panelFunc) generateFunc {
+	return func(g *ChartGenerator, req ChartRequest) (map[string]interface{}, []string, error) {
+		rc, err := panel(g, req)
+		if err != nil {
+			return nil, nil, err
+		}
+		config, err := g.extractEChartsConfig(validatedChart{rc})
+		return config, nil, err
+	}
+}
+
+func commonOptions() chartOptions    { return &CommonOptions{} }
+func barOptions() chartOptions       { return &BarOptions{} }
+func lineOptions() chartOptions      { return &LineOptions{} }
+func pieOptions() chartOptions       { return &PieOptions{} }
+func scatterOptions() chartOptions   { return &ScatterOptions{} }
+func funnelOptions() chartOptions    { return &FunnelOptions{} }
+func gaugeOptions() chartOptions     { return &GaugeOptions{} }
+func mapOptions() chartOptions       { return &MapOptions{} }
+func histogramOptions() chartOptions { return &HistogramOptions{} }
+func calendarOptions() chartOptions  { return &CalendarOptions{} }
+func polarBarOptions() chartOptions  { return &PolarBarOptions{} }
+func bubbleOptions() chartOptions    { return &BubbleOptions{} }
+func ganttOptions() chartOptions     { return &GanttOptions{} }
+func violinOptions() chartOptions    { return &ViolinOptions{} }
+func comboOptions() chartOptions     { return &ComboOptions{} }
+
+var (
+	categoryColumn = ColumnRole{Role: "category", DataType: "string", Description: "Category labels along the axis"}
+	seriesColumns  = ColumnRole{Role: "value", DataType: "number", Multiple: true, Description: "One numeric column per series"}
+	sliceColumns   = []ColumnRole{
+		{Role: "category", DataType: "string", Description: "Slice labels"},
+		{Role: "value", DataType: "number", Description: "Slice values"},
+	}
+	xyzColumns = []ColumnRole{
+		{Role: "x", DataType: "number", Description: "X coordinate"},
+		{Role: "y", DataType: "number", Description: "Y coordinate"},
+		{Role: "z", DataType: "number", Description: "Z coordinate"},
+	}
+	placeColumns = []ColumnRole{
+		{Role: "location", DataType: "string", Optional: true, Description: "Place names, geocoded when no coordinates are given"},
+		{Role: "latitude", DataType: "number", Optional: true, Description: "Latitude of each point"},
+		{Role: "longitude", DataType: "number", Optional: true, Description: "Longitude of each point"},
+		{Role: "value", DataType: "number", Optional: true, Description: "Value of each point"},
+	}
+	gridColumns = []ColumnRole{
+		{Role: "x", DataType: "string", Description: "Column labels"},
+		{Role: "value", DataType: "number", Multiple: true, Description: "One numeric column per row, named by the column header"},
+	}
+	partColumns = []ColumnRole{
+		{Role: "category", DataType: "string", Description: "Part names"},
+		{Role: "value", DataType: "number", Description: "Part sizes"},
+	}
+)
+
+func builtinChartTypes() []ChartType {
+	return []ChartType{
+		// BASIC CHARTS
+		{
+			Type: "bar", Name: "Bar Chart", Category: "basic",
+			Description: "Compare values across categories",
+			Columns:     []ColumnRole{categoryColumn, seriesColumns},
+			newOptions:  barOptions, panel: barPanel((*ChartGenerator).barChart),
+		},
+		{
+			Type: "bar_horizontal", Name: "Horizontal Bar", Category: "basic",
+			Description: "Bar chart with horizontal orientation",
+			Columns:     []ColumnRole{categoryColumn, seriesColumns},
+			newOptions:  barOptions, panel: barPanel((*ChartGenerator).horizontalBarChart),
+		},
+		{
+			Type: "bar_stacked", Name: "Stacked Bar", Category: "basic",
+			Description: "Compare multiple series stacked",
+			Columns:     []ColumnRole{categoryColumn, seriesColumns},
+			newOptions:  barOptions, panel: barPanel((*ChartGenerator).stackedBarChart),
+		},
+		{
+			Type: "line", Name: "Line Chart", Category: "basic",
+			Description: "Show trends over time",
+			Columns:     []ColumnRole{categoryColumn, seriesColumns},
+			newOptions:  lineOptions, panel: linePanel((*ChartGenerator).lineChart),
+		},
+		{
+			Type: "line_smooth", Name: "Smooth Line", Category: "basic",
+			Description: "Line chart with smooth curves",
+			Columns:     []ColumnRole{categoryColumn, seriesColumns},
+			newOptions:  lineOptions, panel: linePanel((*ChartGenerator).smoothLineChart),
+		},
+		{
+			Type: "line_area", Name: "Area Chart", Category: "basic", Aliases: []string{"area"},
+			Description: "Line chart with filled area",
+			Columns:     []ColumnRole{categoryColumn, seriesColumns},
+			newOptions:  lineOptions, panel: linePanel((*ChartGenerator).areaChart),
+		},
+		{
+			Type: "pie", Name: "Pie Chart", Category: "basic",
+			Description: "Show proportions of a whole",
+			Columns:     sliceColumns,
+			newOptions:  pieOptions, generate: configOnly((*ChartGenerator).generatePieChart),
+		},
+		{
+			Type: "pie_doughnut", Name: "Doughnut Chart", Category: "basic",
+			Description: "Pie chart with a hole in the middle",
+			Columns:     sliceColumns,
+			newOptions:  pieOptions, generate: configOnly((*ChartGenerator).generateDoughnutChart),
+		},
+		{
+			Type: "scatter", Name: "Scatter Plot", Category: "basic",
+			Description: "Show relationship between variables",
+			Columns:     []ColumnRole{categoryColumn, seriesColumns},
+			newOptions:  scatterOptions, panel: scatterPanel((*ChartGenerator).scatterChart),
+		},
+		{
+			Type: "scatter_effect", Name: "Effect Scatter", Category: "basic",
+			Description: "Scatter plot with rippling points",
+			Columns:     []ColumnRole{categoryColumn, seriesColumns},
+			newOptions:  scatterOptions, generate: configOnly((*ChartGenerator).generateEffectScatterChart),
+		},
+		{
+			Type: "scatter_bubble", Name: "Bubble Chart", Category: "basic", Aliases: []string{"bubble"},
+			Description: "Scatter plot with a third value as bubble size",
+			Columns: []ColumnRole{
+				{Role: "label", DataType: "string", Optional: true, Description: "Point names"},
+				{Role: "x", DataType: "number", Description: "Horizontal position"},
+				{Role: "y", DataType: "number", Description: "Vertical position"},
+				{Role: "size", DataType: "number", Description: "Bubble size"},
+			},
+			newOptions: bubbleOptions, generate: configOnly((*ChartGenerator).generateBubbleChart),
+		},
+		{
+			Type: "combo", Name: "Combo Chart", Category: "basic",
+			Description: "Mix bars, lines and areas with up to two value axes",
+			Columns:     []ColumnRole{categoryColumn, seriesColumns},
+			newOptions:  comboOptions, generate: configOnly((*ChartGenerator).generateComboChart),
+		},
+		{
+			Type: "polar_bar", Name: "Polar Bar", Category: "basic",
+			Description: "Bars laid out around a circle",
+			Columns:     []ColumnRole{categoryColumn, seriesColumns},
+			newOptions:  polarBarOptions, generate: configOnly((*ChartGenerator).generatePolarBarChart),
+		},
+
+		// STATISTICAL CHARTS
+		{
+			Type: "heatmap", Name: "Heatmap", Category: "statistical",
+			Description: "Show the magnitude of values on a grid",
+			Columns:     gridColumns,
+			newOptions:  commonOptions, generate: configOnly((*ChartGenerator).generateHeatmapChart),
+		},
+		{
+			Type: "boxplot", Name: "Box Plot", Category: "statistical",
+			Description: "Summarize distributions by quartiles",
+			Columns: []ColumnRole{
+				{Role: "value", DataType: "number", Multiple: true, Description: "One column of values per box"},
+			},
+			newOptions: commonOptions, generate: configOnly((*ChartGenerator).generateBoxPlotChart),
+		},
+		{
+			Type: "candlestick", Name: "Candlestick", Category: "statistical",
+			Description: "Show open, close, low and high prices over time",
+			Columns: []ColumnRole{
+				{Role: "date", DataType: "date", Description: "Trading periods"},
+				{Role: "open", DataType: "number", Description: "Opening values"},
+				{Role: "close", DataType: "number", Description: "Closing values"},
+				{Role: "low", DataType: "number", Description: "Lowest values"},
+				{Role: "high", DataType: "number", Description: "Highest values"},
+			},
+			newOptions: commonOptions, generate: configOnly((*ChartGenerator).generateCandlestickChart),
+		},
+		{
+			Type: "histogram", Name: "Histogram", Category: "statistical",
+			Description: "Show how values are distributed across bins",
+			Columns: []ColumnRole{
+				{Role: "value", DataType: "number", Multiple: true, Description: "Values to bin, one column per series"},
+			},
+			newOptions: histogramOptions, generate: configOnly((*ChartGenerator).generateHistogramChart),
+		},
+		{
+			Type: "calendar_heatmap", Name: "Calendar Heatmap", Category: "statistical",
+			Description: "Show daily values on a calendar",
+			Columns: []ColumnRole{
+				{Role: "date", DataType: "date", Description: "Day of each value"},
+				{Role: "value", DataType: "number", Description: "Values, summed per day"},
+			},
+			newOptions: calendarOptions, generate: configOnly((*ChartGenerator).generateCalendarHeatmapChart),
+		},
+		{
+			Type: "violin", Name: "Violin Plot", Category: "statistical",
+			Description: "Compare distributions by their estimated density",
+			Columns: []ColumnRole{
+				{Role: "value", DataType: "number", Multiple: true, Description: "One column of values per violin"},
+			},
+			newOptions: violinOptions, generate: configOnly((*ChartGenerator).generateViolinChart),
+		},
+
+		// SPECIALIZED CHARTS
+		{
+			Type: "radar", Name: "Radar Chart", Category: "specialized",
+			Description: "Compare several measures at once",
+			Columns: []ColumnRole{
+				{Role: "indicator", DataType: "string", Description: "Measures on the radar axes"},
+				seriesColumns,
+			},
+			newOptions: commonOptions, generate: configOnly((*ChartGenerator).generateRadarChart),
+		},
+		{
+			Type: "funnel", Name: "Funnel Chart", Category: "specialized",
+			Description: "Show values through the stages of a process",
+			Columns:     sliceColumns,
+			newOptions:  funnelOptions, generate: configOnly((*ChartGenerator).generateFunnelChart),
+		},
+		{
+			Type: "gauge", Name: "Gauge", Category: "specialized",
+			Description: "Show a single value against a range",
+			Columns:     []ColumnRole{{Role: "value", DataType: "number", Description: "The value to show"}},
+			newOptions:  gaugeOptions, generate: configOnly((*ChartGenerator).generateGaugeChart),
+		},
+		{
+			Type: "wordcloud", Name: "Word Cloud", Category: "specialized",
+			Description: "Size words by frequency",
+			Columns: []ColumnRole{
+				{Role: "word", DataType: "string", Description: "Words"},
+				{Role: "weight", DataType: "number", Description: "Word frequencies"},
+			},
+			newOptions: commonOptions, generate: configOnly((*ChartGenerator).generateWordCloudChart),
+		},
+		{
+			Type: "liquid", Name: "Liquid Fill", Category: "specialized",
+			Description: "Show a percentage as a filled bubble",
+			Columns:     []ColumnRole{{Role: "value", DataType: "number", Description: "Fill ratio between 0 and 1"}},
+			newOptions:  commonOptions, generate: configOnly((*ChartGenerator).generateLiquidChart),
+		},
+		{
+			Type: "themeriver", Name: "Theme River", Category: "specialized",
+			Description: "Show how themes change in volume over time",
+			Columns: []ColumnRole{
+				{Role: "date", DataType: "date", Description: "Time of each value"},
+				{Role: "value", DataType: "number", Multiple: true, Description: "One column of volumes per theme"},
+			},
+			newOptions: commonOptions, generate: configOnly((*ChartGenerator).generateThemeRiverChart),
+		},
+		{
+			Type: "gantt", Name: "Gantt Chart", Category: "specialized",
+			Description: "Show tasks as bars between their start and end dates",
+			Columns: []ColumnRole{
+				{Role: "task", DataType: "string", Description: "Task names"},
+				{Role: "start", DataType: "date", Description: "Start dates"},
+				{Role: "end", DataType: "date", Description: "End dates"},
+				{Role: "group", DataType: "string", Optional: true, Description: "Groups that color the tasks"},
+			},
+			newOptions: ganttOptions, generate: configOnly((*ChartGenerator).generateGanttChart),
+		},
+
+		// RELATIONSHIP CHARTS
+		{
+			Type: "graph", Name: "Network Graph", Category: "relationship",
+			Description: "Show connections between nodes",
+			Columns: []ColumnRole{
+				{Role: "source", DataType: "string", Description: "Node each edge starts from"},
+				{Role: "target", DataType: "string", Description: "Node each edge ends at"},
+				{Role: "value", DataType: "number", Optional: true, Description: "Weight of each edge, summed into node sizes"},
+			},
+			newOptions: commonOptions, generate: configOnly((*ChartGenerator).generateGraphChart),
+		},
+		{
+			Type: "sankey", Name: "Sankey Diagram", Category: "relationship",
+			Description: "Show flows between stages",
+			Columns: []ColumnRole{
+				{Role: "source", DataType: "string", Description: "Stage each flow leaves"},
+				{Role: "target", DataType: "string", Description: "Stage each flow enters"},
+				{Role: "value", DataType: "number", Description: "Size of each flow"},
+			},
+			newOptions: commonOptions, generate: configOnly((*ChartGenerator).generateSankeyChart),
+		},
+		{
+			Type: "tree", Name: "Tree", Category: "relationship",
+			Description: "Show a hierarchy as a tree",
+			Columns: []ColumnRole{
+				{Role: "parent", DataType: "string", Description: "Parent of each node, empty for the roots"},
+				{Role: "child", DataType: "string", Description: "Node names"},
+				{Role: "value", DataType: "number", Optional: true, Description: "Value of each node"},
+			},
+			newOptions: commonOptions, generate: configOnly((*ChartGenerator).generateTreeChart),
+		},
+		{
+			Type: "treemap", Name: "Treemap", Category: "relationship",
+			Description: "Show parts of a whole as rectangles",
+			Columns:     partColumns,
+			newOptions:  commonOptions, generate: configOnly((*ChartGenerator).generateTreemapChart),
+		},
+		{
+			Type: "sunburst", Name: "Sunburst", Category: "relationship",
+			Description: "Show parts of a whole as a ring",
+			Columns:     partColumns,
+			newOptions:  commonOptions, generate: configOnly((*ChartGenerator).generateSunburstChart),
+		},
+
+		// MULTI-DIMENSIONAL
+		{
+			Type: "parallel", Name: "Parallel Coordinates", Category: "multidimensional",
+			Description: "Compare rows across many numeric dimensions",
+			Columns: []ColumnRole{
+				{Role: "label", DataType: "string", Optional: true, Description: "Row names"},
+				{Role: "dimension", DataType: "number", Multiple: true, Description: "One column per axis"},
+			},
+			newOptions: commonOptions, generate: configOnly((*ChartGenerator).generateParallelChart),
+		},
+
+		// GEOGRAPHIC
+		{
+			Type: "geo", Name: "Geo Scatter", Category: "geographic",
+			Description: "Plot points on a map",
+			Columns:     placeColumns,
+			newOptions:  mapOptions,
+			generate: func(g *ChartGenerator, req ChartRequest) (map[string]interface{}, []string, error) {
+				return g.generateGeoChart(req, types.ChartScatter)
+			},
+		},
+		{
+			Type: "geo_effect", Name: "Geo Effect Scatter", Category: "geographic",
+			Description: "Plot rippling points on a map",
+			Columns:     placeColumns,
+			newOptions:  mapOptions,
+			generate: func(g *ChartGenerator, req ChartRequest) (map[string]interface{}, []string, error) {
+				return g.generateGeoChart(req, types.ChartEffectScatter)
+			},
+		},
+		{
+			Type: "map", Name: "Choropleth Map", Category: "geographic",
+			Description: "Shade regions by value",
+			Columns: []ColumnRole{
+				{Role: "region", DataType: "string", Description: "Country, state or province names"},
+				seriesColumns,
+			},
+			newOptions: mapOptions, generate: (*ChartGenerator).generateMapChart,
+		},
+
+		// 3D CHARTS
+		{
+			Type: "bar3d", Name: "3D Bar", Category: "3d",
+			Description: "Bars on a two-dimensional grid",
+			Columns:     gridColumns,
+			newOptions:  commonOptions, generate: configOnly((*ChartGenerator).generateBar3DChart),
+		},
+		{
+			Type: "line3d", Name: "3D Line", Category: "3d",
+			Description: "A path through three-dimensional space",
+			Columns:     xyzColumns,
+			newOptions:  commonOptions, generate: configOnly((*ChartGenerator).generateLine3DChart),
+		},
+		{
+			Type: "scatter3d", Name: "3D Scatter", Category: "3d",
+			Description: "Points in three-dimensional space",
+			Columns:     xyzColumns,
+			newOptions:  commonOptions, generate: configOnly((*ChartGenerator).generateScatter3DChart),
+		},
+		{
+			Type: "surface3d", Name: "3D Surface", Category: "3d",
+			Description: "A surface over a two-dimensional grid",
+			Columns:     xyzColumns,
+			newOptions:  commonOptions, generate: configOnly((*ChartGenerator).generateSurface3DChart),
+		},
+		{
+			Type: "globe", Name: "Globe", Category: "3d",
+			Description: "Points and flows on a rotating globe",
+			Columns: append(append([]ColumnRole{}, placeColumns...),
+				ColumnRole{Role: "from", DataType: "string", Optional: true, Description: "Origin of each flow"},
+				ColumnRole{Role: "to", DataType: "string", Optional: true, Description: "Destination of each flow"},
+			),
+			newOptions: mapOptions, generate: (*ChartGenerator).generateGlobeChart,
+		},
+	}
+}
+
+// optionsSchema describes the JSON fields of an options struct. Embedded
+// structs are flattened, as encoding/json does.
+func optionsSchema(t reflect.Type) []OptionField {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var fields []OptionField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			fields = append(fields, optionsSchema(f.Type)...)
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		field := OptionField{Name: name, Type: schemaType(f.Type)}
+		if enum := f.Tag.Get("enum"); enum != "" {
+			field.Enum = strings.Split(enum, ",")
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		switch ft.Kind() {
+		case reflect.Struct:
+			field.Fields = optionsSchema(ft)
+		case reflect.Slice:
+			field.Items = schemaType(ft.Elem())
+			if ft.Elem().Kind() == reflect.Struct {
+				field.Fields = optionsSchema(ft.Elem())
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func schemaType(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int64, reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,59 @@
+package charts
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// TestChartTypesReadData checks that every chart type reading sheet columns
+// draws them, given one row per label, four series and a small hierarchy
+// of links.
+func TestChartTypesReadData(t *testing.T) {
+	// These types read data the request below does not carry.
+	skip := map[string]bool{"gantt": true, "geo": true, "geo_effect": true, "map": true, "globe": true}
+	req := ChartRequest{
+		XAxisData: []string{"2024-01-01", "2024-01-02", "2024-01-03", "2024-01-04"},
+		Series: []SeriesData{
+			{Name: "x", Data: []float64{0, 1, 0, 1}},
+			{Name: "y", Data: []float64{0, 0, 1, 1}},
+			{Name: "z", Data: []float64{0.2, 0.4, 0.6, 0.8}},
+			{Name: "w", Data: []float64{1, 2, 3, 4}},
+		},
+		Links: []ChartLink{
+			{Source: "a", Target: "b", Value: 1},
+			{Source: "a", Target: "c", Value: 2},
+			{Source: "b", Target: "d", Value: 1},
+		},
+	}
+	for _, ct := range NewRegistry().Types() {
+		if skip[ct.Type] {
+			continue
+		}
+		t.Run(ct.Type, func(t *testing.T) {
+			req := req
+			req.Type = ct.Type
+			resp, err := NewChartGenerator(nil, nil).GenerateChart(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := json.Marshal(resp.ChartConfig["series"])
+			if err != nil {
+				t.Fatal(err)
+			}
+			var series []struct {
+				Data json.RawMessage `json:"data"`
+			}
+			if err := json.Unmarshal(b, &series); err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range series {
+				var data []json.RawMessage
+				if err := json.Unmarshal(s.Data, &data); err == nil && len(data) > 0 {
+					return
+				}
+			}
+			t.Errorf("%s chart draws no data: %s", ct.Type, b)
+		})
+	}
+}
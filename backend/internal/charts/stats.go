@@ -0,0 +1,167 @@
+package charts
+
+import (
+	"math"
+	"sort"
+)
+
+// maxHistogramBins caps automatic binning so outlier-heavy data cannot ask
+// for thousands of bars.
+const maxHistogramBins = 200
+
+// histogramBin is a half-open interval [Lo, Hi); the last bin also holds Hi.
+type histogramBin struct {
+	Lo, Hi float64
+	Count  int
+}
+
+// binCount picks a bin count for values, which must be sorted. method is
+// "sturges" or "freedman-diaconis"; Freedman-Diaconis falls back to Sturges
+// when the interquartile range is zero.
+func binCount(sorted []float64, method string) int {
+	n := len(sorted)
+	if n < 2 || sorted[0] == sorted[n-1] {
+		return 1
+	}
+	sturges := int(math.Ceil(math.Log2(float64(n)))) + 1
+	if method == "sturges" {
+		return sturges
+	}
+
+	iqr := quantile(sorted, 0.75) - quantile(sorted, 0.25)
+	if iqr == 0 {
+		return sturges
+	}
+	width := 2 * iqr / math.Cbrt(float64(n))
+	k := int(math.Ceil((sorted[n-1] - sorted[0]) / width))
+	if k > maxHistogramBins {
+		k = maxHistogramBins
+	}
+	if k < 1 {
+		k = 1
+	}
+	return k
+}
+
+// histogramBins splits [lo, hi] into k equal-width empty bins.
+func histogramBins(lo, hi float64, k int) []histogramBin {
+	if lo == hi {
+		return []histogramBin{{Lo: lo, Hi: hi}}
+	}
+	width := (hi - lo) / float64(k)
+	bins := make([]histogramBin, k)
+	for i := range bins {
+		bins[i].Lo = lo + float64(i)*width
+		bins[i].Hi = lo + float64(i+1)*width
+	}
+	bins[k-1].Hi = hi
+	return bins
+}
+
+// fillBins counts values into bins, which must be contiguous and sorted.
+// Values outside the bins are ignored.
+func fillBins(bins []histogramBin, values []float64) {
+	if len(bins) == 0 {
+		return
+	}
+	lo, hi := bins[0].Lo, bins[len(bins)-1].Hi
+	for _, v := range values {
+		if v < lo || v > hi {
+			continue
+		}
+		i := len(bins) - 1
+		if hi > lo {
+			i = int((v - lo) / (hi - lo) * float64(len(bins)))
+		}
+		if i >= len(bins) {
+			i = len(bins) - 1
+		}
+		bins[i].Count++
+	}
+}
+
+// quantile returns the p-quantile of sorted values by linear interpolation
+// between closest ranks.
+func quantile(sorted []float64, p float64) float64 {
+	n := len(sorted)
+	if n == 0 {
+		return math.NaN()
+	}
+	pos := p * float64(n-1)
+	i := int(math.Floor(pos))
+	if i >= n-1 {
+		return sorted[n-1]
+	}
+	return sorted[i] + (pos-float64(i))*(sorted[i+1]-sorted[i])
+}
+
+func meanStdDev(values []float64) (mean, sd float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+	for _, v := range values {
+		sd += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sd / float64(len(values)-1))
+}
+
+// silvermanBandwidth is Silverman's rule of thumb for a Gaussian kernel.
+func silvermanBandwidth(sorted []float64) float64 {
+	_, sd := meanStdDev(sorted)
+	spread := sd
+	if iqr := (quantile(sorted, 0.75) - quantile(sorted, 0.25)) / 1.34; iqr > 0 && iqr < spread {
+		spread = iqr
+	}
+	if spread == 0 {
+		return 0
+	}
+	return 0.9 * spread * math.Pow(float64(len(sorted)), -0.2)
+}
+
+// kde estimates the density of sorted values with a Gaussian kernel at
+// points (at least 2) evenly spaced over the data range. A bandwidth of 0
+// selects Silverman's rule.
+func kde(sorted []float64, points int, bandwidth float64) (xs, density []float64) {
+	n := len(sorted)
+	if n == 0 {
+		return nil, nil
+	}
+	if bandwidth <= 0 {
+		bandwidth = silvermanBandwidth(sorted)
+	}
+	lo, hi := sorted[0], sorted[n-1]
+	xs = make([]float64, points)
+	density = make([]float64, points)
+	if bandwidth == 0 || lo == hi {
+		// Every value is the same: a spike at that value.
+		for i := range xs {
+			xs[i], density[i] = lo, 1
+		}
+		return xs, density
+	}
+
+	norm := 1 / (float64(n) * bandwidth * math.Sqrt(2*math.Pi))
+	for i := range xs {
+		x := lo + (hi-lo)*float64(i)/float64(points-1)
+		var sum float64
+		for _, v := range sorted {
+			u := (x - v) / bandwidth
+			sum += math.Exp(-0.5 * u * u)
+		}
+		xs[i], density[i] = x, sum*norm
+	}
+	return xs, density
+}
+
+func sortedCopy(values []float64) []float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return sorted
+}
@@ -0,0 +1,105 @@
+package charts
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestBinCount(t *testing.T) {
+	tests := []struct {
+		name   string
+		sorted []float64
+		method string
+		want   int
+	}{
+		{"sturges", []float64{1, 2, 3, 4, 5, 6, 7, 8}, "sturges", 4},
+		{"freedman-diaconis", []float64{1, 2, 3, 4, 5, 6, 7, 8}, "freedman-diaconis", 2},
+		{"zero interquartile range", []float64{1, 1, 1, 1, 1, 1, 1, 10}, "freedman-diaconis", 4},
+		{"capped", []float64{0, 1, 1.001, 1.002, 1.003, 1.004, 1.005, 1e6}, "freedman-diaconis", maxHistogramBins},
+		{"constant", []float64{3, 3, 3}, "sturges", 1},
+		{"single value", []float64{3}, "freedman-diaconis", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := binCount(tt.sorted, tt.method); got != tt.want {
+				t.Errorf("binCount(%v, %q) = %d, want %d", tt.sorted, tt.method, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistogramBins(t *testing.T) {
+	bins := histogramBins(0, 10, 4)
+	fillBins(bins, []float64{0, 2.5, 4.9, 10, 11, -1})
+	want := []histogramBin{{0, 2.5, 1}, {2.5, 5, 2}, {5, 7.5, 0}, {7.5, 10, 1}}
+	if !reflect.DeepEqual(bins, want) {
+		t.Errorf("bins = %v, want %v", bins, want)
+	}
+
+	bins = histogramBins(3, 3, 4)
+	fillBins(bins, []float64{3, 3})
+	if want := []histogramBin{{3, 3, 2}}; !reflect.DeepEqual(bins, want) {
+		t.Errorf("bins of a constant = %v, want %v", bins, want)
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	tests := []struct {
+		sorted []float64
+		p      float64
+		want   float64
+	}{
+		{[]float64{1, 2, 3, 4}, 0, 1},
+		{[]float64{1, 2, 3, 4}, 0.25, 1.75},
+		{[]float64{1, 2, 3, 4}, 0.5, 2.5},
+		{[]float64{1, 2, 3, 4}, 1, 4},
+		{[]float64{5}, 0.5, 5},
+	}
+	for _, tt := range tests {
+		if got := quantile(tt.sorted, tt.p); got != tt.want {
+			t.Errorf("quantile(%v, %v) = %v, want %v", tt.sorted, tt.p, got, tt.want)
+		}
+	}
+	if got := quantile(nil, 0.5); !math.IsNaN(got) {
+		t.Errorf("quantile(nil, 0.5) = %v, want NaN", got)
+	}
+}
+
+func TestKDE(t *testing.T) {
+	tests := []struct {
+		name        string
+		sorted      []float64
+		points      int
+		bandwidth   float64
+		wantXs      []float64
+		wantDensity []float64
+	}{
+		{"gaussian", []float64{0, 2}, 3, 1, []float64{0, 1, 2}, []float64{0.2264666, 0.2419707, 0.2264666}},
+		{"constant", []float64{4, 4}, 2, 0, []float64{4, 4}, []float64{1, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			xs, density := kde(tt.sorted, tt.points, tt.bandwidth)
+			if !reflect.DeepEqual(xs, tt.wantXs) {
+				t.Errorf("kde() xs = %v, want %v", xs, tt.wantXs)
+			}
+			if len(density) != len(tt.wantDensity) {
+				t.Fatalf("kde() density = %v, want %v", density, tt.wantDensity)
+			}
+			for i := range density {
+				if math.Abs(density[i]-tt.wantDensity[i]) > 1e-6 {
+					t.Errorf("kde() density = %v, want %v", density, tt.wantDensity)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestSilvermanBandwidth(t *testing.T) {
+	got := silvermanBandwidth([]float64{1, 2, 3, 4, 5, 6, 7, 8})
+	if want := 1.4544545; math.Abs(got-want) > 1e-6 {
+		t.Errorf("silvermanBandwidth() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,203 @@
+package charts
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// missingValue is how ECharts marks a point with no data, breaking lines.
+const missingValue = "-"
+
+// maxTimeBuckets caps the points of each series on a time axis once
+// resampling fills in the empty buckets, so that two far apart dates cannot
+// ask for millions of points.
+const maxTimeBuckets = defaultScatterPoints
+
+// seriesValues returns the data of each series ready for the chart's
+// category axis: plain numbers, or [millis, value] pairs when t turns the
+// axis into a time axis.
+func seriesValues(req ChartRequest, t *TimeOptions) ([][]interface{}, error) {
+	if t == nil {
+		values := make([][]interface{}, len(req.Series))
+		for j, series := range req.Series {
+			values[j] = make([]interface{}, len(series.Data))
+			for i, v := range series.Data {
+				values[j][i] = v
+			}
+		}
+		return values, nil
+	}
+	return timeValues(req, t)
+}
+
+// categoryAxisType is the type of the axis that holds XAxisData.
+func categoryAxisType(t *TimeOptions) string {
+	if t != nil {
+		return "time"
+	}
+	return "category"
+}
+
+// timeValues parses XAxisData as dates, aggregates the values of each
+// series per timestamp or per resampling bucket, and returns them sorted by
+// time with gaps handled as t asks.
+func timeValues(req ChartRequest, t *TimeOptions) ([][]interface{}, error) {
+	buckets := make(map[int64][][]float64)
+	for i, s := range req.XAxisData {
+		at, ok := ParseDate(s)
+		if !ok {
+			return nil, &OptionsError{ChartType: req.Type, Fields: []FieldError{
+				{Field: fmt.Sprintf("xAxisData[%d]", i), Message: fmt.Sprintf("%q is not a date", s)},
+			}}
+		}
+		key := bucketStart(at, t.Resample).UnixMilli()
+		if _, ok := buckets[key]; !ok {
+			buckets[key] = make([][]float64, len(req.Series))
+		}
+		for j, series := range req.Series {
+			if i < len(series.Data) {
+				buckets[key][j] = append(buckets[key][j], series.Data[i])
+			}
+		}
+	}
+
+	keys := make([]int64, 0, len(buckets))
+	for key := range buckets {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(a, b int) bool { return keys[a] < keys[b] })
+	gaps, ok := gapPoints(keys, t, max(maxTimeBuckets-len(keys), 0))
+	if !ok {
+		return nil, &OptionsError{ChartType: req.Type, Fields: []FieldError{
+			{Field: "options.time.resample", Message: fmt.Sprintf("makes more than %d points; pick a longer period", maxTimeBuckets)},
+		}}
+	}
+
+	values := make([][]interface{}, len(req.Series))
+	for j := range req.Series {
+		points := make([]interface{}, 0, len(keys)+len(gaps))
+		g := 0
+		for _, key := range keys {
+			for ; g < len(gaps) && gaps[g] < key; g++ {
+				points = append(points, []interface{}{gaps[g], gapValue(t)})
+			}
+			if vs := buckets[key][j]; len(vs) > 0 {
+				points = append(points, []interface{}{key, aggregate(vs, t.Aggregate)})
+			} else {
+				points = append(points, []interface{}{key, missingValue})
+			}
+		}
+		values[j] = points
+	}
+	return values, nil
+}
+
+// gapPoints returns the timestamps at which gaps between keys are marked.
+// With resampling these are the empty buckets. Without it, any interval
+// more than twice the median interval gets a point at its midpoint. It
+// reports false when there would be more than limit empty buckets.
+func gapPoints(keys []int64, t *TimeOptions, limit int) ([]int64, bool) {
+	if len(keys) < 2 || t.Gaps == "connect" || (t.Gaps == "" && t.Resample == "") {
+		return nil, true
+	}
+
+	var gaps []int64
+	if t.Resample != "" {
+		for i := 1; i < len(keys); i++ {
+			prev := time.UnixMilli(keys[i-1]).UTC()
+			for next := nextBucket(prev, t.Resample); next.UnixMilli() < keys[i]; next = nextBucket(next, t.Resample) {
+				if len(gaps) == limit {
+					return nil, false
+				}
+				gaps = append(gaps, next.UnixMilli())
+			}
+		}
+		return gaps, true
+	}
+
+	intervals := make([]float64, len(keys)-1)
+	for i := 1; i < len(keys); i++ {
+		intervals[i-1] = float64(keys[i] - keys[i-1])
+	}
+	median := quantile(sortedCopy(intervals), 0.5)
+	for i := 1; i < len(keys); i++ {
+		if float64(keys[i]-keys[i-1]) > 2*median {
+			gaps = append(gaps, keys[i-1]+(keys[i]-keys[i-1])/2)
+		}
+	}
+	return gaps, true
+}
+
+func gapValue(t *TimeOptions) interface{} {
+	if t.Gaps == "zero" {
+		return 0.0
+	}
+	return missingValue
+}
+
+// bucketStart truncates at to the start of its resampling bucket. Weeks
+// start on Monday.
+func bucketStart(at time.Time, resample string) time.Time {
+	y, m, d := at.Date()
+	switch resample {
+	case "day":
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	case "week":
+		offset := (int(at.Weekday()) + 6) % 7
+		return time.Date(y, m, d-offset, 0, 0, 0, 0, time.UTC)
+	case "month":
+		return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+	case "quarter":
+		return time.Date(y, m-(m-1)%3, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return at
+	}
+}
+
+func nextBucket(start time.Time, resample string) time.Time {
+	switch resample {
+	case "day":
+		return start.AddDate(0, 0, 1)
+	case "week":
+		return start.AddDate(0, 0, 7)
+	case "month":
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 3, 0)
+	}
+}
+
+// aggregate combines the values of one bucket, summing by default.
+func aggregate(values []float64, fn string) float64 {
+	switch fn {
+	case "mean":
+		mean, _ := meanStdDev(values)
+		return mean
+	case "min":
+		result := values[0]
+		for _, v := range values[1:] {
+			result = math.Min(result, v)
+		}
+		return result
+	case "max":
+		result := values[0]
+		for _, v := range values[1:] {
+			result = math.Max(result, v)
+		}
+		return result
+	case "count":
+		return float64(len(values))
+	case "first":
+		return values[0]
+	case "last":
+		return values[len(values)-1]
+	default:
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum
+	}
+}
@@ -0,0 +1,194 @@
+package charts
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// millis returns the Unix milliseconds of date, a day in 2006-01-02 form.
+func millis(t *testing.T, date string) int64 {
+	t.Helper()
+	at, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return at.UnixMilli()
+}
+
+func TestBucketStart(t *testing.T) {
+	tests := []struct {
+		in, resample, want string
+	}{
+		{"2024-01-01T13:30:00Z", "day", "2024-01-01T00:00:00Z"},
+		{"2024-01-01T13:30:00Z", "", "2024-01-01T13:30:00Z"},
+		{"2024-01-07T00:00:00Z", "week", "2024-01-01T00:00:00Z"},
+		{"2024-01-08T00:00:00Z", "week", "2024-01-08T00:00:00Z"},
+		{"2024-01-03T00:00:00Z", "week", "2024-01-01T00:00:00Z"},
+		{"2023-12-31T00:00:00Z", "week", "2023-12-25T00:00:00Z"},
+		{"2024-02-29T10:00:00Z", "month", "2024-02-01T00:00:00Z"},
+		{"2024-03-31T00:00:00Z", "quarter", "2024-01-01T00:00:00Z"},
+		{"2024-04-01T00:00:00Z", "quarter", "2024-04-01T00:00:00Z"},
+		{"2024-12-31T23:59:59Z", "quarter", "2024-10-01T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resample+" "+tt.in, func(t *testing.T) {
+			at, err := time.Parse(time.RFC3339, tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bucketStart(at, tt.resample).Format(time.RFC3339); got != tt.want {
+				t.Errorf("bucketStart(%s, %q) = %s, want %s", tt.in, tt.resample, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextBucket(t *testing.T) {
+	tests := []struct {
+		start, resample, want string
+	}{
+		{"2024-02-28", "day", "2024-02-29"},
+		{"2024-12-30", "week", "2025-01-06"},
+		{"2024-01-01", "month", "2024-02-01"},
+		{"2024-10-01", "quarter", "2025-01-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resample, func(t *testing.T) {
+			start, err := time.Parse("2006-01-02", tt.start)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := nextBucket(start, tt.resample).Format("2006-01-02"); got != tt.want {
+				t.Errorf("nextBucket(%s, %q) = %s, want %s", tt.start, tt.resample, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	values := []float64{3, 1, 2}
+	tests := []struct {
+		fn   string
+		want float64
+	}{
+		{"", 6},
+		{"sum", 6},
+		{"mean", 2},
+		{"min", 1},
+		{"max", 3},
+		{"count", 3},
+		{"first", 3},
+		{"last", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fn, func(t *testing.T) {
+			if got := aggregate(values, tt.fn); got != tt.want {
+				t.Errorf("aggregate(%v, %q) = %v, want %v", values, tt.fn, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGapPoints(t *testing.T) {
+	const day = int64(24 * time.Hour / time.Millisecond)
+	months := []int64{millis(t, "2024-01-01"), millis(t, "2024-04-01")}
+	emptyMonths := []int64{millis(t, "2024-02-01"), millis(t, "2024-03-01")}
+	tests := []struct {
+		name  string
+		keys  []int64
+		time  TimeOptions
+		limit int
+		want  []int64
+		ok    bool
+	}{
+		{"resampled breaks by default", months, TimeOptions{Resample: "month"}, 10, emptyMonths, true},
+		{"resampled zero", months, TimeOptions{Resample: "month", Gaps: "zero"}, 10, emptyMonths, true},
+		{"resampled connect", months, TimeOptions{Resample: "month", Gaps: "connect"}, 10, nil, true},
+		{"resampled without gaps", []int64{millis(t, "2024-01-01"), millis(t, "2024-01-08")}, TimeOptions{Resample: "week"}, 10, nil, true},
+		{"over the limit", []int64{millis(t, "2024-01-01"), millis(t, "2024-01-10")}, TimeOptions{Resample: "day"}, 3, nil, false},
+		{"raw kept by default", []int64{0, day, 2 * day, 9 * day}, TimeOptions{}, 10, nil, true},
+		{"raw break", []int64{0, day, 2 * day, 9 * day}, TimeOptions{Gaps: "break"}, 10, []int64{2*day + 7*day/2}, true},
+		{"raw connect", []int64{0, day, 2 * day, 9 * day}, TimeOptions{Gaps: "connect"}, 10, nil, true},
+		{"single key", []int64{0}, TimeOptions{Resample: "day"}, 10, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := gapPoints(tt.keys, &tt.time, tt.limit)
+			if !reflect.DeepEqual(got, tt.want) || ok != tt.ok {
+				t.Errorf("gapPoints() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestTimeValues(t *testing.T) {
+	jan, feb, mar := millis(t, "2024-01-01"), millis(t, "2024-02-01"), millis(t, "2024-03-01")
+	req := ChartRequest{
+		Type:      "line",
+		XAxisData: []string{"2024-03-15", "2024-01-20", "2024-01-10"},
+		Series: []SeriesData{
+			{Name: "sales", Data: []float64{1, 2, 3}},
+			{Name: "cost", Data: []float64{7}},
+		},
+	}
+	tests := []struct {
+		name string
+		time TimeOptions
+		want [][]interface{}
+	}{
+		{"break", TimeOptions{Resample: "month"}, [][]interface{}{
+			{[]interface{}{jan, 5.0}, []interface{}{feb, missingValue}, []interface{}{mar, 1.0}},
+			{[]interface{}{jan, missingValue}, []interface{}{feb, missingValue}, []interface{}{mar, 7.0}},
+		}},
+		{"zero", TimeOptions{Resample: "month", Gaps: "zero", Aggregate: "max"}, [][]interface{}{
+			{[]interface{}{jan, 3.0}, []interface{}{feb, 0.0}, []interface{}{mar, 1.0}},
+			{[]interface{}{jan, missingValue}, []interface{}{feb, 0.0}, []interface{}{mar, 7.0}},
+		}},
+		{"connect", TimeOptions{Resample: "month", Gaps: "connect", Aggregate: "last"}, [][]interface{}{
+			{[]interface{}{jan, 3.0}, []interface{}{mar, 1.0}},
+			{[]interface{}{jan, missingValue}, []interface{}{mar, 7.0}},
+		}},
+		{"sorted without resampling", TimeOptions{}, [][]interface{}{
+			{[]interface{}{millis(t, "2024-01-10"), 3.0}, []interface{}{millis(t, "2024-01-20"), 2.0}, []interface{}{millis(t, "2024-03-15"), 1.0}},
+			{[]interface{}{millis(t, "2024-01-10"), missingValue}, []interface{}{millis(t, "2024-01-20"), missingValue}, []interface{}{millis(t, "2024-03-15"), 7.0}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := timeValues(req, &tt.time)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("timeValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeValuesErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		dates     []string
+		time      TimeOptions
+		wantField string
+	}{
+		{"not a date", []string{"2024-01-01", "soon"}, TimeOptions{}, "xAxisData[1]"},
+		{"too many buckets", []string{"0001-01-01", "2024-01-01"}, TimeOptions{Resample: "day"}, "options.time.resample"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := ChartRequest{Type: "line", XAxisData: tt.dates, Series: []SeriesData{{Name: "s", Data: []float64{1, 2}}}}
+			_, err := timeValues(req, &tt.time)
+			var optsErr *OptionsError
+			if !errors.As(err, &optsErr) {
+				t.Fatalf("timeValues() error = %v, want an OptionsError", err)
+			}
+			if len(optsErr.Fields) != 1 || optsErr.Fields[0].Field != tt.wantField {
+				t.Errorf("timeValues() fields = %+v, want one on %s", optsErr.Fields, tt.wantField)
+			}
+		})
+	}
+}
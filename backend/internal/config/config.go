@@ -10,6 +10,22 @@ type Config struct {
 	FirebaseProjectID   string
 	FirebaseCredentials string
 	AllowedOrigins      []string
+	GazetteerPath       string
+	// Storage is "firebase" to verify Firebase ID tokens and keep
+	// dashboards in Firestore, or "local" to run without Google services,
+	// keeping users, dashboards and cached sheets in the embedded database
+	// at DatabasePath. It defaults to "firebase" when Firebase credentials
+	// are set.
+	Storage      string
+	DatabasePath string
+	// AuthSecret signs local session tokens; one is generated and kept in
+	// the database when unset.
+	AuthSecret string
+	// ShareSecret signs share link tokens. With local storage one is
+	// generated and kept in the database when unset; with Firebase an
+	// unset secret is generated on each start, so links stop working on
+	// restart.
+	ShareSecret string
 }
 
 func Load() *Config {
@@ -21,6 +37,18 @@ func Load() *Config {
 	firebaseProjectID := os.Getenv("FIREBASE_PROJECT_ID")
 	firebaseCredentials := os.Getenv("FIREBASE_CREDENTIALS_JSON")
 
+	storage := os.Getenv("STORAGE")
+	if storage == "" {
+		storage = "local"
+		if firebaseCredentials != "" {
+			storage = "firebase"
+		}
+	}
+	databasePath := os.Getenv("DATABASE_PATH")
+	if databasePath == "" {
+		databasePath = "loomis.db"
+	}
+
 	allowedOrigins := []string{
 		"http://localhost:5173",
 		"http://localhost:3000",
@@ -34,8 +62,13 @@ func Load() *Config {
 		FirebaseProjectID:   firebaseProjectID,
 		FirebaseCredentials: firebaseCredentials,
 		AllowedOrigins:      allowedOrigins,
+		GazetteerPath:       os.Getenv("GAZETTEER_PATH"),
+		Storage:             storage,
+		DatabasePath:        databasePath,
+		AuthSecret:          os.Getenv("AUTH_SECRET"),
+		ShareSecret:         os.Getenv("SHARE_SECRET"),
 	}
 
-	log.Printf("Configuration loaded: Port=%s, Project=%s", config.Port, config.FirebaseProjectID)
+	log.Printf("Configuration loaded: Port=%s, Storage=%s, Project=%s", config.Port, config.Storage, config.FirebaseProjectID)
 	return config
 }
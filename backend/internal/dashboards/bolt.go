@@ -0,0 +1,179 @@
+package dashboards
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mjrtuhin/loomis-backend/internal/storage"
+)
+
+const (
+	// bucket holds dashboards in the embedded database, keyed by ID.
+	bucket = "dashboards"
+	// revisionsBucket holds revisions keyed by dashboard ID and zero-padded
+	// number, so that a dashboard's revisions are adjacent and in order.
+	revisionsBucket = "dashboardRevisions"
+)
+
+func revisionKey(id string, number int) string {
+	return fmt.Sprintf("%s/%010d", id, number)
+}
+
+type boltStore struct {
+	db *storage.DB
+}
+
+// NewBoltStore creates a Store in the embedded database db.
+func NewBoltStore(db *storage.DB) Store {
+	return &boltStore{db: db}
+}
+
+func (s *boltStore) List(_ context.Context, userID string) ([]Dashboard, error) {
+	var out []Dashboard
+	err := s.db.View(func(tx *storage.Tx) error {
+		return tx.ForEach(bucket, func(_ string, raw json.RawMessage) error {
+			var d Dashboard
+			if err := json.Unmarshal(raw, &d); err != nil {
+				return err
+			}
+			if d.UserID == userID {
+				out = append(out, d)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortByUpdated(out)
+	return out, nil
+}
+
+func (s *boltStore) Get(_ context.Context, id string) (*Dashboard, error) {
+	var d Dashboard
+	found, err := s.db.Get(bucket, id, &d)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrNotFound
+	}
+	return &d, nil
+}
+
+func (s *boltStore) Create(_ context.Context, d Dashboard) (*Dashboard, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	d.ID = id
+	rev := created(&d)
+	err = s.db.Update(func(tx *storage.Tx) error {
+		if err := tx.Put(bucket, id, d); err != nil {
+			return err
+		}
+		return tx.Put(revisionsBucket, revisionKey(id, rev.Number), rev)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (s *boltStore) Update(_ context.Context, d Dashboard) (*Dashboard, error) {
+	err := s.db.Update(func(tx *storage.Tx) error {
+		var prev Dashboard
+		found, err := tx.Get(bucket, d.ID, &prev)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrNotFound
+		}
+		rev := updated(&prev, &d)
+		if err := tx.Put(bucket, d.ID, d); err != nil {
+			return err
+		}
+		return tx.Put(revisionsBucket, revisionKey(d.ID, rev.Number), rev)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (s *boltStore) Delete(_ context.Context, id string) error {
+	return s.db.Update(func(tx *storage.Tx) error {
+		found, err := tx.Delete(bucket, id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrNotFound
+		}
+		var keys []string
+		err = tx.ForEachPrefix(revisionsBucket, id+"/", func(key string, _ json.RawMessage) error {
+			keys = append(keys, key)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if _, err := tx.Delete(revisionsBucket, key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *boltStore) Revisions(_ context.Context, id string) ([]Revision, error) {
+	var out []Revision
+	err := s.db.View(func(tx *storage.Tx) error {
+		var d Dashboard
+		found, err := tx.Get(bucket, id, &d)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrNotFound
+		}
+		return tx.ForEachPrefix(revisionsBucket, id+"/", func(_ string, raw json.RawMessage) error {
+			var r Revision
+			if err := json.Unmarshal(raw, &r); err != nil {
+				return err
+			}
+			out = append(out, r)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summaries(out), nil
+}
+
+func (s *boltStore) Revision(_ context.Context, id string, number int) (*Revision, error) {
+	var r Revision
+	err := s.db.View(func(tx *storage.Tx) error {
+		var d Dashboard
+		found, err := tx.Get(bucket, id, &d)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrNotFound
+		}
+		found, err = tx.Get(revisionsBucket, revisionKey(id, number), &r)
+		if err == nil && !found {
+			return ErrRevisionNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,146 @@
+package dashboards
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxRefreshInterval is the longest refresh interval, in minutes.
+const maxRefreshInterval = 1440
+
+// Dashboard is a saved canvas of charts and text blocks fed by a Google
+// Sheet. Field names follow the documents the frontend has been writing
+// to Firestore, so that existing dashboards load unchanged; chart configs
+// are stored there as JSON text, which the frontend's reader decodes.
+type Dashboard struct {
+	ID             string `json:"id" firestore:"-"`
+	UserID         string `json:"userId" firestore:"userId"`
+	GoogleSheetURL string `json:"googleSheetUrl" firestore:"googleSheetUrl"`
+	// RefreshInterval is how often the sheet is re-read, in minutes.
+	RefreshInterval int    `json:"refreshInterval" firestore:"refreshInterval"`
+	Layout          Layout `json:"layout" firestore:"layout"`
+	// BrandTheme is the ID of the brand theme the dashboard's charts are
+	// generated with, if any.
+	BrandTheme string    `json:"brandTheme,omitempty" firestore:"brandTheme,omitempty"`
+	CreatedAt  time.Time `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt" firestore:"updatedAt"`
+	// UpdatedBy is the user ID of whoever saved the dashboard last.
+	UpdatedBy string `json:"updatedBy" firestore:"updatedBy"`
+	// Revision is the number of the dashboard's latest revision.
+	Revision int `json:"revision" firestore:"revision"`
+}
+
+// Layout places the items of a dashboard on its canvas.
+type Layout struct {
+	Charts     []ChartItem `json:"charts" firestore:"charts"`
+	TextBlocks []TextBlock `json:"textBlocks" firestore:"textBlocks"`
+}
+
+// ChartItem is a chart on the canvas. ChartConfig is the ECharts option
+// object last generated for it.
+type ChartItem struct {
+	ID          string                 `json:"id" firestore:"id"`
+	Type        string                 `json:"type" firestore:"type"`
+	ChartType   string                 `json:"chartType" firestore:"chartType"`
+	Position    Position               `json:"position" firestore:"position"`
+	ChartConfig map[string]interface{} `json:"chartConfig,omitempty" firestore:"chartConfig,omitempty"`
+}
+
+// TextBlock is a block of text on the canvas.
+type TextBlock struct {
+	ID       string   `json:"id" firestore:"id"`
+	Type     string   `json:"type" firestore:"type"`
+	Content  string   `json:"content" firestore:"content"`
+	Position Position `json:"position" firestore:"position"`
+}
+
+// Position is the box of an item on the canvas, in pixels.
+type Position struct {
+	X float64 `json:"x" firestore:"x"`
+	Y float64 `json:"y" firestore:"y"`
+	W float64 `json:"w" firestore:"w"`
+	H float64 `json:"h" firestore:"h"`
+}
+
+// FieldError describes one invalid field of a dashboard.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when a dashboard is malformed.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + ": " + f.Message
+	}
+	return "invalid dashboard: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks the sheet, refresh interval and layout of d, returning a
+// *ValidationError listing every problem. Missing item types are filled
+// in.
+func (d *Dashboard) Validate() error {
+	var errs []FieldError
+	fail := func(field, message string) {
+		errs = append(errs, FieldError{Field: field, Message: message})
+	}
+
+	if u, err := url.Parse(d.GoogleSheetURL); d.GoogleSheetURL == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		fail("googleSheetUrl", "must be an http(s) URL")
+	}
+	if d.RefreshInterval < 1 || d.RefreshInterval > maxRefreshInterval {
+		fail("refreshInterval", fmt.Sprintf("must be between 1 and %d minutes", maxRefreshInterval))
+	}
+
+	ids := make(map[string]bool)
+	item := func(field, id string, p Position) {
+		switch {
+		case id == "":
+			fail(field+".id", "is required")
+		case ids[id]:
+			fail(field+".id", fmt.Sprintf("%q is used by another item", id))
+		}
+		ids[id] = true
+		if p.X < 0 || p.Y < 0 {
+			fail(field+".position", "must not be negative")
+		}
+		if p.W <= 0 || p.H <= 0 {
+			fail(field+".position", "width and height must be positive")
+		}
+	}
+	for i := range d.Layout.Charts {
+		c := &d.Layout.Charts[i]
+		field := fmt.Sprintf("layout.charts[%d]", i)
+		item(field, c.ID, c.Position)
+		if c.Type == "" {
+			c.Type = "chart"
+		} else if c.Type != "chart" {
+			fail(field+".type", `must be "chart"`)
+		}
+		if c.ChartType == "" {
+			fail(field+".chartType", "is required")
+		}
+	}
+	for i := range d.Layout.TextBlocks {
+		b := &d.Layout.TextBlocks[i]
+		field := fmt.Sprintf("layout.textBlocks[%d]", i)
+		item(field, b.ID, b.Position)
+		if b.Type == "" {
+			b.Type = "text"
+		} else if b.Type != "text" {
+			fail(field+".type", `must be "text"`)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Fields: errs}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package dashboards
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mjrtuhin/loomis-backend/internal/themes"
+)
+
+const (
+	// DocumentFormat identifies exported dashboard documents.
+	DocumentFormat = "loomis.dashboard"
+	// DocumentVersion is the version of the document format written by
+	// Export. Import upgrades older documents through migrations.
+	DocumentVersion = 2
+)
+
+// Document is a dashboard exported as a self-contained JSON file that can
+// be kept in version control and imported into any account. It leaves out
+// IDs, owners, timestamps and revisions, so exporting the same dashboard
+// twice gives the same file.
+//
+// Version 1 documents are dashboards as stored by the frontend and served
+// by GET /api/dashboards/:id, without a format field.
+type Document struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	// RefreshInterval is how often the data sources are re-read, in
+	// minutes.
+	RefreshInterval int          `json:"refreshInterval"`
+	DataSources     []DataSource `json:"dataSources"`
+	Layout          Layout       `json:"layout"`
+	// Theme is the brand theme the charts are generated with, embedded so
+	// that it can be recreated on import.
+	Theme *DocumentTheme `json:"theme,omitempty"`
+}
+
+// DataSource is a reference to the data a dashboard reads. Dashboards have
+// a single Google Sheet for now; the list leaves room for more.
+type DataSource struct {
+	ID string `json:"id"`
+	// Type is "google-sheet".
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// DocumentTheme is a brand theme embedded in a document.
+type DocumentTheme struct {
+	Name       string            `json:"name"`
+	Definition themes.Definition `json:"definition"`
+}
+
+const (
+	// sheetSource is the ID of a dashboard's Google Sheet data source.
+	sheetSource = "sheet"
+	sheetType   = "google-sheet"
+	// defaultRefreshInterval is the refresh interval the frontend gives new
+	// dashboards, used for version 1 documents without one.
+	defaultRefreshInterval = 60
+)
+
+// ErrInvalidDocument is returned for documents that cannot be imported.
+var ErrInvalidDocument = errors.New("invalid dashboard document")
+
+// migrations upgrade a decoded document from the version it is keyed by to
+// the next one.
+var migrations = map[int]func(doc map[string]interface{}) error{
+	1: migrateV1,
+}
+
+// migrateV1 turns a stored dashboard into a version 2 document: the sheet
+// becomes a data source, and the fields tied to the original account are
+// dropped. A brand theme ID is dropped too, as version 1 has no theme
+// definition to recreate it from.
+func migrateV1(doc map[string]interface{}) error {
+	sheet, _ := doc["googleSheetUrl"].(string)
+	doc["dataSources"] = []interface{}{
+		map[string]interface{}{"id": sheetSource, "type": sheetType, "url": sheet},
+	}
+	if _, ok := doc["refreshInterval"]; !ok {
+		doc["refreshInterval"] = defaultRefreshInterval
+	}
+	for _, k := range []string{"id", "userId", "googleSheetUrl", "brandTheme", "createdAt", "updatedAt", "updatedBy", "revision"} {
+		delete(doc, k)
+	}
+	doc["format"] = DocumentFormat
+	return nil
+}
+
+// Export builds the document for d, embedding theme when it is not nil.
+func Export(d *Dashboard, theme *themes.Theme) Document {
+	doc := Document{
+		Format:          DocumentFormat,
+		Version:         DocumentVersion,
+		RefreshInterval: d.RefreshInterval,
+		DataSources:     []DataSource{{ID: sheetSource, Type: sheetType, URL: d.GoogleSheetURL}},
+		Layout:          d.Layout,
+	}
+	if theme != nil {
+		doc.Theme = &DocumentTheme{Name: theme.Name, Definition: theme.Definition}
+	}
+	return doc
+}
+
+// ParseDocument decodes a document of any supported version, upgrading it
+// to the current one.
+func ParseDocument(raw []byte) (*Document, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil || doc == nil {
+		return nil, fmt.Errorf("%w: not a JSON object", ErrInvalidDocument)
+	}
+
+	version := 1
+	if format, ok := doc["format"]; ok {
+		if format != DocumentFormat {
+			return nil, fmt.Errorf("%w: format must be %q", ErrInvalidDocument, DocumentFormat)
+		}
+		v, ok := doc["version"].(float64)
+		if !ok || v != float64(int(v)) || v < 1 {
+			return nil, fmt.Errorf("%w: version must be a positive integer", ErrInvalidDocument)
+		}
+		version = int(v)
+	} else if _, ok := doc["layout"]; !ok {
+		return nil, fmt.Errorf("%w: not a dashboard", ErrInvalidDocument)
+	}
+	if version > DocumentVersion {
+		return nil, fmt.Errorf("%w: version %d is newer than the supported version %d", ErrInvalidDocument, version, DocumentVersion)
+	}
+	for ; version < DocumentVersion; version++ {
+		if err := migrations[version](doc); err != nil {
+			return nil, err
+		}
+		doc["version"] = version + 1
+	}
+
+	upgraded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var out Document
+	dec := json.NewDecoder(bytes.NewReader(upgraded))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	return &out, nil
+}
+
+// Dashboard returns the dashboard described by doc, without an owner. The
+// brand theme, if any, is left for the caller to recreate.
+func (doc *Document) Dashboard() (Dashboard, error) {
+	d := Dashboard{RefreshInterval: doc.RefreshInterval, Layout: doc.Layout}
+	var errs []FieldError
+	switch {
+	case len(doc.DataSources) != 1:
+		errs = append(errs, FieldError{Field: "dataSources", Message: "must hold exactly one data source"})
+	case doc.DataSources[0].Type != sheetType:
+		errs = append(errs, FieldError{Field: "dataSources[0].type", Message: fmt.Sprintf("must be %q", sheetType)})
+	default:
+		d.GoogleSheetURL = doc.DataSources[0].URL
+	}
+	if err := d.Validate(); err != nil {
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			return Dashboard{}, err
+		}
+		for _, f := range validationErr.Fields {
+			if f.Field == "googleSheetUrl" {
+				if errs != nil {
+					continue
+				}
+				f.Field = "dataSources[0].url"
+			}
+			errs = append(errs, f)
+		}
+	}
+	if errs != nil {
+		return Dashboard{}, &ValidationError{Fields: errs}
+	}
+	return d, nil
+}
@@ -0,0 +1,287 @@
+package dashboards
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// collection is the Firestore collection the frontend has been writing
+	// dashboards to.
+	collection = "dashboards"
+	// revisionsCollection is the subcollection of a dashboard document
+	// holding its revisions, keyed by number.
+	revisionsCollection = "revisions"
+)
+
+// firestoreRevision is a Revision as stored in Firestore. Changes and
+// snapshots are kept as JSON text, as Firestore rejects the nested arrays
+// chart configs often hold.
+type firestoreRevision struct {
+	Number    int       `firestore:"number"`
+	Author    string    `firestore:"author"`
+	CreatedAt time.Time `firestore:"createdAt"`
+	Changes   string    `firestore:"changes"`
+	Snapshot  string    `firestore:"snapshot"`
+}
+
+func toFirestoreRevision(r Revision) (firestoreRevision, error) {
+	changes, err := json.Marshal(r.Changes)
+	if err != nil {
+		return firestoreRevision{}, err
+	}
+	snapshot, err := json.Marshal(r.Snapshot)
+	if err != nil {
+		return firestoreRevision{}, err
+	}
+	return firestoreRevision{Number: r.Number, Author: r.Author, CreatedAt: r.CreatedAt, Changes: string(changes), Snapshot: string(snapshot)}, nil
+}
+
+func fromFirestoreRevision(doc *firestore.DocumentSnapshot, withSnapshot bool) (*Revision, error) {
+	var fr firestoreRevision
+	if err := doc.DataTo(&fr); err != nil {
+		return nil, fmt.Errorf("failed to read revision %s: %w", doc.Ref.Path, err)
+	}
+	r := Revision{Number: fr.Number, Author: fr.Author, CreatedAt: fr.CreatedAt}
+	if err := json.Unmarshal([]byte(fr.Changes), &r.Changes); err != nil {
+		return nil, fmt.Errorf("failed to read revision %s: %w", doc.Ref.Path, err)
+	}
+	if withSnapshot {
+		if err := json.Unmarshal([]byte(fr.Snapshot), &r.Snapshot); err != nil {
+			return nil, fmt.Errorf("failed to read revision %s: %w", doc.Ref.Path, err)
+		}
+	}
+	return &r, nil
+}
+
+// firestoreDashboard is a Dashboard as stored in Firestore. Chart configs
+// are kept as JSON text, like revisions; those of dashboards the frontend
+// wrote are maps, which are still read.
+type firestoreDashboard struct {
+	Dashboard
+	Layout firestoreLayout `firestore:"layout"`
+}
+
+type firestoreLayout struct {
+	Charts     []firestoreChartItem `firestore:"charts"`
+	TextBlocks []TextBlock          `firestore:"textBlocks"`
+}
+
+type firestoreChartItem struct {
+	ID        string   `firestore:"id"`
+	Type      string   `firestore:"type"`
+	ChartType string   `firestore:"chartType"`
+	Position  Position `firestore:"position"`
+	// ChartConfig is JSON text, or a map in older documents.
+	ChartConfig interface{} `firestore:"chartConfig,omitempty"`
+}
+
+func toFirestoreDashboard(d Dashboard) (firestoreDashboard, error) {
+	fd := firestoreDashboard{Dashboard: d, Layout: firestoreLayout{TextBlocks: d.Layout.TextBlocks}}
+	if d.Layout.Charts != nil {
+		fd.Layout.Charts = make([]firestoreChartItem, 0, len(d.Layout.Charts))
+	}
+	for _, item := range d.Layout.Charts {
+		fi := firestoreChartItem{ID: item.ID, Type: item.Type, ChartType: item.ChartType, Position: item.Position}
+		if item.ChartConfig != nil {
+			config, err := json.Marshal(item.ChartConfig)
+			if err != nil {
+				return firestoreDashboard{}, err
+			}
+			fi.ChartConfig = string(config)
+		}
+		fd.Layout.Charts = append(fd.Layout.Charts, fi)
+	}
+	return fd, nil
+}
+
+type firestoreStore struct {
+	client *firestore.Client
+}
+
+// NewFirestoreStore creates a Store on the dashboards collection of the
+// Firebase project. An empty projectID is detected from the credentials.
+func NewFirestoreStore(ctx context.Context, projectID, credentialsJSON string) (Store, error) {
+	if projectID == "" {
+		projectID = firestore.DetectProjectID
+	}
+	client, err := firestore.NewClient(ctx, projectID, option.WithCredentialsJSON([]byte(credentialsJSON)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Firestore: %w", err)
+	}
+	return &firestoreStore{client: client}, nil
+}
+
+func (s *firestoreStore) List(ctx context.Context, userID string) ([]Dashboard, error) {
+	docs, err := s.client.Collection(collection).Where("userId", "==", userID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Dashboard, 0, len(docs))
+	for _, doc := range docs {
+		d, err := fromDoc(doc)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *d)
+	}
+	// Sorted here rather than in the query, which would need a composite
+	// index on userId and updatedAt.
+	sortByUpdated(out)
+	return out, nil
+}
+
+func (s *firestoreStore) Get(ctx context.Context, id string) (*Dashboard, error) {
+	doc, err := s.client.Collection(collection).Doc(id).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fromDoc(doc)
+}
+
+func (s *firestoreStore) Create(ctx context.Context, d Dashboard) (*Dashboard, error) {
+	ref := s.client.Collection(collection).NewDoc()
+	d.ID = ref.ID
+	rev, err := toFirestoreRevision(created(&d))
+	if err != nil {
+		return nil, err
+	}
+	fd, err := toFirestoreDashboard(d)
+	if err != nil {
+		return nil, err
+	}
+	batch := s.client.Batch()
+	batch.Create(ref, fd)
+	batch.Create(ref.Collection(revisionsCollection).Doc(strconv.Itoa(rev.Number)), rev)
+	if _, err := batch.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (s *firestoreStore) Update(ctx context.Context, d Dashboard) (*Dashboard, error) {
+	ref := s.client.Collection(collection).Doc(d.ID)
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		prev, err := fromDoc(doc)
+		if err != nil {
+			return err
+		}
+		rev, err := toFirestoreRevision(updated(prev, &d))
+		if err != nil {
+			return err
+		}
+		fd, err := toFirestoreDashboard(d)
+		if err != nil {
+			return err
+		}
+		if err := tx.Set(ref, fd); err != nil {
+			return err
+		}
+		// Creating rather than setting the revision fails the transaction
+		// if a concurrent save took the same number.
+		return tx.Create(ref.Collection(revisionsCollection).Doc(strconv.Itoa(rev.Number)), rev)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (s *firestoreStore) Delete(ctx context.Context, id string) error {
+	ref := s.client.Collection(collection).Doc(id)
+	if _, err := ref.Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return ErrNotFound
+		}
+		return err
+	}
+	revisions, err := ref.Collection(revisionsCollection).DocumentRefs(ctx).GetAll()
+	if err != nil {
+		return err
+	}
+	for _, r := range revisions {
+		if _, err := r.Delete(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *firestoreStore) Revisions(ctx context.Context, id string) ([]Revision, error) {
+	ref := s.client.Collection(collection).Doc(id)
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	docs, err := ref.Collection(revisionsCollection).OrderBy("number", firestore.Asc).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Revision, 0, len(docs))
+	for _, doc := range docs {
+		r, err := fromFirestoreRevision(doc, false)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *r)
+	}
+	return out, nil
+}
+
+func (s *firestoreStore) Revision(ctx context.Context, id string, number int) (*Revision, error) {
+	ref := s.client.Collection(collection).Doc(id)
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	doc, err := ref.Collection(revisionsCollection).Doc(strconv.Itoa(number)).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fromFirestoreRevision(doc, true)
+}
+
+func fromDoc(doc *firestore.DocumentSnapshot) (*Dashboard, error) {
+	var fd firestoreDashboard
+	if err := doc.DataTo(&fd); err != nil {
+		return nil, fmt.Errorf("failed to read dashboard %s: %w", doc.Ref.ID, err)
+	}
+	d := fd.Dashboard
+	d.ID = doc.Ref.ID
+	d.Layout = Layout{TextBlocks: fd.Layout.TextBlocks}
+	if fd.Layout.Charts != nil {
+		d.Layout.Charts = make([]ChartItem, 0, len(fd.Layout.Charts))
+	}
+	for _, fi := range fd.Layout.Charts {
+		item := ChartItem{ID: fi.ID, Type: fi.Type, ChartType: fi.ChartType, Position: fi.Position}
+		switch config := fi.ChartConfig.(type) {
+		case string:
+			if err := json.Unmarshal([]byte(config), &item.ChartConfig); err != nil {
+				return nil, fmt.Errorf("failed to read dashboard %s: %w", doc.Ref.ID, err)
+			}
+		case map[string]interface{}:
+			item.ChartConfig = config
+		}
+		d.Layout.Charts = append(d.Layout.Charts, item)
+	}
+	return &d, nil
+}
@@ -0,0 +1,343 @@
+package dashboards
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjrtuhin/loomis-backend/internal/themes"
+)
+
+// maxImportSize bounds the size of imported documents, in bytes.
+const maxImportSize = 16 << 20
+
+type Handler struct {
+	store       Store
+	brandThemes themes.Store
+}
+
+// NewHandler creates a Handler. brandThemes resolves the themes embedded
+// in exports and saved on import.
+func NewHandler(store Store, brandThemes themes.Store) *Handler {
+	return &Handler{store: store, brandThemes: brandThemes}
+}
+
+// DashboardRequest is the body of dashboard creates and updates.
+type DashboardRequest struct {
+	GoogleSheetURL  string `json:"googleSheetUrl"`
+	RefreshInterval int    `json:"refreshInterval"`
+	Layout          Layout `json:"layout"`
+	BrandTheme      string `json:"brandTheme"`
+}
+
+// List handles GET /api/dashboards, listing the caller's dashboards.
+func (h *Handler) List(c *gin.Context) {
+	list, err := h.store.List(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	if list == nil {
+		list = []Dashboard{}
+	}
+	c.JSON(http.StatusOK, gin.H{"dashboards": list})
+}
+
+// Get handles GET /api/dashboards/:id.
+func (h *Handler) Get(c *gin.Context) {
+	d, ok := h.owned(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, d)
+}
+
+// Create handles POST /api/dashboards.
+func (h *Handler) Create(c *gin.Context) {
+	d, ok := h.bind(c)
+	if !ok {
+		return
+	}
+	d.UserID = c.GetString("userID")
+	d.UpdatedBy = d.UserID
+	saved, err := h.store.Create(c.Request.Context(), d)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, saved)
+}
+
+// Update handles PUT /api/dashboards/:id, replacing the sheet, refresh
+// interval, layout and theme.
+func (h *Handler) Update(c *gin.Context) {
+	current, ok := h.owned(c)
+	if !ok {
+		return
+	}
+	d, ok := h.bind(c)
+	if !ok {
+		return
+	}
+	d.ID, d.UserID, d.UpdatedBy = current.ID, current.UserID, c.GetString("userID")
+	saved, err := h.store.Update(c.Request.Context(), d)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, saved)
+}
+
+// Delete handles DELETE /api/dashboards/:id.
+func (h *Handler) Delete(c *gin.Context) {
+	current, ok := h.owned(c)
+	if !ok {
+		return
+	}
+	if err := h.store.Delete(c.Request.Context(), current.ID); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Revisions handles GET /api/dashboards/:id/revisions, listing the
+// revisions of a dashboard without their snapshots.
+func (h *Handler) Revisions(c *gin.Context) {
+	current, ok := h.owned(c)
+	if !ok {
+		return
+	}
+	revisions, err := h.store.Revisions(c.Request.Context(), current.ID)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+// Revision handles GET /api/dashboards/:id/revisions/:rev.
+func (h *Handler) Revision(c *gin.Context) {
+	current, ok := h.owned(c)
+	if !ok {
+		return
+	}
+	rev, ok := h.revision(c, current.ID, c.Param("rev"))
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, rev)
+}
+
+// Diff handles GET /api/dashboards/:id/diff?from=1&to=3, listing the
+// changes between two revisions. to defaults to the latest revision.
+func (h *Handler) Diff(c *gin.Context) {
+	current, ok := h.owned(c)
+	if !ok {
+		return
+	}
+	from, ok := h.revision(c, current.ID, c.Query("from"))
+	if !ok {
+		return
+	}
+	to, ok := h.revision(c, current.ID, c.DefaultQuery("to", strconv.Itoa(current.Revision)))
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"from":    from.Number,
+		"to":      to.Number,
+		"changes": Diff(from.Snapshot, to.Snapshot),
+	})
+}
+
+// Restore handles POST /api/dashboards/:id/revisions/:rev/restore, saving
+// the sheet, refresh interval, layout and theme of a revision as a new
+// revision.
+func (h *Handler) Restore(c *gin.Context) {
+	current, ok := h.owned(c)
+	if !ok {
+		return
+	}
+	rev, ok := h.revision(c, current.ID, c.Param("rev"))
+	if !ok {
+		return
+	}
+	d := *current
+	d.GoogleSheetURL = rev.Snapshot.GoogleSheetURL
+	d.RefreshInterval = rev.Snapshot.RefreshInterval
+	d.Layout = rev.Snapshot.Layout
+	d.BrandTheme = rev.Snapshot.BrandTheme
+	d.UpdatedBy = c.GetString("userID")
+	saved, err := h.store.Update(c.Request.Context(), d)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, saved)
+}
+
+// Export handles GET /api/dashboards/:id/export, downloading the dashboard
+// as a Document with its brand theme embedded.
+func (h *Handler) Export(c *gin.Context) {
+	current, ok := h.owned(c)
+	if !ok {
+		return
+	}
+	var theme *themes.Theme
+	if current.BrandTheme != "" {
+		t, err := h.brandThemes.Get(c.Request.Context(), current.BrandTheme, 0)
+		switch {
+		case err == nil:
+			theme = t
+		case !errors.Is(err, themes.ErrNotFound):
+			h.fail(c, err)
+			return
+		}
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+current.ID+`.json"`)
+	c.IndentedJSON(http.StatusOK, Export(current, theme))
+}
+
+// Import handles POST /api/dashboards/import, creating a dashboard for the
+// caller from a Document of any supported version. An embedded brand theme
+// is saved as a new theme of the caller, and deleted again if the
+// dashboard can't be created.
+func (h *Handler) Import(c *gin.Context) {
+	raw, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize))
+	if err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	doc, err := ParseDocument(raw)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	d, err := doc.Dashboard()
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	d.UserID = c.GetString("userID")
+	d.UpdatedBy = d.UserID
+
+	if doc.Theme != nil {
+		t := themes.Theme{Name: doc.Theme.Name, Owner: d.UserID, Definition: doc.Theme.Definition}
+		if err := t.Validate(); err != nil {
+			var themeErr *themes.ValidationError
+			if errors.As(err, &themeErr) {
+				fields := make([]FieldError, len(themeErr.Fields))
+				for i, f := range themeErr.Fields {
+					fields[i] = FieldError{Field: "theme." + f.Field, Message: f.Message}
+				}
+				err = &ValidationError{Fields: fields}
+			}
+			h.fail(c, err)
+			return
+		}
+		saved, err := h.brandThemes.Save(c.Request.Context(), t)
+		if err != nil {
+			h.fail(c, err)
+			return
+		}
+		d.BrandTheme = saved.ID
+	}
+
+	saved, err := h.store.Create(c.Request.Context(), d)
+	if err != nil {
+		if d.BrandTheme != "" {
+			if delErr := h.brandThemes.Delete(c.Request.Context(), d.BrandTheme); delErr != nil {
+				err = fmt.Errorf("%w (and failed to delete imported theme %s: %v)", err, d.BrandTheme, delErr)
+			}
+		}
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, saved)
+}
+
+// revision loads the revision numbered number of dashboard id.
+func (h *Handler) revision(c *gin.Context, id, number string) (*Revision, bool) {
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "revision must be a number"})
+		return nil, false
+	}
+	rev, err := h.store.Revision(c.Request.Context(), id, n)
+	if err != nil {
+		h.fail(c, err)
+		return nil, false
+	}
+	return rev, true
+}
+
+// bind strictly decodes and validates the request body.
+func (h *Handler) bind(c *gin.Context) (Dashboard, bool) {
+	var req DashboardRequest
+	dec := json.NewDecoder(c.Request.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return Dashboard{}, false
+	}
+	d := Dashboard{GoogleSheetURL: req.GoogleSheetURL, RefreshInterval: req.RefreshInterval, Layout: req.Layout, BrandTheme: req.BrandTheme}
+	if err := d.Validate(); err != nil {
+		h.fail(c, err)
+		return Dashboard{}, false
+	}
+	if d.BrandTheme != "" {
+		t, err := h.brandThemes.Get(c.Request.Context(), d.BrandTheme, 0)
+		if err == nil && t.Owner != c.GetString("userID") {
+			err = themes.ErrNotFound
+		}
+		if errors.Is(err, themes.ErrNotFound) {
+			err = &ValidationError{Fields: []FieldError{{Field: "brandTheme", Message: fmt.Sprintf("unknown theme %q", d.BrandTheme)}}}
+		}
+		if err != nil {
+			h.fail(c, err)
+			return Dashboard{}, false
+		}
+	}
+	return d, true
+}
+
+// owned returns the dashboard in the path, failing the request unless the
+// caller owns it. Other users' dashboards are reported as not found.
+func (h *Handler) owned(c *gin.Context) (*Dashboard, bool) {
+	d, err := h.store.Get(c.Request.Context(), c.Param("id"))
+	if err == nil && d.UserID != c.GetString("userID") {
+		err = ErrNotFound
+	}
+	if err != nil {
+		h.fail(c, err)
+		return nil, false
+	}
+	return d, true
+}
+
+func (h *Handler) fail(c *gin.Context, err error) {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid dashboard",
+			"fields": validationErr.Fields,
+		})
+	case errors.Is(err, ErrInvalidDocument):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrRevisionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,239 @@
+package dashboards
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjrtuhin/loomis-backend/internal/themes"
+)
+
+// failingStore is a Store whose creates fail.
+type failingStore struct {
+	Store
+}
+
+func (failingStore) Create(context.Context, Dashboard) (*Dashboard, error) {
+	return nil, errors.New("disk full")
+}
+
+// newTestRouter routes the dashboard API to h as user u1.
+func newTestRouter(h *Handler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("userID", "u1") })
+	router.POST("/api/dashboards/import", h.Import)
+	router.POST("/api/dashboards/:id/revisions/:rev/restore", h.Restore)
+	router.GET("/api/dashboards/:id/diff", h.Diff)
+	return router
+}
+
+const importedDocument = `{
+	"format": "loomis.dashboard",
+	"version": 2,
+	"refreshInterval": 30,
+	"dataSources": [{"id": "sheet", "type": "google-sheet", "url": "https://docs.google.com/spreadsheets/d/abc/edit"}],
+	"layout": {"charts": [], "textBlocks": []},
+	"theme": {"name": "Brand", "definition": {"colors": ["#112233"]}}
+}`
+
+func TestImport(t *testing.T) {
+	store, err := NewFileStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	brandThemes, err := themes.NewStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := newTestRouter(NewHandler(store, brandThemes))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/dashboards/import", strings.NewReader(importedDocument)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("import status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	list, err := brandThemes.List(context.Background(), "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("imported %d themes, want 1", len(list))
+	}
+}
+
+func TestImportFailureDeletesTheme(t *testing.T) {
+	store, err := NewFileStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	brandThemes, err := themes.NewStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := newTestRouter(NewHandler(failingStore{store}, brandThemes))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/dashboards/import", strings.NewReader(importedDocument)))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("import status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	list, err := brandThemes.List(context.Background(), "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("failed import left %d themes, want none", len(list))
+	}
+}
+
+func TestImportTooLarge(t *testing.T) {
+	store, err := NewFileStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := newTestRouter(NewHandler(store, nil))
+
+	body := `{"layout": {"textBlocks": [{"content": "` + strings.Repeat("x", maxImportSize) + `"}]}}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/dashboards/import", strings.NewReader(body)))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("import status = %d, want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+// revisedDashboard creates a dashboard of user with a second revision
+// changing its refresh interval and moving its chart.
+func revisedDashboard(t *testing.T, store Store, user string) *Dashboard {
+	t.Helper()
+	ctx := context.Background()
+	d, err := store.Create(ctx, testDashboard(user))
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := testDashboard(user)
+	next.ID = d.ID
+	next.RefreshInterval = 30
+	next.Layout.Charts[0].Position.X = 50
+	d, err = store.Update(ctx, next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestRestore(t *testing.T) {
+	store, err := NewFileStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := revisedDashboard(t, store, "u1")
+	router := newTestRouter(NewHandler(store, nil))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/dashboards/"+d.ID+"/revisions/1/restore", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("restore status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var restored Dashboard
+	if err := json.Unmarshal(w.Body.Bytes(), &restored); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Revision != 3 || restored.RefreshInterval != 60 || restored.Layout.Charts[0].Position.X != 0 || restored.UpdatedBy != "u1" {
+		t.Fatalf("restored = %+v, want revision 3 with the layout and refresh interval of revision 1", restored)
+	}
+
+	rev, err := store.Revision(context.Background(), d.ID, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, c := range rev.Changes {
+		paths = append(paths, c.Path)
+	}
+	if want := []string{"/refreshInterval", "/layout/charts/c1/position"}; strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("restore revision changes = %v, want %v", paths, want)
+	}
+}
+
+func TestRestoreErrors(t *testing.T) {
+	store, err := NewFileStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mine := revisedDashboard(t, store, "u1")
+	theirs := revisedDashboard(t, store, "u2")
+	router := newTestRouter(NewHandler(store, nil))
+
+	tests := []struct {
+		name, path string
+		want       int
+	}{
+		{"revision not a number", "/api/dashboards/" + mine.ID + "/revisions/latest/restore", http.StatusBadRequest},
+		{"unknown revision", "/api/dashboards/" + mine.ID + "/revisions/9/restore", http.StatusNotFound},
+		{"unknown dashboard", "/api/dashboards/missing/revisions/1/restore", http.StatusNotFound},
+		{"other user's dashboard", "/api/dashboards/" + theirs.ID + "/revisions/1/restore", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, nil))
+			if w.Code != tt.want {
+				t.Errorf("restore status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+	if got, err := store.Get(context.Background(), theirs.ID); err != nil || got.Revision != 2 {
+		t.Errorf("other user's dashboard = %+v, %v, want it left at revision 2", got, err)
+	}
+}
+
+func TestDiffHandler(t *testing.T) {
+	store, err := NewFileStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := revisedDashboard(t, store, "u1")
+	router := newTestRouter(NewHandler(store, nil))
+
+	tests := []struct {
+		name, query string
+		want        int
+		wantFrom    int
+		wantTo      int
+		wantChanges int
+	}{
+		{"to latest", "?from=1", http.StatusOK, 1, 2, 2},
+		{"backwards", "?from=2&to=1", http.StatusOK, 2, 1, 2},
+		{"same revision", "?from=2&to=2", http.StatusOK, 2, 2, 0},
+		{"missing from", "", http.StatusBadRequest, 0, 0, 0},
+		{"unknown revision", "?from=1&to=5", http.StatusNotFound, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/dashboards/"+d.ID+"/diff"+tt.query, nil))
+			if w.Code != tt.want {
+				t.Fatalf("diff status = %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+			var body struct {
+				From, To int
+				Changes  []Change
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.From != tt.wantFrom || body.To != tt.wantTo || len(body.Changes) != tt.wantChanges {
+				t.Errorf("diff = %+v, want %d changes from %d to %d", body, tt.wantChanges, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
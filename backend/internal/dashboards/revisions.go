@@ -0,0 +1,155 @@
+package dashboards
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Revision is an entry of the append-only history of a dashboard, written
+// on every create, update and restore.
+type Revision struct {
+	Number int `json:"number"`
+	// Author is the user ID of whoever saved the revision.
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Changes is what the revision changed since the one before it.
+	Changes []Change `json:"changes"`
+	// Snapshot is the dashboard as saved. It is left out of revision
+	// lists.
+	Snapshot *Dashboard `json:"snapshot,omitempty"`
+}
+
+// Change is one difference between two versions of a dashboard. Path
+// points at the changed value like a JSON pointer, except that items of
+// the layout are addressed by ID rather than index, e.g.
+// "/layout/charts/chart_1/position", so that moving an item in the list
+// is not a change.
+type Change struct {
+	// Op is "add", "remove" or "replace".
+	Op     string      `json:"op"`
+	Path   string      `json:"path"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// newRevision builds the revision recording the save of d over prev, which
+// is nil for a new dashboard. d.Revision must already be its number.
+func newRevision(prev, d *Dashboard) Revision {
+	snapshot := *d
+	return Revision{
+		Number:    d.Revision,
+		Author:    d.UpdatedBy,
+		CreatedAt: d.UpdatedAt,
+		Changes:   Diff(prev, d),
+		Snapshot:  &snapshot,
+	}
+}
+
+// Diff lists the changes from one version of a dashboard to another; a nil
+// from stands for an empty dashboard. Items are compared field by field.
+func Diff(from, to *Dashboard) []Change {
+	var prev Dashboard
+	if from != nil {
+		prev = *from
+	}
+	changes := []Change{}
+	field := func(path string, before, after interface{}) {
+		b, a := jsonValue(before), jsonValue(after)
+		switch {
+		case from == nil:
+			changes = append(changes, Change{Op: "add", Path: path, After: a})
+		case !reflect.DeepEqual(b, a):
+			changes = append(changes, Change{Op: "replace", Path: path, Before: b, After: a})
+		}
+	}
+	field("/googleSheetUrl", prev.GoogleSheetURL, to.GoogleSheetURL)
+	field("/refreshInterval", prev.RefreshInterval, to.RefreshInterval)
+	if prev.BrandTheme != to.BrandTheme {
+		field("/brandTheme", prev.BrandTheme, to.BrandTheme)
+	}
+
+	items := func(path string, before, after map[string]map[string]interface{}, beforeIDs, afterIDs []string) {
+		for _, id := range afterIDs {
+			itemPath := path + "/" + escapePointer(id)
+			b, ok := before[id]
+			if !ok {
+				changes = append(changes, Change{Op: "add", Path: itemPath, After: after[id]})
+				continue
+			}
+			a := after[id]
+			keys := make([]string, 0, len(a)+len(b))
+			for k := range a {
+				keys = append(keys, k)
+			}
+			for k := range b {
+				if _, ok := a[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				if !reflect.DeepEqual(b[k], a[k]) {
+					op := "replace"
+					if b[k] == nil {
+						op = "add"
+					} else if a[k] == nil {
+						op = "remove"
+					}
+					changes = append(changes, Change{Op: op, Path: itemPath + "/" + escapePointer(k), Before: b[k], After: a[k]})
+				}
+			}
+		}
+		for _, id := range beforeIDs {
+			if _, ok := after[id]; !ok {
+				changes = append(changes, Change{Op: "remove", Path: path + "/" + escapePointer(id), Before: before[id]})
+			}
+		}
+	}
+	beforeCharts, beforeChartIDs := chartItems(prev.Layout.Charts)
+	afterCharts, afterChartIDs := chartItems(to.Layout.Charts)
+	items("/layout/charts", beforeCharts, afterCharts, beforeChartIDs, afterChartIDs)
+	beforeBlocks, beforeBlockIDs := textItems(prev.Layout.TextBlocks)
+	afterBlocks, afterBlockIDs := textItems(to.Layout.TextBlocks)
+	items("/layout/textBlocks", beforeBlocks, afterBlocks, beforeBlockIDs, afterBlockIDs)
+	return changes
+}
+
+func chartItems(charts []ChartItem) (map[string]map[string]interface{}, []string) {
+	byID := make(map[string]map[string]interface{}, len(charts))
+	ids := make([]string, len(charts))
+	for i, c := range charts {
+		ids[i] = c.ID
+		byID[c.ID], _ = jsonValue(c).(map[string]interface{})
+	}
+	return byID, ids
+}
+
+func textItems(blocks []TextBlock) (map[string]map[string]interface{}, []string) {
+	byID := make(map[string]map[string]interface{}, len(blocks))
+	ids := make([]string, len(blocks))
+	for i, b := range blocks {
+		ids[i] = b.ID
+		byID[b.ID], _ = jsonValue(b).(map[string]interface{})
+	}
+	return byID, ids
+}
+
+// jsonValue returns v as decoded from its JSON encoding, so that values
+// compare the way they are stored.
+func jsonValue(v interface{}) interface{} {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out interface{}
+	_ = json.Unmarshal(raw, &out)
+	return out
+}
+
+// escapePointer escapes a JSON pointer segment.
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,77 @@
+package dashboards
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	base := testDashboard("u1")
+	edit := func(fn func(d *Dashboard)) *Dashboard {
+		d := testDashboard("u1")
+		fn(&d)
+		return &d
+	}
+	tests := []struct {
+		name     string
+		from, to *Dashboard
+		want     []string
+	}{
+		{"created", nil, &base, []string{"add /googleSheetUrl", "add /refreshInterval", "add /layout/charts/c1"}},
+		{"unchanged", &base, &base, nil},
+		{"refresh interval", &base, edit(func(d *Dashboard) { d.RefreshInterval = 5 }), []string{"replace /refreshInterval"}},
+		{"brand theme", &base, edit(func(d *Dashboard) { d.BrandTheme = "t1" }), []string{"replace /brandTheme"}},
+		{"moved chart", &base, edit(func(d *Dashboard) { d.Layout.Charts[0].Position.X = 40 }), []string{"replace /layout/charts/c1/position"}},
+		{"cleared config", &base, edit(func(d *Dashboard) { d.Layout.Charts[0].ChartConfig = nil }), []string{"remove /layout/charts/c1/chartConfig"}},
+		{
+			"reordered charts",
+			edit(func(d *Dashboard) {
+				d.Layout.Charts = append(d.Layout.Charts, ChartItem{ID: "c2", Type: "chart", ChartType: "pie"})
+			}),
+			edit(func(d *Dashboard) {
+				d.Layout.Charts = append([]ChartItem{{ID: "c2", Type: "chart", ChartType: "pie"}}, d.Layout.Charts...)
+			}),
+			nil,
+		},
+		{
+			"replaced text block",
+			edit(func(d *Dashboard) { d.Layout.TextBlocks = []TextBlock{{ID: "t1", Type: "text", Content: "a"}} }),
+			edit(func(d *Dashboard) { d.Layout.TextBlocks = []TextBlock{{ID: "a/b", Type: "text", Content: "b"}} }),
+			[]string{"add /layout/textBlocks/a~1b", "remove /layout/textBlocks/t1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := Diff(tt.from, tt.to)
+			if changes == nil {
+				t.Fatal("Diff() = nil, want an empty list")
+			}
+			var got []string
+			for _, c := range changes {
+				got = append(got, c.Op+" "+c.Path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffValues(t *testing.T) {
+	from := testDashboard("u1")
+	to := testDashboard("u1")
+	to.Layout.Charts[0].Position.X = 40
+	changes := Diff(&from, &to)
+	if len(changes) != 1 {
+		t.Fatalf("Diff() = %+v, want one change", changes)
+	}
+	want := Change{
+		Op:     "replace",
+		Path:   "/layout/charts/c1/position",
+		Before: map[string]interface{}{"x": 0.0, "y": 0.0, "w": 400.0, "h": 300.0},
+		After:  map[string]interface{}{"x": 40.0, "y": 0.0, "w": 400.0, "h": 300.0},
+	}
+	if !reflect.DeepEqual(changes[0], want) {
+		t.Errorf("Diff() = %+v, want %+v", changes[0], want)
+	}
+}
@@ -0,0 +1,262 @@
+package dashboards
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned for unknown dashboard IDs.
+	ErrNotFound = errors.New("dashboard not found")
+	// ErrRevisionNotFound is returned for unknown revision numbers.
+	ErrRevisionNotFound = errors.New("revision not found")
+)
+
+// Store persists dashboards and their revisions.
+type Store interface {
+	// List returns the dashboards of a user, most recently updated first.
+	List(ctx context.Context, userID string) ([]Dashboard, error)
+	Get(ctx context.Context, id string) (*Dashboard, error)
+	// Create stores d under a new ID, with its first revision, and
+	// returns it as stored.
+	Create(ctx context.Context, d Dashboard) (*Dashboard, error)
+	// Update replaces the dashboard d.ID, keeping its creation time, and
+	// appends a revision. It returns the dashboard as stored.
+	Update(ctx context.Context, d Dashboard) (*Dashboard, error)
+	// Delete removes a dashboard with its revisions.
+	Delete(ctx context.Context, id string) error
+	// Revisions returns the revisions of a dashboard, oldest first and
+	// without snapshots.
+	Revisions(ctx context.Context, id string) ([]Revision, error)
+	// Revision returns one revision of a dashboard with its snapshot.
+	Revision(ctx context.Context, id string, number int) (*Revision, error)
+}
+
+// created prepares d for storage as a new dashboard.
+func created(d *Dashboard) Revision {
+	d.CreatedAt = time.Now().UTC()
+	d.UpdatedAt = d.CreatedAt
+	d.Revision = 1
+	return newRevision(nil, d)
+}
+
+// updated prepares d for storage over prev.
+func updated(prev, d *Dashboard) Revision {
+	d.CreatedAt = prev.CreatedAt
+	d.UpdatedAt = time.Now().UTC()
+	d.Revision = prev.Revision + 1
+	return newRevision(prev, d)
+}
+
+// summaries drops the snapshots of revisions, for listing.
+func summaries(revisions []Revision) []Revision {
+	out := make([]Revision, len(revisions))
+	for i, r := range revisions {
+		r.Snapshot = nil
+		out[i] = r
+	}
+	return out
+}
+
+// sortByUpdated orders dashboards most recently updated first.
+func sortByUpdated(list []Dashboard) {
+	sort.Slice(list, func(a, b int) bool {
+		if !list[a].UpdatedAt.Equal(list[b].UpdatedAt) {
+			return list[a].UpdatedAt.After(list[b].UpdatedAt)
+		}
+		return list[a].ID < list[b].ID
+	})
+}
+
+// fileStore keeps dashboards in memory and, when path is set, rewrites
+// them all to the JSON file at path after every change.
+type fileStore struct {
+	mu   sync.RWMutex
+	path string
+	data fileData
+}
+
+// fileData is the content of a fileStore file.
+type fileData struct {
+	Dashboards map[string]Dashboard  `json:"dashboards"`
+	Revisions  map[string][]Revision `json:"revisions"`
+}
+
+// NewFileStore creates a Store backed by the JSON file at path, loading
+// the dashboards already saved there. With an empty path dashboards are
+// kept in memory only, which suits tests and local development.
+func NewFileStore(path string) (Store, error) {
+	s := &fileStore{path: path}
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read dashboards: %w", err)
+		}
+		if err == nil {
+			if err := json.Unmarshal(b, &s.data); err != nil {
+				return nil, fmt.Errorf("failed to parse dashboards: %w", err)
+			}
+		}
+	}
+	if s.data.Dashboards == nil {
+		s.data.Dashboards = make(map[string]Dashboard)
+	}
+	if s.data.Revisions == nil {
+		s.data.Revisions = make(map[string][]Revision)
+	}
+	return s, nil
+}
+
+func (s *fileStore) List(_ context.Context, userID string) ([]Dashboard, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []Dashboard
+	for _, d := range s.data.Dashboards {
+		if d.UserID == userID {
+			out = append(out, d)
+		}
+	}
+	sortByUpdated(out)
+	return out, nil
+}
+
+func (s *fileStore) Get(_ context.Context, id string) (*Dashboard, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d, ok := s.data.Dashboards[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &d, nil
+}
+
+func (s *fileStore) Create(_ context.Context, d Dashboard) (*Dashboard, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	d.ID = id
+	rev := created(&d)
+	s.data.Dashboards[id] = d
+	s.data.Revisions[id] = []Revision{rev}
+	if err := s.flush(); err != nil {
+		delete(s.data.Dashboards, id)
+		delete(s.data.Revisions, id)
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (s *fileStore) Update(_ context.Context, d Dashboard) (*Dashboard, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev, ok := s.data.Dashboards[d.ID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	revisions := s.data.Revisions[d.ID]
+	s.data.Revisions[d.ID] = append(revisions, updated(&prev, &d))
+	s.data.Dashboards[d.ID] = d
+	if err := s.flush(); err != nil {
+		s.data.Dashboards[d.ID] = prev
+		s.data.Revisions[d.ID] = revisions
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (s *fileStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev, ok := s.data.Dashboards[id]
+	if !ok {
+		return ErrNotFound
+	}
+	revisions := s.data.Revisions[id]
+	delete(s.data.Dashboards, id)
+	delete(s.data.Revisions, id)
+	if err := s.flush(); err != nil {
+		s.data.Dashboards[id] = prev
+		s.data.Revisions[id] = revisions
+		return err
+	}
+	return nil
+}
+
+func (s *fileStore) Revisions(_ context.Context, id string) ([]Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.data.Dashboards[id]; !ok {
+		return nil, ErrNotFound
+	}
+	return summaries(s.data.Revisions[id]), nil
+}
+
+func (s *fileStore) Revision(_ context.Context, id string, number int) (*Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.data.Dashboards[id]; !ok {
+		return nil, ErrNotFound
+	}
+	for _, r := range s.data.Revisions[id] {
+		if r.Number == number {
+			return &r, nil
+		}
+	}
+	return nil, ErrRevisionNotFound
+}
+
+// flush writes the dashboards to a temporary file next to s.path and
+// renames it over s.path, so that a failed write never truncates saved
+// dashboards.
+func (s *fileStore) flush() error {
+	if s.path == "" {
+		return nil
+	}
+	b, err := json.Marshal(s.data)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save dashboards: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save dashboards: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save dashboards: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save dashboards: %w", err)
+	}
+	return nil
+}
+
+func newID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "dashboard_" + hex.EncodeToString(b), nil
+}
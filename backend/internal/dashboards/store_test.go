@@ -0,0 +1,157 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mjrtuhin/loomis-backend/internal/storage"
+)
+
+// stores returns a fresh store of each local kind.
+func stores(t *testing.T) map[string]Store {
+	t.Helper()
+	memory, err := NewFileStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := NewFileStore(filepath.Join(t.TempDir(), "dashboards.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := storage.Open(filepath.Join(t.TempDir(), "loomis.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return map[string]Store{"memory": memory, "file": file, "bolt": NewBoltStore(db)}
+}
+
+func testDashboard(userID string) Dashboard {
+	return Dashboard{
+		UserID:          userID,
+		GoogleSheetURL:  "https://docs.google.com/spreadsheets/d/abc/edit",
+		RefreshInterval: 60,
+		Layout: Layout{
+			Charts: []ChartItem{{
+				ID: "c1", Type: "chart", ChartType: "bar",
+				Position:    Position{W: 400, H: 300},
+				ChartConfig: map[string]interface{}{"series": []interface{}{map[string]interface{}{"data": []interface{}{[]interface{}{1.0, 2.0}}}}},
+			}},
+			TextBlocks: []TextBlock{},
+		},
+	}
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	for name, s := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			d, err := s.Create(ctx, testDashboard("u1"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.ID == "" || d.Revision != 1 || d.CreatedAt.IsZero() {
+				t.Fatalf("Create() = %+v, want an ID, revision 1 and a creation time", d)
+			}
+			if _, err := s.Create(ctx, testDashboard("u2")); err != nil {
+				t.Fatal(err)
+			}
+
+			list, err := s.List(ctx, "u1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(list) != 1 || list[0].ID != d.ID {
+				t.Fatalf("List(u1) = %+v, want only %s", list, d.ID)
+			}
+
+			d.RefreshInterval = 30
+			updated, err := s.Update(ctx, *d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if updated.Revision != 2 || !updated.CreatedAt.Equal(d.CreatedAt) {
+				t.Fatalf("Update() = %+v, want revision 2 keeping the creation time", updated)
+			}
+			got, err := s.Get(ctx, d.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.RefreshInterval != 30 || len(got.Layout.Charts) != 1 || got.Layout.Charts[0].ChartConfig["series"] == nil {
+				t.Fatalf("Get() = %+v, want the updated dashboard", got)
+			}
+
+			revisions, err := s.Revisions(ctx, d.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(revisions) != 2 || revisions[0].Number != 1 || revisions[1].Number != 2 || revisions[1].Snapshot != nil {
+				t.Fatalf("Revisions() = %+v, want revisions 1 and 2 without snapshots", revisions)
+			}
+			rev, err := s.Revision(ctx, d.ID, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rev.Snapshot == nil || rev.Snapshot.RefreshInterval != 60 {
+				t.Fatalf("Revision(1) snapshot = %+v, want the created dashboard", rev.Snapshot)
+			}
+			if _, err := s.Revision(ctx, d.ID, 3); !errors.Is(err, ErrRevisionNotFound) {
+				t.Fatalf("Revision(3) error = %v, want ErrRevisionNotFound", err)
+			}
+
+			if err := s.Delete(ctx, d.ID); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.Get(ctx, d.ID); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Get() after Delete error = %v, want ErrNotFound", err)
+			}
+			if _, err := s.Update(ctx, *d); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Update() after Delete error = %v, want ErrNotFound", err)
+			}
+			if err := s.Delete(ctx, d.ID); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Delete() after Delete error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestFileStoreReload(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "dashboards.json")
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := s.Create(ctx, testDashboard("u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := reopened.Get(ctx, d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GoogleSheetURL != d.GoogleSheetURL || got.Revision != 1 {
+		t.Fatalf("Get() after reopening = %+v, want %+v", got, d)
+	}
+	if revisions, err := reopened.Revisions(ctx, d.ID); err != nil || len(revisions) != 1 {
+		t.Fatalf("Revisions() after reopening = %+v, %v, want one revision", revisions, err)
+	}
+}
+
+func TestNewFileStoreInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dashboards.json")
+	if err := os.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileStore(path); err == nil {
+		t.Fatal("NewFileStore() on an invalid file succeeded")
+	}
+}
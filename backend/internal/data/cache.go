@@ -0,0 +1,105 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/mjrtuhin/loomis-backend/internal/storage"
+)
+
+// cacheTTL is how long a fetched sheet is served from the cache. It stays
+// under the shortest dashboard refresh interval.
+const cacheTTL = time.Minute
+
+// CacheEntry is a fetched sheet together with its quality analysis.
+type CacheEntry struct {
+	Data      SheetData     `json:"data"`
+	Quality   QualityReport `json:"quality"`
+	FetchedAt time.Time     `json:"fetchedAt"`
+}
+
+// Cache keeps fetched sheets and their analyses by sheet URL.
+type Cache interface {
+	// Get returns the entry for url, or nil when there is none.
+	Get(ctx context.Context, url string) (*CacheEntry, error)
+	Put(ctx context.Context, url string, e CacheEntry) error
+}
+
+// LoadSheet fetches and analyzes the sheet at url. Sheets fetched less than
+// cacheTTL ago are served from cache, which may be nil, unless refresh is
+// set.
+func LoadSheet(ctx context.Context, cache Cache, url string, refresh bool) (*CacheEntry, error) {
+	if cache != nil && !refresh {
+		entry, err := cache.Get(ctx, url)
+		if err != nil {
+			log.Printf("Failed to read sheet cache: %v", err)
+		} else if entry != nil && time.Since(entry.FetchedAt) < cacheTTL {
+			return entry, nil
+		}
+	}
+
+	data, err := FetchGoogleSheet(url)
+	if err != nil {
+		return nil, err
+	}
+	entry := CacheEntry{Data: *data, Quality: *AnalyzeQuality(data), FetchedAt: time.Now().UTC()}
+	if cache != nil {
+		if err := cache.Put(ctx, url, entry); err != nil {
+			log.Printf("Failed to cache sheet: %v", err)
+		}
+	}
+	return &entry, nil
+}
+
+// cacheBucket holds cached sheets in the embedded database.
+const cacheBucket = "datasets"
+
+type boltCache struct {
+	db *storage.DB
+}
+
+// NewBoltCache creates a Cache in the embedded database db.
+func NewBoltCache(db *storage.DB) Cache {
+	return &boltCache{db: db}
+}
+
+func (c *boltCache) Get(_ context.Context, url string) (*CacheEntry, error) {
+	var e CacheEntry
+	found, err := c.db.Get(cacheBucket, url, &e)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Put stores e and drops the entries fetched cacheTTL or more before it,
+// which would never be served again, so that the bucket only holds the
+// sheets read in the last cacheTTL.
+func (c *boltCache) Put(_ context.Context, url string, e CacheEntry) error {
+	return c.db.Update(func(tx *storage.Tx) error {
+		var expired []string
+		err := tx.ForEach(cacheBucket, func(key string, raw json.RawMessage) error {
+			var cached struct {
+				FetchedAt time.Time `json:"fetchedAt"`
+			}
+			if err := json.Unmarshal(raw, &cached); err != nil {
+				return err
+			}
+			if e.FetchedAt.Sub(cached.FetchedAt) >= cacheTTL {
+				expired = append(expired, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range expired {
+			if _, err := tx.Delete(cacheBucket, key); err != nil {
+				return err
+			}
+		}
+		return tx.Put(cacheBucket, url, e)
+	})
+}
@@ -0,0 +1,93 @@
+package data
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mjrtuhin/loomis-backend/internal/storage"
+)
+
+func newTestCache(t *testing.T) Cache {
+	t.Helper()
+	db, err := storage.Open(filepath.Join(t.TempDir(), "loomis.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewBoltCache(db)
+}
+
+func TestBoltCache(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestCache(t)
+	if e, err := cache.Get(ctx, "https://example.com/a"); err != nil || e != nil {
+		t.Fatalf("Get() of an uncached sheet = %+v, %v, want nil", e, err)
+	}
+
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	entry := CacheEntry{Data: SheetData{Headers: []string{"month", "sales"}}, FetchedAt: at}
+	if err := cache.Put(ctx, "https://example.com/a", entry); err != nil {
+		t.Fatal(err)
+	}
+	got, err := cache.Get(ctx, "https://example.com/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || len(got.Data.Headers) != 2 || !got.FetchedAt.Equal(at) {
+		t.Fatalf("Get() = %+v, want the cached entry", got)
+	}
+}
+
+func TestBoltCacheDropsExpired(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestCache(t)
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for url, fetched := range map[string]time.Time{
+		"https://example.com/old":    at.Add(-cacheTTL),
+		"https://example.com/recent": at.Add(-cacheTTL / 2),
+	} {
+		if err := cache.Put(ctx, url, CacheEntry{FetchedAt: fetched}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cache.Put(ctx, "https://example.com/new", CacheEntry{FetchedAt: at}); err != nil {
+		t.Fatal(err)
+	}
+
+	for url, want := range map[string]bool{
+		"https://example.com/old":    false,
+		"https://example.com/recent": true,
+		"https://example.com/new":    true,
+	} {
+		e, err := cache.Get(ctx, url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (e != nil) != want {
+			t.Errorf("Get(%s) = %+v, want cached %v", url, e, want)
+		}
+	}
+}
+
+func TestLoadSheetFromCache(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestCache(t)
+	// The URL cannot be fetched, so only a cache hit succeeds.
+	url := "http://127.0.0.1:0/sheet"
+	entry := CacheEntry{Data: SheetData{Headers: []string{"month"}}, FetchedAt: time.Now().UTC()}
+	if err := cache.Put(ctx, url, entry); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadSheet(ctx, cache, url, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Data.Headers) != 1 {
+		t.Errorf("LoadSheet() = %+v, want the cached sheet", got)
+	}
+	if _, err := LoadSheet(ctx, cache, url, true); err == nil {
+		t.Error("LoadSheet() with refresh served the cache")
+	}
+}
@@ -2,12 +2,18 @@ package data
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
 	"strings"
 )
 
+var (
+	ErrSheetNotPublic = errors.New("sheet is not public (403 Forbidden)")
+	ErrSheetNotFound  = errors.New("sheet not found (404 Not Found)")
+)
+
 // ExtractFileID extracts the Google Sheets file ID from various URL formats
 func ExtractFileID(url string) (string, error) {
 	patterns := []string{
@@ -45,11 +51,11 @@ func FetchGoogleSheet(url string) (*SheetData, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 403 {
-		return nil, fmt.Errorf("sheet is not public (403 Forbidden)")
+		return nil, ErrSheetNotPublic
 	}
 
 	if resp.StatusCode == 404 {
-		return nil, fmt.Errorf("sheet not found (404 Not Found)")
+		return nil, ErrSheetNotFound
 	}
 
 	if resp.StatusCode != 200 {
@@ -1,15 +1,20 @@
 package data
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
-type Handler struct{}
+type Handler struct {
+	cache Cache
+}
 
-func NewHandler() *Handler {
-	return &Handler{}
+// NewHandler creates a Handler. cache keeps recently fetched sheets and
+// may be nil.
+func NewHandler(cache Cache) *Handler {
+	return &Handler{cache: cache}
 }
 
 // AnalyzeSheet handles POST /api/sheets/analyze
@@ -24,38 +29,40 @@ func (h *Handler) AnalyzeSheet(c *gin.Context) {
 		return
 	}
 
-	// Fetch data from Google Sheets
-	data, err := FetchGoogleSheet(req.URL)
+	entry, err := LoadSheet(c.Request.Context(), h.cache, req.URL, req.Refresh)
 	if err != nil {
-		if err.Error() == "sheet is not public (403 Forbidden)" {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error":   "Sheet not accessible",
-				"message": "The Google Sheet is not public. Please share it with 'Anyone with the link can view'.",
-			})
-			return
-		}
-
-		if err.Error() == "sheet not found (404 Not Found)" {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error":   "Sheet not found",
-				"message": "Could not find the Google Sheet. Please check the URL.",
-			})
-			return
-		}
+		SheetError(c, err)
+		return
+	}
 
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Failed to load sheet",
-			"message": err.Error(),
+	// Return response
+	c.JSON(http.StatusOK, AnalyzeResponse{
+		Data:    entry.Data,
+		Quality: entry.Quality,
+	})
+}
+
+// SheetError fails the request with the response for an error loading a
+// sheet.
+func SheetError(c *gin.Context, err error) {
+	if errors.Is(err, ErrSheetNotPublic) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Sheet not accessible",
+			"message": "The Google Sheet is not public. Please share it with 'Anyone with the link can view'.",
 		})
 		return
 	}
 
-	// Analyze data quality
-	quality := AnalyzeQuality(data)
+	if errors.Is(err, ErrSheetNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Sheet not found",
+			"message": "Could not find the Google Sheet. Please check the URL.",
+		})
+		return
+	}
 
-	// Return response
-	c.JSON(http.StatusOK, AnalyzeResponse{
-		Data:    *data,
-		Quality: *quality,
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":   "Failed to load sheet",
+		"message": err.Error(),
 	})
 }
@@ -29,4 +29,6 @@ type AnalyzeResponse struct {
 
 type AnalyzeRequest struct {
 	URL string `json:"url" binding:"required"`
+	// Refresh skips the sheet cache.
+	Refresh bool `json:"refresh"`
 }
@@ -0,0 +1,149 @@
+package geocode
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-echarts/go-echarts/v2/datasets"
+)
+
+// Location is a resolved place.
+type Location struct {
+	Name  string  `json:"name"`
+	Lat   float64 `json:"lat"`
+	Lng   float64 `json:"lng"`
+	Found bool    `json:"found"`
+}
+
+// Geocoder resolves place names to coordinates using the city table bundled
+// with go-echarts plus any entries loaded from a gazetteer file.
+type Geocoder struct {
+	mu      sync.RWMutex
+	entries map[string][2]float64
+}
+
+// NewGeocoder builds a Geocoder seeded with datasets.Coordinates. If
+// gazetteerPath is set, its entries are loaded on top and take precedence.
+func NewGeocoder(gazetteerPath string) (*Geocoder, error) {
+	g := &Geocoder{entries: make(map[string][2]float64, len(datasets.Coordinates))}
+	for name, c := range datasets.Coordinates {
+		// datasets.Coordinates stores {lng, lat}
+		g.entries[normalizePlace(name)] = [2]float64{widen(c[1]), widen(c[0])}
+	}
+
+	if gazetteerPath != "" {
+		f, err := os.Open(gazetteerPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gazetteer: %w", err)
+		}
+		defer f.Close()
+
+		if err := g.LoadGazetteer(f); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// LoadGazetteer reads CSV rows of name,lat,lng. Any further columns are
+// treated as aliases for the same place. Lines starting with '#' and a
+// leading "name,lat,lng" header are skipped.
+func (g *Geocoder) LoadGazetteer(r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to parse gazetteer: %w", err)
+	}
+
+	for i, record := range records {
+		if len(record) < 3 {
+			return fmt.Errorf("gazetteer line %d: expected name,lat,lng", i+1)
+		}
+		if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "name") {
+			continue
+		}
+
+		lat, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil || lat < -90 || lat > 90 {
+			return fmt.Errorf("gazetteer line %d: invalid latitude %q", i+1, record[1])
+		}
+		lng, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		if err != nil || lng < -180 || lng > 180 {
+			return fmt.Errorf("gazetteer line %d: invalid longitude %q", i+1, record[2])
+		}
+
+		g.Add(record[0], lat, lng)
+		for _, alias := range record[3:] {
+			if strings.TrimSpace(alias) != "" {
+				g.Add(alias, lat, lng)
+			}
+		}
+	}
+	return nil
+}
+
+// Add registers or replaces a place.
+func (g *Geocoder) Add(name string, lat, lng float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.entries[normalizePlace(name)] = [2]float64{lat, lng}
+}
+
+// Lookup resolves a single place name.
+func (g *Geocoder) Lookup(name string) (Location, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	key := normalizePlace(name)
+	c, ok := g.entries[key]
+	if !ok {
+		// Chinese place names are often written with an administrative
+		// suffix that the bundled table omits, e.g. "杭州市" for "杭州".
+		for _, suffix := range []string{"市", "县", "区"} {
+			if trimmed := strings.TrimSuffix(key, suffix); trimmed != key {
+				if c, ok = g.entries[trimmed]; ok {
+					break
+				}
+			}
+		}
+	}
+	if !ok {
+		return Location{Name: name}, false
+	}
+	return Location{Name: name, Lat: c[0], Lng: c[1], Found: true}, true
+}
+
+// Resolve geocodes a column of place names, returning one Location per input
+// in order along with the distinct names that could not be found.
+func (g *Geocoder) Resolve(names []string) ([]Location, []string) {
+	locations := make([]Location, len(names))
+	var unmatched []string
+	seen := make(map[string]bool)
+	for i, name := range names {
+		loc, ok := g.Lookup(name)
+		locations[i] = loc
+		if !ok && !seen[name] {
+			seen[name] = true
+			unmatched = append(unmatched, name)
+		}
+	}
+	return locations, unmatched
+}
+
+// widen converts f to float64 without picking up float32 rounding noise.
+func widen(f float32) float64 {
+	v, _ := strconv.ParseFloat(strconv.FormatFloat(float64(f), 'f', -1, 32), 64)
+	return v
+}
+
+func normalizePlace(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
@@ -0,0 +1,111 @@
+package geocode
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const gazetteer = `name,lat,lng
+# offices
+HQ,51.5,-0.12,London office, head office
+Hangzhou Lab,30.1,120.1
+杭州,1,2
+小镇,3,4
+`
+
+func TestLookup(t *testing.T) {
+	g, err := NewGeocoder("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.LoadGazetteer(strings.NewReader(gazetteer)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		lat, lng float64
+		ok       bool
+	}{
+		{"大连", 38.92, 121.62, true},
+		{"杭州", 1, 2, true},
+		{"HQ", 51.5, -0.12, true},
+		{"  head   OFFICE ", 51.5, -0.12, true},
+		{"hangzhou lab", 30.1, 120.1, true},
+		{"小镇县", 3, 4, true},
+		{"小镇区", 3, 4, true},
+		{"小镇省", 0, 0, false},
+		{"市", 0, 0, false},
+		{"Atlantis", 0, 0, false},
+		{"name", 0, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := g.Lookup(tt.name)
+		want := Location{Name: tt.name, Lat: tt.lat, Lng: tt.lng, Found: tt.ok}
+		if got != want || ok != tt.ok {
+			t.Errorf("Lookup(%q) = %+v, %v, want %+v, %v", tt.name, got, ok, want, tt.ok)
+		}
+	}
+}
+
+func TestLoadGazetteerErrors(t *testing.T) {
+	tests := []struct {
+		name, csv, want string
+	}{
+		{"short line", "HQ,51.5\n", "gazetteer line 1: expected name,lat,lng"},
+		{"latitude", "name,lat,lng\nHQ,95,0\n", `gazetteer line 2: invalid latitude "95"`},
+		{"longitude", "HQ,0,east\n", `gazetteer line 1: invalid longitude "east"`},
+		{"quoting", "\"HQ,0,0\n", "failed to parse gazetteer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGeocoder("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = g.LoadGazetteer(strings.NewReader(tt.csv))
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("LoadGazetteer() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewGeocoderGazetteer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "places.csv")
+	if err := os.WriteFile(path, []byte(gazetteer), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	g, err := NewGeocoder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc, ok := g.Lookup("杭州"); !ok || loc.Lat != 1 || loc.Lng != 2 {
+		t.Errorf("Lookup(杭州) = %+v, want the gazetteer entry over the bundled one", loc)
+	}
+
+	if _, err := NewGeocoder(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("NewGeocoder() with a missing gazetteer succeeded")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	g, err := NewGeocoder("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	locations, unmatched := g.Resolve([]string{"大连", "Atlantis", "大连市", "Atlantis", "Narnia"})
+	var found []bool
+	for _, loc := range locations {
+		found = append(found, loc.Found)
+	}
+	if want := []bool{true, false, true, false, false}; !reflect.DeepEqual(found, want) {
+		t.Errorf("Resolve() found = %v, want %v", found, want)
+	}
+	if want := []string{"Atlantis", "Narnia"}; !reflect.DeepEqual(unmatched, want) {
+		t.Errorf("Resolve() unmatched = %v, want %v", unmatched, want)
+	}
+}
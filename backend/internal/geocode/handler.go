@@ -0,0 +1,39 @@
+package geocode
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	geocoder *Geocoder
+}
+
+func NewHandler(geocoder *Geocoder) *Handler {
+	return &Handler{geocoder: geocoder}
+}
+
+type GeocodeRequest struct {
+	Names []string `json:"names" binding:"required"`
+}
+
+type GeocodeResponse struct {
+	Locations []Location `json:"locations"`
+	Unmatched []string   `json:"unmatched"`
+}
+
+// Geocode handles POST /api/geocode
+func (h *Handler) Geocode(c *gin.Context) {
+	var req GeocodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	locations, unmatched := h.geocoder.Resolve(req.Names)
+	if unmatched == nil {
+		unmatched = []string{}
+	}
+	c.JSON(http.StatusOK, GeocodeResponse{Locations: locations, Unmatched: unmatched})
+}
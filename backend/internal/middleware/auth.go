@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"context"
 	"net/http"
 	"strings"
 
@@ -10,12 +9,12 @@ import (
 )
 
 type AuthMiddleware struct {
-	firebaseAuth *auth.FirebaseAuth
+	verifier auth.Verifier
 }
 
-func NewAuthMiddleware(firebaseAuth *auth.FirebaseAuth) *AuthMiddleware {
+func NewAuthMiddleware(verifier auth.Verifier) *AuthMiddleware {
 	return &AuthMiddleware{
-		firebaseAuth: firebaseAuth,
+		verifier: verifier,
 	}
 }
 
@@ -38,9 +37,8 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 
 		token := parts[1]
 
-		// Verify token with Firebase
-		ctx := context.Background()
-		decodedToken, err := m.firebaseAuth.VerifyToken(ctx, token)
+		// Verify token with Firebase or the local user store
+		identity, err := m.verifier.Verify(c.Request.Context(), token)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			c.Abort()
@@ -48,8 +46,8 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 		}
 
 		// Store user ID in context
-		c.Set("userID", decodedToken.UID)
-		c.Set("email", decodedToken.Claims["email"])
+		c.Set("userID", identity.UID)
+		c.Set("email", identity.Email)
 
 		c.Next()
 	}
@@ -0,0 +1,44 @@
+package pages
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/mjrtuhin/loomis-backend/internal/assets"
+)
+
+// inlineAssets replaces the script and style links of page with the
+// contents of the embedded assets, so that it renders without network
+// access. It must be called once every chart is added, before the page is
+// rendered.
+func inlineAssets(page *components.Page) error {
+	var headers []string
+	for _, name := range page.JSAssets.Values {
+		body, err := assets.Read(name)
+		if err != nil {
+			return fmt.Errorf("failed to read asset %s: %w", name, err)
+		}
+		headers = append(headers, "<script>"+escapeEnd(string(body), "</script")+"</script>")
+	}
+	for _, name := range page.CSSAssets.Values {
+		body, err := assets.Read(name)
+		if err != nil {
+			return fmt.Errorf("failed to read asset %s: %w", name, err)
+		}
+		headers = append(headers, "<style>"+escapeEnd(string(body), "</style")+"</style>")
+	}
+	page.ClearPresetAssets()
+	// The inlined assets go before the headers already set, which may
+	// use them.
+	headers = append(headers, page.CustomizedHeaders.Values...)
+	page.CustomizedHeaders.Clear()
+	page.AddCustomizedHeaders(headers...)
+	return nil
+}
+
+// escapeEnd breaks up the end tag in the inlined asset s, which would
+// otherwise close the element holding it early.
+func escapeEnd(s, tag string) string {
+	return strings.ReplaceAll(s, tag, `<\/`+tag[2:])
+}
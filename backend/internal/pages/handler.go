@@ -0,0 +1,283 @@
+package pages
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/mjrtuhin/loomis-backend/internal/dashboards"
+	"github.com/mjrtuhin/loomis-backend/internal/shares"
+)
+
+const (
+	providerName = "Loomis"
+	// pageStyle drops the body margin so that chart pages fill their
+	// iframe.
+	pageStyle = `<style>html, body { margin: 0; height: 100%; }</style>`
+)
+
+type Handler struct {
+	dashboards dashboards.Store
+	shares     *shares.Handler
+}
+
+// NewHandler creates a Handler exporting the dashboards in store and
+// serving those shared through shareHandler's links.
+func NewHandler(store dashboards.Store, shareHandler *shares.Handler) *Handler {
+	return &Handler{dashboards: store, shares: shareHandler}
+}
+
+// OEmbed is an oEmbed response of the rich type.
+type OEmbed struct {
+	Version      string `json:"version"`
+	Type         string `json:"type"`
+	ProviderName string `json:"provider_name"`
+	ProviderURL  string `json:"provider_url"`
+	Title        string `json:"title"`
+	HTML         string `json:"html"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+}
+
+// Chart handles GET /embed/charts/:id?token=, a page showing one chart of
+// the dashboard shared through token, filling the frame it is embedded in.
+func (h *Handler) Chart(c *gin.Context) {
+	token := c.Query("token")
+	_, d, err := h.shares.Open(c.Request.Context(), token, "")
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	item, ok := findChart(d, c.Param("id"))
+	if !ok {
+		h.fail(c, shares.ErrNotFound)
+		return
+	}
+
+	page := components.NewPage()
+	page.SetPageTitle(chartTitle(item))
+	page.SetLayout(components.PageFullLayout)
+	page.SetAssetsHost(assetsHost(c))
+	page.AddCustomizedHeaders(pageStyle, h.discovery(c, "charts", item.ID, token))
+	page.AddCharts(newChart("chart1", item.ChartConfig, "100%", "100%"))
+	h.render(c, page)
+}
+
+// Dashboard handles GET /embed/dashboards/:id?token=, a page showing the
+// charts and text blocks of the dashboard shared through token as laid out
+// on the builder canvas.
+func (h *Handler) Dashboard(c *gin.Context) {
+	token := c.Query("token")
+	_, d, err := h.shares.Open(c.Request.Context(), token, "")
+	if err == nil && d.ID != c.Param("id") {
+		err = shares.ErrNotFound
+	}
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	page := canvasPage(providerName+" dashboard", d.Layout)
+	page.SetAssetsHost(assetsHost(c))
+	page.AddCustomizedHeaders(h.discovery(c, "dashboards", d.ID, token))
+	h.render(c, page)
+}
+
+// Export handles GET /api/dashboards/:id/export/html, a single HTML file
+// of the dashboard as laid out on the builder canvas, with the ECharts
+// scripts inlined so that it opens offline. With inline=false the scripts
+// are loaded from this server instead.
+func (h *Handler) Export(c *gin.Context) {
+	d, err := h.dashboards.Get(c.Request.Context(), c.Param("id"))
+	if err == nil && d.UserID != c.GetString("userID") {
+		err = dashboards.ErrNotFound
+	}
+	if err != nil {
+		exportError(c, err)
+		return
+	}
+	page := canvasPage(providerName+" dashboard", d.Layout)
+	if c.Query("inline") == "false" {
+		page.SetAssetsHost(assetsHost(c))
+	} else if err := inlineAssets(page); err != nil {
+		exportError(c, err)
+		return
+	}
+	var buf bytes.Buffer
+	if err := page.Render(&buf); err != nil {
+		exportError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+d.ID+`.html"`)
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+func exportError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, dashboards.ErrNotFound) {
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// OEmbed handles GET /oembed?url=, the oEmbed endpoint of embed pages. The
+// iframe is sized as the chart or dashboard in its layout, scaled down to
+// maxwidth and maxheight when given. Only the JSON format is supported.
+func (h *Handler) OEmbed(c *gin.Context) {
+	if format := c.Query("format"); format != "" && format != "json" {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "only the json format is supported"})
+		return
+	}
+	u, err := url.Parse(c.Query("url"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not an embed URL"})
+		return
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 3 || parts[0] != "embed" || (parts[1] != "charts" && parts[1] != "dashboards") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not an embed URL"})
+		return
+	}
+	kind, id, token := parts[1], parts[2], u.Query().Get("token")
+
+	_, d, err := h.shares.Resolve(c.Request.Context(), token, "")
+	if err == nil && kind == "dashboards" && d.ID != id {
+		err = shares.ErrNotFound
+	}
+	var box dashboards.Position
+	title := providerName + " dashboard"
+	if err == nil {
+		if kind == "charts" {
+			item, ok := findChart(d, id)
+			if !ok {
+				err = shares.ErrNotFound
+			}
+			box, title = item.Position, chartTitle(item)
+		} else {
+			box = bounds(d.Layout)
+		}
+	}
+	switch {
+	case errors.Is(err, shares.ErrPasswordRequired):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "password-protected links cannot be embedded"})
+		return
+	case errors.Is(err, shares.ErrNotFound), errors.Is(err, shares.ErrInvalidShare):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	width, height := box.W, box.H
+	if maxWidth, err := strconv.Atoi(c.Query("maxwidth")); err == nil && maxWidth > 0 && width > float64(maxWidth) {
+		width, height = float64(maxWidth), height*float64(maxWidth)/width
+	}
+	if maxHeight, err := strconv.Atoi(c.Query("maxheight")); err == nil && maxHeight > 0 && height > float64(maxHeight) {
+		width, height = width*float64(maxHeight)/height, float64(maxHeight)
+	}
+	w, ht := int(math.Round(width)), int(math.Round(height))
+	src := embedURL(c, kind, id, token)
+	c.JSON(http.StatusOK, OEmbed{
+		Version:      "1.0",
+		Type:         "rich",
+		ProviderName: providerName,
+		ProviderURL:  baseURL(c),
+		Title:        title,
+		HTML: fmt.Sprintf(`<iframe src="%s" width="%d" height="%d" title="%s" style="border: 0;" loading="lazy"></iframe>`,
+			html.EscapeString(src), w, ht, html.EscapeString(title)),
+		Width:  w,
+		Height: ht,
+	})
+}
+
+// discovery returns the oEmbed discovery link of an embed page.
+func (h *Handler) discovery(c *gin.Context, kind, id, token string) string {
+	href := baseURL(c) + "/oembed?" + url.Values{"url": {embedURL(c, kind, id, token)}}.Encode()
+	return fmt.Sprintf(`<link rel="alternate" type="application/json+oembed" href="%s">`, html.EscapeString(href))
+}
+
+// render writes page with headers letting any site frame it.
+func (h *Handler) render(c *gin.Context, page *components.Page) {
+	var buf bytes.Buffer
+	if err := page.Render(&buf); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.Header("Content-Security-Policy", "frame-ancestors *")
+	c.Header("Cache-Control", "private, max-age=60")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// fail reports err as an HTML page, since embed pages are shown in frames
+// rather than read by the frontend.
+func (h *Handler) fail(c *gin.Context, err error) {
+	status, message := http.StatusInternalServerError, "Something went wrong."
+	switch {
+	case errors.Is(err, shares.ErrPasswordRequired):
+		status, message = http.StatusForbidden, "Password-protected links cannot be embedded."
+	case errors.Is(err, shares.ErrNotFound), errors.Is(err, shares.ErrInvalidShare):
+		status, message = http.StatusNotFound, "This link is invalid or has expired."
+	}
+	c.Header("Content-Security-Policy", "frame-ancestors *")
+	c.Data(status, "text/html; charset=utf-8", []byte(fmt.Sprintf(
+		"<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>%s</title></head>\n<body><p>%s</p></body>\n</html>\n",
+		providerName, html.EscapeString(message))))
+}
+
+// findChart returns the chart item id of d, if it has a config.
+func findChart(d *dashboards.Dashboard, id string) (dashboards.ChartItem, bool) {
+	for _, item := range d.Layout.Charts {
+		if item.ID == id && item.ChartConfig != nil {
+			return item, true
+		}
+	}
+	return dashboards.ChartItem{}, false
+}
+
+// chartTitle returns the title text of a chart's config.
+func chartTitle(item dashboards.ChartItem) string {
+	var title map[string]interface{}
+	switch t := item.ChartConfig["title"].(type) {
+	case map[string]interface{}:
+		title = t
+	case []interface{}:
+		if len(t) > 0 {
+			title, _ = t[0].(map[string]interface{})
+		}
+	}
+	if text, _ := title["text"].(string); text != "" {
+		return text
+	}
+	return providerName + " chart"
+}
+
+// embedURL returns the address of an embed page.
+func embedURL(c *gin.Context, kind, id, token string) string {
+	return baseURL(c) + "/embed/" + kind + "/" + url.PathEscape(id) + "?" + url.Values{"token": {token}}.Encode()
+}
+
+// assetsHost returns the address this server serves the go-echarts assets
+// at.
+func assetsHost(c *gin.Context) string {
+	return baseURL(c) + "/assets/"
+}
+
+// baseURL returns the scheme and host the request was made to.
+func baseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
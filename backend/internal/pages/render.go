@@ -0,0 +1,208 @@
+// Package pages renders dashboards and their charts as standalone HTML
+// pages through the go-echarts page templates, for embedding in other
+// sites.
+package pages
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/mjrtuhin/loomis-backend/internal/dashboards"
+)
+
+// storedOption is a go-echarts visitor that swaps the options a chart
+// builds for a chart config generated earlier, so that stored configs
+// render through the go-echarts templates.
+type storedOption struct {
+	charts.BaseConfigurationVisitor
+	option map[string]interface{}
+}
+
+func (v storedOption) Visit(chart map[string]interface{}) {
+	for k := range chart {
+		delete(chart, k)
+	}
+	for k, val := range v.option {
+		chart[k] = val
+	}
+}
+
+// funcMarker wraps the JavaScript functions of go-echarts options: page
+// rendering strips it along with the quotes around it, so that the string
+// between becomes code.
+const funcMarker = "__f__"
+
+// escapeOption returns a copy of v, part of a stored chart config, that
+// cannot run code once rendered in a page script: strings holding the
+// function marker or HTML special characters are written with those
+// characters as JSON escapes, and keys holding them, which ECharts never
+// reads, are dropped.
+func escapeOption(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		if !strings.Contains(v, funcMarker) && !strings.ContainsAny(v, "<>&") {
+			return v
+		}
+		// json.Marshal escapes <, > and &.
+		b, _ := json.Marshal(v)
+		return json.RawMessage(strings.ReplaceAll(string(b), "_", `\u005f`))
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			if strings.Contains(k, funcMarker) || strings.ContainsAny(k, "<>&") {
+				continue
+			}
+			out[k] = escapeOption(val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, val := range v {
+			out[i] = escapeOption(val)
+		}
+		return out
+	}
+	return v
+}
+
+// resizeJS keeps a chart sized to its container as the window resizes.
+const resizeJS = "window.addEventListener('resize', function () { %MY_ECHARTS%.resize(); });"
+
+// glSeries are the series types drawn by echarts-gl.
+var glSeries = map[string]bool{
+	"bar3D": true, "line3D": true, "scatter3D": true, "surface": true,
+	"lines3D": true, "map3D": true, "scatterGL": true, "linesGL": true,
+	"flowGL": true, "graphGL": true,
+}
+
+// newChart returns a chart drawing option in a width by height box. It is
+// built from the go-echarts chart type that loads the scripts option
+// needs: echarts-gl, the word cloud or liquid fill extensions, or a preset
+// map. option is escaped, so that a stored config can't inject code. id
+// becomes part of JS identifiers and must be alphanumeric.
+func newChart(id string, option map[string]interface{}, width, height string) components.Charter {
+	var (
+		chart components.Charter
+		base  *charts.BaseConfiguration
+	)
+	switch kind, mapName := optionKind(option); kind {
+	case "gl":
+		c := charts.NewBar3D()
+		chart, base = c, &c.BaseConfiguration
+	case "wordCloud":
+		c := charts.NewWordCloud()
+		chart, base = c, &c.BaseConfiguration
+	case "liquidFill":
+		c := charts.NewLiquid()
+		chart, base = c, &c.BaseConfiguration
+	case "map":
+		c := charts.NewMap()
+		c.RegisterMapType(mapName)
+		chart, base = c, &c.BaseConfiguration
+	default:
+		c := charts.NewCustom()
+		chart, base = c, &c.BaseConfiguration
+	}
+	charts.WithInitializationOpts(opts.Initialization{ChartID: id, Width: width, Height: height})(base)
+	base.AddJSFuncStrs(resizeJS)
+	base.Accept(storedOption{option: escapeOption(option).(map[string]interface{})})
+	return chart
+}
+
+// optionKind tells which scripts an ECharts option needs beyond the
+// ECharts core: "gl", "wordCloud", "liquidFill" or "map" with the name of
+// the map. Only the maps bundled with go-echarts can be loaded.
+func optionKind(option map[string]interface{}) (kind, mapName string) {
+	for _, key := range []string{"globe", "grid3D", "geo3D"} {
+		if _, ok := option[key]; ok {
+			return "gl", ""
+		}
+	}
+	if geo, ok := option["geo"].(map[string]interface{}); ok {
+		if name, ok := geo["map"].(string); ok {
+			mapName = name
+		}
+	}
+	var series []interface{}
+	switch s := option["series"].(type) {
+	case []interface{}:
+		series = s
+	case map[string]interface{}:
+		series = []interface{}{s}
+	}
+	for _, s := range series {
+		s, _ := s.(map[string]interface{})
+		switch t, _ := s["type"].(string); {
+		case glSeries[t]:
+			return "gl", ""
+		case t == "wordCloud", t == "liquidFill":
+			return t, ""
+		case t == "map":
+			if name, ok := s["map"].(string); ok {
+				mapName = name
+			}
+		}
+	}
+	if mapName != "" {
+		return "map", mapName
+	}
+	return "", ""
+}
+
+// textBlockStyle styles the text blocks of canvas pages.
+const textBlockStyle = `<style>html, body { margin: 0; } .text-block { height: 100%; box-sizing: border-box; padding: 12px; font-family: sans-serif; }</style>`
+
+// canvasPage returns a page drawing the charts and text blocks of layout
+// at their place and size on the builder canvas. Text blocks are
+// sanitized, since pages are served from the API origin.
+func canvasPage(title string, layout dashboards.Layout) *components.Page {
+	page := components.NewPage()
+	page.SetPageTitle(title)
+	page.SetLayout(components.PageGridLayout)
+	page.AddCustomizedHeaders(textBlockStyle)
+	n := 0
+	for _, item := range layout.Charts {
+		if item.ChartConfig == nil {
+			continue
+		}
+		n++
+		page.AddChartAt(newChart(fmt.Sprintf("chart%d", n), item.ChartConfig, px(item.Position.W), px(item.Position.H)), gridPosition(item.Position))
+	}
+	for _, block := range layout.TextBlocks {
+		html := template.HTML(`<div class="text-block">` + sanitizeHTML(block.Content) + `</div>`)
+		page.AddHTMLAt(html, gridPosition(block.Position))
+	}
+	return page
+}
+
+// px formats v as a CSS length in whole pixels.
+func px(v float64) string {
+	return strconv.FormatFloat(math.Round(v), 'f', -1, 64) + "px"
+}
+
+func gridPosition(p dashboards.Position) components.GridPosition {
+	return components.GridPosition{Left: p.X, Top: p.Y, Width: p.W, Height: p.H}
+}
+
+// bounds returns the box holding every item of layout.
+func bounds(layout dashboards.Layout) dashboards.Position {
+	var box dashboards.Position
+	grow := func(p dashboards.Position) {
+		box.W = math.Max(box.W, p.X+p.W)
+		box.H = math.Max(box.H, p.Y+p.H)
+	}
+	for _, item := range layout.Charts {
+		grow(item.Position)
+	}
+	for _, block := range layout.TextBlocks {
+		grow(block.Position)
+	}
+	return box
+}
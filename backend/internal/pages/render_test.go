@@ -0,0 +1,31 @@
+package pages
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-echarts/go-echarts/v2/components"
+)
+
+func TestNewChartEscapesStoredConfig(t *testing.T) {
+	option := map[string]interface{}{
+		"title":    map[string]interface{}{"text": `__f__alert(1)__f__ </script><script>alert(2)</script>`},
+		"__f__key": "dropped",
+		"series":   []interface{}{map[string]interface{}{"type": "bar", "name": "a_b", "data": []interface{}{1.0, 2.0}}},
+	}
+	page := components.NewPage()
+	page.AddCharts(newChart("chart1", option, "100%", "100%"))
+	html := string(page.RenderContent())
+
+	for _, bad := range []string{"alert(1)__f__", `"alert(1)`, "<script>alert(2)", "dropped"} {
+		if strings.Contains(html, bad) {
+			t.Errorf("page contains %q", bad)
+		}
+	}
+	if want := `"text":"\u005f\u005ff\u005f\u005falert(1)`; !strings.Contains(html, want) {
+		t.Errorf("page does not contain %q", want)
+	}
+	if want := `"name":"a_b"`; !strings.Contains(html, want) {
+		t.Errorf("page does not contain %q", want)
+	}
+}
@@ -0,0 +1,174 @@
+package pages
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// allowedAttrs lists the elements text blocks may hold, those the
+// dashboard's rich text editor produces, with the attributes each may
+// have besides style.
+var allowedAttrs = map[string][]string{
+	"p": nil, "br": nil, "hr": nil, "span": nil, "div": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"strong": nil, "b": nil, "em": nil, "i": nil, "u": nil, "s": nil,
+	"code": nil, "pre": nil, "blockquote": nil, "mark": {"data-color"},
+	"ul": nil, "ol": {"start"}, "li": nil,
+	"a":   {"href", "title", "target", "rel"},
+	"img": {"src", "alt", "title", "width", "height"},
+}
+
+// voidElements are the allowed elements without end tags.
+var voidElements = map[string]bool{"br": true, "hr": true, "img": true}
+
+// droppedContent are the elements whose content is dropped along with
+// them, rather than kept as text.
+var droppedContent = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "noembed": true, "noframes": true, "template": true,
+	"textarea": true, "title": true, "xmp": true, "svg": true, "math": true,
+}
+
+// allowedStyles are the CSS properties the editor sets.
+var allowedStyles = map[string]bool{
+	"color": true, "background-color": true, "font-family": true, "font-size": true,
+	"font-weight": true, "font-style": true, "text-align": true, "text-decoration": true,
+}
+
+// styleValue matches CSS values without functions other than colors, nor
+// escapes or comments.
+var styleValue = regexp.MustCompile(`^(?:[#\w\s.,%'"-]+|rgba?\([\d\s.,%]+\))$`)
+
+// sanitizeHTML returns the text block content s with only the elements,
+// attributes, URLs and styles the editor produces, balanced so that it
+// can't close the elements around it. Text and attribute values are
+// escaped, underscores included, so that the function markers of
+// go-echarts pages can't appear in it.
+func sanitizeHTML(s string) string {
+	var b strings.Builder
+	var open []string
+	skip := ""
+	z := nethtml.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		if tt == nethtml.ErrorToken {
+			// io.EOF, or input too long for the tokenizer's buffer.
+			break
+		}
+		tok := z.Token()
+		if skip != "" {
+			if tt == nethtml.EndTagToken && tok.Data == skip {
+				skip = ""
+			}
+			continue
+		}
+		switch tt {
+		case nethtml.TextToken:
+			b.WriteString(escapeText(tok.Data))
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			if droppedContent[tok.Data] {
+				if tt == nethtml.StartTagToken {
+					skip = tok.Data
+				}
+				continue
+			}
+			attrs, ok := allowedAttrs[tok.Data]
+			if !ok {
+				continue
+			}
+			b.WriteString("<" + tok.Data)
+			for _, attr := range tok.Attr {
+				if value, ok := sanitizeAttr(tok.Data, attr, attrs); ok {
+					b.WriteString(" " + attr.Key + `="` + escapeText(value) + `"`)
+				}
+			}
+			b.WriteString(">")
+			if !voidElements[tok.Data] {
+				open = append(open, tok.Data)
+			}
+		case nethtml.EndTagToken:
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == tok.Data {
+					for _, name := range reverse(open[i:]) {
+						b.WriteString("</" + name + ">")
+					}
+					open = open[:i]
+					break
+				}
+			}
+		}
+	}
+	for _, name := range reverse(open) {
+		b.WriteString("</" + name + ">")
+	}
+	return b.String()
+}
+
+// sanitizeAttr returns the value attr of element keeps, if it is allowed.
+func sanitizeAttr(element string, attr nethtml.Attribute, allowed []string) (string, bool) {
+	if attr.Namespace != "" {
+		return "", false
+	}
+	switch attr.Key {
+	case "style":
+		style := sanitizeStyle(attr.Val)
+		return style, style != ""
+	case "href":
+		return attr.Val, element == "a" && safeURL(attr.Val, "http:", "https:", "mailto:")
+	case "src":
+		return attr.Val, element == "img" && safeURL(attr.Val, "http:", "https:",
+			"data:image/png", "data:image/jpeg", "data:image/gif", "data:image/webp")
+	}
+	for _, key := range allowed {
+		if attr.Key == key {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+// safeURL tells whether u starts with one of prefixes, ignoring case and
+// surrounding space.
+func safeURL(u string, prefixes ...string) bool {
+	u = strings.ToLower(strings.TrimSpace(u))
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(u, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeStyle keeps the declarations of style setting allowedStyles to
+// plain values.
+func sanitizeStyle(style string) string {
+	var kept []string
+	for _, decl := range strings.Split(style, ";") {
+		prop, value, ok := strings.Cut(decl, ":")
+		if !ok {
+			continue
+		}
+		prop, value = strings.ToLower(strings.TrimSpace(prop)), strings.TrimSpace(value)
+		if allowedStyles[prop] && styleValue.MatchString(value) {
+			kept = append(kept, prop+": "+value)
+		}
+	}
+	return strings.Join(kept, "; ")
+}
+
+// escapeText escapes s for HTML text and attribute values, writing
+// underscores as character references.
+func escapeText(s string) string {
+	return strings.ReplaceAll(html.EscapeString(s), "_", "&#95;")
+}
+
+func reverse(names []string) []string {
+	out := make([]string, len(names))
+	for i, name := range names {
+		out[len(names)-1-i] = name
+	}
+	return out
+}
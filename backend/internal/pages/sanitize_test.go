@@ -0,0 +1,30 @@
+package pages
+
+import "testing"
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"editor output", `<h2 style="text-align: center">Sales</h2><p><strong>Up</strong> <mark data-color="#ff0">10%</mark></p>`,
+			`<h2 style="text-align: center">Sales</h2><p><strong>Up</strong> <mark data-color="#ff0">10%</mark></p>`},
+		{"script", `<p>a<script>alert(1)</script>b</p>`, `<p>ab</p>`},
+		{"event handler", `<img src="x.png" onerror="alert(1)">`, `<img>`},
+		{"image", `<img src="https://example.com/x.png" alt="x">`, `<img src="https://example.com/x.png" alt="x">`},
+		{"javascript URL", `<a href=" JavaScript:alert(1)">x</a>`, `<a>x</a>`},
+		{"link", `<a href="https://example.com/?a=1&b=2" target="_blank">x</a>`, `<a href="https://example.com/?a=1&amp;b=2" target="&#95;blank">x</a>`},
+		{"unknown element", `<form action="/x"><p>x</p></form>`, `<p>x</p>`},
+		{"style", `<span style="color: rgb(1, 2, 3); background: url(x); font-size: expression(alert(1))">x</span>`,
+			`<span style="color: rgb(1, 2, 3)">x</span>`},
+		{"unbalanced", `<p><em>x</div></div><b>y`, `<p><em>x<b>y</b></em></p>`},
+		{"function marker", `<a title="__f__">__f__alert(1)__f__</a>`, `<a title="&#95;&#95;f&#95;&#95;">&#95;&#95;f&#95;&#95;alert(1)&#95;&#95;f&#95;&#95;</a>`},
+		{"text", `1 < 2 & "3"`, `1 &lt; 2 &amp; &#34;3&#34;`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeHTML(tt.in); got != tt.want {
+				t.Errorf("sanitizeHTML(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
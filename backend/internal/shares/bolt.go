@@ -0,0 +1,105 @@
+package shares
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/mjrtuhin/loomis-backend/internal/storage"
+)
+
+// bucket holds share links in the embedded database, keyed by ID.
+const bucket = "shares"
+
+type boltStore struct {
+	db *storage.DB
+}
+
+// NewBoltStore creates a Store in the embedded database db.
+func NewBoltStore(db *storage.DB) Store {
+	return &boltStore{db: db}
+}
+
+func (s *boltStore) List(_ context.Context, dashboardID string) ([]Share, error) {
+	var out []Share
+	err := s.db.View(func(tx *storage.Tx) error {
+		return tx.ForEach(bucket, func(_ string, raw json.RawMessage) error {
+			var sh Share
+			if err := json.Unmarshal(raw, &sh); err != nil {
+				return err
+			}
+			if sh.DashboardID == dashboardID {
+				out = append(out, sh)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortByCreated(out)
+	return out, nil
+}
+
+func (s *boltStore) Get(_ context.Context, id string) (*Share, error) {
+	var sh Share
+	found, err := s.db.Get(bucket, id, &sh)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrNotFound
+	}
+	return &sh, nil
+}
+
+func (s *boltStore) Create(_ context.Context, sh Share) (*Share, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	sh.ID = id
+	if err := s.db.Put(bucket, id, sh); err != nil {
+		return nil, err
+	}
+	return &sh, nil
+}
+
+func (s *boltStore) Revoke(_ context.Context, id string, at time.Time) error {
+	return s.update(id, func(sh *Share) {
+		sh.RevokedAt = &at
+	})
+}
+
+func (s *boltStore) AddView(_ context.Context, id string) error {
+	return s.update(id, func(sh *Share) {
+		sh.Views++
+	})
+}
+
+// update applies fn to the link id in a single transaction.
+func (s *boltStore) update(id string, fn func(sh *Share)) error {
+	return s.db.Update(func(tx *storage.Tx) error {
+		var sh Share
+		found, err := tx.Get(bucket, id, &sh)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrNotFound
+		}
+		fn(&sh)
+		return tx.Put(bucket, id, sh)
+	})
+}
+
+// sortByCreated orders links newest first.
+func sortByCreated(list []Share) {
+	sort.Slice(list, func(a, b int) bool {
+		if !list[a].CreatedAt.Equal(list[b].CreatedAt) {
+			return list[a].CreatedAt.After(list[b].CreatedAt)
+		}
+		return list[a].ID < list[b].ID
+	})
+}
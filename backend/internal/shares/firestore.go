@@ -0,0 +1,99 @@
+package shares
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// collection is the Firestore collection of share links.
+const collection = "shares"
+
+type firestoreStore struct {
+	client *firestore.Client
+}
+
+// NewFirestoreStore creates a Store on the shares collection of the
+// Firebase project. An empty projectID is detected from the credentials.
+func NewFirestoreStore(ctx context.Context, projectID, credentialsJSON string) (Store, error) {
+	if projectID == "" {
+		projectID = firestore.DetectProjectID
+	}
+	client, err := firestore.NewClient(ctx, projectID, option.WithCredentialsJSON([]byte(credentialsJSON)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Firestore: %w", err)
+	}
+	return &firestoreStore{client: client}, nil
+}
+
+func (s *firestoreStore) List(ctx context.Context, dashboardID string) ([]Share, error) {
+	docs, err := s.client.Collection(collection).Where("dashboardId", "==", dashboardID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Share, 0, len(docs))
+	for _, doc := range docs {
+		sh, err := fromDoc(doc)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *sh)
+	}
+	sortByCreated(out)
+	return out, nil
+}
+
+func (s *firestoreStore) Get(ctx context.Context, id string) (*Share, error) {
+	doc, err := s.client.Collection(collection).Doc(id).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fromDoc(doc)
+}
+
+func (s *firestoreStore) Create(ctx context.Context, sh Share) (*Share, error) {
+	// Link IDs end up in tokens, so they come from newID rather than
+	// Firestore's shorter document IDs.
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	sh.ID = id
+	if _, err := s.client.Collection(collection).Doc(id).Create(ctx, sh); err != nil {
+		return nil, err
+	}
+	return &sh, nil
+}
+
+func (s *firestoreStore) Revoke(ctx context.Context, id string, at time.Time) error {
+	return s.update(ctx, id, firestore.Update{Path: "revokedAt", Value: at})
+}
+
+func (s *firestoreStore) AddView(ctx context.Context, id string) error {
+	return s.update(ctx, id, firestore.Update{Path: "views", Value: firestore.Increment(1)})
+}
+
+func (s *firestoreStore) update(ctx context.Context, id string, u firestore.Update) error {
+	_, err := s.client.Collection(collection).Doc(id).Update(ctx, []firestore.Update{u})
+	if status.Code(err) == codes.NotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+func fromDoc(doc *firestore.DocumentSnapshot) (*Share, error) {
+	var sh Share
+	if err := doc.DataTo(&sh); err != nil {
+		return nil, fmt.Errorf("failed to read share %s: %w", doc.Ref.ID, err)
+	}
+	sh.ID = doc.Ref.ID
+	return &sh, nil
+}
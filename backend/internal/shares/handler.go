@@ -0,0 +1,264 @@
+package shares
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjrtuhin/loomis-backend/internal/auth"
+	"github.com/mjrtuhin/loomis-backend/internal/dashboards"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	defaultTTL = 30 * 24 * time.Hour
+	maxTTL     = 365 * 24 * time.Hour
+	// PasswordHeader carries the password of protected links.
+	PasswordHeader = "X-Share-Password"
+)
+
+type Handler struct {
+	store      Store
+	dashboards dashboards.Store
+	signer     *auth.Signer
+}
+
+// NewHandler creates a Handler for the links of dashboards in
+// dashboardStore, whose tokens are signed by signer.
+func NewHandler(store Store, dashboardStore dashboards.Store, signer *auth.Signer) *Handler {
+	return &Handler{store: store, dashboards: dashboardStore, signer: signer}
+}
+
+// ShareRequest is the body of share link creates.
+type ShareRequest struct {
+	// ExpiresAt defaults to 30 days from now and may be at most a year
+	// away.
+	ExpiresAt *time.Time `json:"expiresAt"`
+	// Password, when set, must be given to open the link.
+	Password string `json:"password"`
+}
+
+// Link is a share link as shown to the dashboard's owner.
+type Link struct {
+	ID          string     `json:"id"`
+	DashboardID string     `json:"dashboardId"`
+	Token       string     `json:"token"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	ExpiresAt   time.Time  `json:"expiresAt"`
+	RevokedAt   *time.Time `json:"revokedAt,omitempty"`
+	Protected   bool       `json:"protected"`
+	Views       int        `json:"views"`
+}
+
+// SharedDashboard is the read-only view of a dashboard opened through a
+// link. It leaves out the owner and the sheet.
+type SharedDashboard struct {
+	RefreshInterval int               `json:"refreshInterval"`
+	Layout          dashboards.Layout `json:"layout"`
+	UpdatedAt       time.Time         `json:"updatedAt"`
+	ExpiresAt       time.Time         `json:"expiresAt"`
+}
+
+// tokenClaims are the signed contents of a share token.
+type tokenClaims struct {
+	ShareID string `json:"sid"`
+	Expires int64  `json:"exp"`
+}
+
+// List handles GET /api/dashboards/:id/shares.
+func (h *Handler) List(c *gin.Context) {
+	d, ok := h.owned(c)
+	if !ok {
+		return
+	}
+	list, err := h.store.List(c.Request.Context(), d.ID)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	links := make([]Link, 0, len(list))
+	for i := range list {
+		link, err := h.link(&list[i])
+		if err != nil {
+			h.fail(c, err)
+			return
+		}
+		links = append(links, link)
+	}
+	c.JSON(http.StatusOK, gin.H{"shares": links})
+}
+
+// Create handles POST /api/dashboards/:id/shares, returning the new link
+// with its token.
+func (h *Handler) Create(c *gin.Context) {
+	d, ok := h.owned(c)
+	if !ok {
+		return
+	}
+	// An empty body asks for a link with the defaults.
+	var req ShareRequest
+	dec := json.NewDecoder(c.Request.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now().UTC()
+	sh := Share{DashboardID: d.ID, Owner: c.GetString("userID"), CreatedAt: now, ExpiresAt: now.Add(defaultTTL)}
+	if req.ExpiresAt != nil {
+		sh.ExpiresAt = req.ExpiresAt.UTC()
+		if !sh.ExpiresAt.After(now) || sh.ExpiresAt.Sub(now) > maxTTL {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expiresAt must be in the future and at most a year away"})
+			return
+		}
+	}
+	if req.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			h.fail(c, err)
+			return
+		}
+		sh.PasswordHash = hash
+	}
+
+	saved, err := h.store.Create(c.Request.Context(), sh)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	link, err := h.link(saved)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, link)
+}
+
+// Revoke handles DELETE /api/dashboards/:id/shares/:shareId. Revoking a
+// revoked link does nothing.
+func (h *Handler) Revoke(c *gin.Context) {
+	d, ok := h.owned(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	sh, err := h.store.Get(ctx, c.Param("shareId"))
+	if err == nil && sh.DashboardID != d.ID {
+		err = ErrNotFound
+	}
+	if err == nil && sh.RevokedAt == nil {
+		err = h.store.Revoke(ctx, sh.ID, time.Now().UTC())
+	}
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// View handles GET /api/shared/:token, the unauthenticated read-only view
+// of a shared dashboard. Protected links need their password in the
+// X-Share-Password header.
+func (h *Handler) View(c *gin.Context) {
+	sh, d, err := h.Open(c.Request.Context(), c.Param("token"), c.GetHeader(PasswordHeader))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SharedDashboard{
+		RefreshInterval: d.RefreshInterval,
+		Layout:          d.Layout,
+		UpdatedAt:       d.UpdatedAt,
+		ExpiresAt:       sh.ExpiresAt,
+	})
+}
+
+// Open resolves token like Resolve and counts a view of the link.
+func (h *Handler) Open(ctx context.Context, token, password string) (*Share, *dashboards.Dashboard, error) {
+	sh, d, err := h.Resolve(ctx, token, password)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := h.store.AddView(ctx, sh.ID); err != nil {
+		log.Printf("Failed to count view of share %s: %v", sh.ID, err)
+	}
+	return sh, d, nil
+}
+
+// Resolve returns the link and dashboard token grants access to, checking
+// password for protected links. It returns ErrInvalidShare for bad,
+// expired and revoked tokens and ErrPasswordRequired for wrong passwords.
+func (h *Handler) Resolve(ctx context.Context, token, password string) (*Share, *dashboards.Dashboard, error) {
+	var claims tokenClaims
+	now := time.Now()
+	if err := h.signer.Verify(token, &claims); err != nil || now.Unix() >= claims.Expires {
+		return nil, nil, ErrInvalidShare
+	}
+	sh, err := h.store.Get(ctx, claims.ShareID)
+	if errors.Is(err, ErrNotFound) || (err == nil && !sh.active(now)) {
+		return nil, nil, ErrInvalidShare
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if sh.PasswordHash != nil && bcrypt.CompareHashAndPassword(sh.PasswordHash, []byte(password)) != nil {
+		return nil, nil, ErrPasswordRequired
+	}
+	d, err := h.dashboards.Get(ctx, sh.DashboardID)
+	if errors.Is(err, dashboards.ErrNotFound) {
+		return nil, nil, ErrInvalidShare
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return sh, d, nil
+}
+
+// link signs the token of sh.
+func (h *Handler) link(sh *Share) (Link, error) {
+	token, err := h.signer.Sign(tokenClaims{ShareID: sh.ID, Expires: sh.ExpiresAt.Unix()})
+	if err != nil {
+		return Link{}, err
+	}
+	return Link{
+		ID:          sh.ID,
+		DashboardID: sh.DashboardID,
+		Token:       token,
+		CreatedAt:   sh.CreatedAt,
+		ExpiresAt:   sh.ExpiresAt,
+		RevokedAt:   sh.RevokedAt,
+		Protected:   sh.PasswordHash != nil,
+		Views:       sh.Views,
+	}, nil
+}
+
+// owned returns the dashboard in the path, failing the request unless the
+// caller owns it. Other users' dashboards are reported as not found.
+func (h *Handler) owned(c *gin.Context) (*dashboards.Dashboard, bool) {
+	d, err := h.dashboards.Get(c.Request.Context(), c.Param("id"))
+	if err == nil && d.UserID != c.GetString("userID") {
+		err = dashboards.ErrNotFound
+	}
+	if err != nil {
+		h.fail(c, err)
+		return nil, false
+	}
+	return d, true
+}
+
+func (h *Handler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrPasswordRequired):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "passwordRequired": true})
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrInvalidShare), errors.Is(err, dashboards.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,272 @@
+package shares
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjrtuhin/loomis-backend/internal/auth"
+	"github.com/mjrtuhin/loomis-backend/internal/dashboards"
+	"github.com/mjrtuhin/loomis-backend/internal/storage"
+)
+
+// testEnv is a share handler routed as user u1, with a dashboard of u1 and
+// one of u2.
+type testEnv struct {
+	t          *testing.T
+	handler    *Handler
+	store      Store
+	dashboards dashboards.Store
+	router     *gin.Engine
+	mine       *dashboards.Dashboard
+	theirs     *dashboards.Dashboard
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	db, err := storage.Open(filepath.Join(t.TempDir(), "loomis.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	dashboardStore, err := dashboards.NewFileStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := &testEnv{t: t, store: NewBoltStore(db), dashboards: dashboardStore}
+	env.handler = NewHandler(env.store, dashboardStore, auth.NewSigner([]byte("secret")))
+	for _, owner := range []string{"u1", "u2"} {
+		d, err := dashboardStore.Create(context.Background(), dashboards.Dashboard{
+			UserID:          owner,
+			GoogleSheetURL:  "https://docs.google.com/spreadsheets/d/abc/edit",
+			RefreshInterval: 60,
+			Layout:          dashboards.Layout{Charts: []dashboards.ChartItem{}, TextBlocks: []dashboards.TextBlock{}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if owner == "u1" {
+			env.mine = d
+		} else {
+			env.theirs = d
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	env.router = gin.New()
+	env.router.GET("/api/shared/:token", env.handler.View)
+	api := env.router.Group("/api/dashboards", func(c *gin.Context) { c.Set("userID", "u1") })
+	api.GET("/:id/shares", env.handler.List)
+	api.POST("/:id/shares", env.handler.Create)
+	api.DELETE("/:id/shares/:shareId", env.handler.Revoke)
+	return env
+}
+
+func (env *testEnv) do(method, path, body string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	env.router.ServeHTTP(w, req)
+	return w
+}
+
+// create makes a link of the caller's dashboard from body.
+func (env *testEnv) create(body string) Link {
+	env.t.Helper()
+	w := env.do(http.MethodPost, "/api/dashboards/"+env.mine.ID+"/shares", body, nil)
+	if w.Code != http.StatusCreated {
+		env.t.Fatalf("create status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	var link Link
+	if err := json.Unmarshal(w.Body.Bytes(), &link); err != nil {
+		env.t.Fatal(err)
+	}
+	return link
+}
+
+func (env *testEnv) view(token, password string) int {
+	header := http.Header{}
+	if password != "" {
+		header.Set(PasswordHeader, password)
+	}
+	return env.do(http.MethodGet, "/api/shared/"+token, "", header).Code
+}
+
+func TestShareLink(t *testing.T) {
+	env := newTestEnv(t)
+	link := env.create(`{}`)
+	if want := time.Now().Add(defaultTTL); link.ExpiresAt.Sub(want).Abs() > time.Minute {
+		t.Errorf("expiresAt = %v, want about %v", link.ExpiresAt, want)
+	}
+	if link.Protected {
+		t.Error("link without a password is protected")
+	}
+
+	w := env.do(http.MethodGet, "/api/shared/"+link.Token, "", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("view status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	if strings.Contains(w.Body.String(), env.mine.GoogleSheetURL) || strings.Contains(w.Body.String(), "u1") {
+		t.Errorf("shared view %s shows the sheet or the owner", w.Body)
+	}
+
+	w = env.do(http.MethodGet, "/api/dashboards/"+env.mine.ID+"/shares", "", nil)
+	var list struct {
+		Shares []Link `json:"shares"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Shares) != 1 || list.Shares[0].ID != link.ID || list.Shares[0].Views != 1 || list.Shares[0].Token != link.Token {
+		t.Errorf("shares = %+v, want the link with one view", list.Shares)
+	}
+}
+
+func TestShareEmptyBody(t *testing.T) {
+	env := newTestEnv(t)
+	link := env.create("")
+	if want := time.Now().Add(defaultTTL); link.Protected || link.ExpiresAt.Sub(want).Abs() > time.Minute {
+		t.Errorf("link from an empty body = %+v, want an open link expiring about %v", link, want)
+	}
+}
+
+func TestShareCreateErrors(t *testing.T) {
+	env := newTestEnv(t)
+	tests := []struct {
+		name, dashboard, body string
+		want                  int
+	}{
+		{"past expiry", env.mine.ID, `{"expiresAt": "2020-01-01T00:00:00Z"}`, http.StatusBadRequest},
+		{"expiry over a year away", env.mine.ID, `{"expiresAt": "` + time.Now().Add(maxTTL+time.Hour).Format(time.RFC3339) + `"}`, http.StatusBadRequest},
+		{"unknown field", env.mine.ID, `{"expires": "tomorrow"}`, http.StatusBadRequest},
+		{"other user's dashboard", env.theirs.ID, `{}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := env.do(http.MethodPost, "/api/dashboards/"+tt.dashboard+"/shares", tt.body, nil); w.Code != tt.want {
+				t.Errorf("create status = %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+		})
+	}
+}
+
+func TestShareExpiry(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	expired, err := env.store.Create(ctx, Share{DashboardID: env.mine.ID, Owner: "u1", CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	link, err := env.handler.link(expired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := env.view(link.Token, ""); code != http.StatusNotFound {
+		t.Errorf("view of an expired link status = %d, want %d", code, http.StatusNotFound)
+	}
+
+	// A token whose own expiry has passed is refused even while its link
+	// is still active.
+	active, err := env.store.Create(ctx, Share{DashboardID: env.mine.ID, Owner: "u1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := env.handler.signer.Sign(tokenClaims{ShareID: active.ID, Expires: now.Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := env.view(token, ""); code != http.StatusNotFound {
+		t.Errorf("view with an expired token status = %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestShareRevoke(t *testing.T) {
+	env := newTestEnv(t)
+	link := env.create(`{}`)
+	path := "/api/dashboards/" + env.mine.ID + "/shares/" + link.ID
+	if w := env.do(http.MethodDelete, path, "", nil); w.Code != http.StatusNoContent {
+		t.Fatalf("revoke status = %d, want %d: %s", w.Code, http.StatusNoContent, w.Body)
+	}
+	if code := env.view(link.Token, ""); code != http.StatusNotFound {
+		t.Errorf("view of a revoked link status = %d, want %d", code, http.StatusNotFound)
+	}
+	sh, err := env.store.Get(context.Background(), link.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revokedAt := *sh.RevokedAt
+	if w := env.do(http.MethodDelete, path, "", nil); w.Code != http.StatusNoContent {
+		t.Errorf("second revoke status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if sh, err := env.store.Get(context.Background(), link.ID); err != nil || !sh.RevokedAt.Equal(revokedAt) {
+		t.Errorf("second revoke moved the revocation time to %v, want %v", sh.RevokedAt, revokedAt)
+	}
+
+	for name, path := range map[string]string{
+		"unknown link":              "/api/dashboards/" + env.mine.ID + "/shares/missing",
+		"link of another dashboard": "/api/dashboards/" + env.theirs.ID + "/shares/" + link.ID,
+	} {
+		if w := env.do(http.MethodDelete, path, "", nil); w.Code != http.StatusNotFound {
+			t.Errorf("revoke of %s status = %d, want %d", name, w.Code, http.StatusNotFound)
+		}
+	}
+}
+
+func TestSharePassword(t *testing.T) {
+	env := newTestEnv(t)
+	link := env.create(`{"password": "hunter2"}`)
+	if !link.Protected {
+		t.Error("link with a password is not protected")
+	}
+	sh, err := env.store.Get(context.Background(), link.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sh.PasswordHash) == 0 || strings.Contains(string(sh.PasswordHash), "hunter2") {
+		t.Errorf("stored password hash = %q, want a bcrypt hash", sh.PasswordHash)
+	}
+
+	w := env.do(http.MethodGet, "/api/shared/"+link.Token, "", nil)
+	if w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), `"passwordRequired":true`) {
+		t.Errorf("view without a password = %d %s, want %d asking for the password", w.Code, w.Body, http.StatusUnauthorized)
+	}
+	if code := env.view(link.Token, "hunter3"); code != http.StatusUnauthorized {
+		t.Errorf("view with a wrong password status = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := env.view(link.Token, "hunter2"); code != http.StatusOK {
+		t.Errorf("view with the password status = %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestShareInvalidToken(t *testing.T) {
+	env := newTestEnv(t)
+	link := env.create(`{}`)
+	forged, err := auth.NewSigner([]byte("other")).Sign(tokenClaims{ShareID: link.ID, Expires: link.ExpiresAt.Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, token := range map[string]string{
+		"malformed":    "not-a-token",
+		"other secret": forged,
+	} {
+		if code := env.view(token, ""); code != http.StatusNotFound {
+			t.Errorf("view with a %s token status = %d, want %d", name, code, http.StatusNotFound)
+		}
+	}
+
+	if err := env.dashboards.Delete(context.Background(), env.mine.ID); err != nil {
+		t.Fatal(err)
+	}
+	if code := env.view(link.Token, ""); code != http.StatusNotFound {
+		t.Errorf("view of a deleted dashboard status = %d, want %d", code, http.StatusNotFound)
+	}
+}
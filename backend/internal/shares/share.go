@@ -0,0 +1,64 @@
+// Package shares implements public share links: signed, expiring tokens
+// that give anyone holding them read-only access to a dashboard until they
+// expire or are revoked.
+package shares
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned for unknown share IDs.
+	ErrNotFound = errors.New("share not found")
+	// ErrInvalidShare is returned for share tokens that are malformed,
+	// expired, revoked or whose dashboard is gone.
+	ErrInvalidShare = errors.New("share link is invalid or has expired")
+	// ErrPasswordRequired is returned when a share's password is missing
+	// or wrong.
+	ErrPasswordRequired = errors.New("share link needs a valid password")
+)
+
+// Share is a share link of a dashboard.
+type Share struct {
+	ID          string    `json:"id" firestore:"-"`
+	DashboardID string    `json:"dashboardId" firestore:"dashboardId"`
+	Owner       string    `json:"owner" firestore:"owner"`
+	CreatedAt   time.Time `json:"createdAt" firestore:"createdAt"`
+	ExpiresAt   time.Time `json:"expiresAt" firestore:"expiresAt"`
+	// RevokedAt is set once the link is revoked.
+	RevokedAt *time.Time `json:"revokedAt,omitempty" firestore:"revokedAt,omitempty"`
+	// PasswordHash is the bcrypt hash of the password, if any.
+	PasswordHash []byte `json:"passwordHash,omitempty" firestore:"passwordHash,omitempty"`
+	// Views counts the times the dashboard was opened through the link.
+	Views int `json:"views" firestore:"views"`
+}
+
+// active reports whether the link still grants access at now.
+func (s *Share) active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// Store keeps share links.
+type Store interface {
+	// List returns the links of a dashboard, newest first.
+	List(ctx context.Context, dashboardID string) ([]Share, error)
+	Get(ctx context.Context, id string) (*Share, error)
+	// Create stores s under a new ID.
+	Create(ctx context.Context, s Share) (*Share, error)
+	// Revoke marks a link as revoked at the given time.
+	Revoke(ctx context.Context, id string, at time.Time) error
+	// AddView counts a view of a link.
+	AddView(ctx context.Context, id string) error
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
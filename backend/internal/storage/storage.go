@@ -0,0 +1,138 @@
+// Package storage is the embedded database behind self-hosted installs,
+// which keep dashboards, users and cached sheets on local disk instead of
+// in Firebase. Records are JSON values in named buckets.
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// DB is an embedded key/value database in a single file.
+type DB struct {
+	bolt *bolt.DB
+}
+
+// Open opens the database at path, creating it if needed. Only one
+// process can hold it open at a time.
+func Open(path string) (*DB, error) {
+	b, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+	return &DB{bolt: b}, nil
+}
+
+func (db *DB) Close() error {
+	return db.bolt.Close()
+}
+
+// View runs fn in a read-only transaction.
+func (db *DB) View(fn func(tx *Tx) error) error {
+	return db.bolt.View(func(tx *bolt.Tx) error {
+		return fn(&Tx{tx: tx})
+	})
+}
+
+// Update runs fn in a read-write transaction, committed when fn returns
+// nil and rolled back otherwise.
+func (db *DB) Update(fn func(tx *Tx) error) error {
+	return db.bolt.Update(func(tx *bolt.Tx) error {
+		return fn(&Tx{tx: tx})
+	})
+}
+
+// Get reads the record at key in bucket into v, reporting whether it
+// exists.
+func (db *DB) Get(bucket, key string, v interface{}) (found bool, err error) {
+	err = db.View(func(tx *Tx) error {
+		found, err = tx.Get(bucket, key, v)
+		return err
+	})
+	return found, err
+}
+
+// Put writes v as the record at key in bucket.
+func (db *DB) Put(bucket, key string, v interface{}) error {
+	return db.Update(func(tx *Tx) error {
+		return tx.Put(bucket, key, v)
+	})
+}
+
+// Tx is a transaction on a DB.
+type Tx struct {
+	tx *bolt.Tx
+}
+
+// Get reads the record at key in bucket into v, reporting whether it
+// exists.
+func (t *Tx) Get(bucket, key string, v interface{}) (bool, error) {
+	b := t.tx.Bucket([]byte(bucket))
+	if b == nil {
+		return false, nil
+	}
+	raw := b.Get([]byte(key))
+	if raw == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return false, fmt.Errorf("failed to read %s/%s: %w", bucket, key, err)
+	}
+	return true, nil
+}
+
+// Put writes v as the record at key in bucket, creating the bucket if
+// needed.
+func (t *Tx) Put(bucket, key string, v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	b, err := t.tx.CreateBucketIfNotExists([]byte(bucket))
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(key), raw)
+}
+
+// Delete removes the record at key in bucket, reporting whether it
+// existed.
+func (t *Tx) Delete(bucket, key string) (bool, error) {
+	b := t.tx.Bucket([]byte(bucket))
+	if b == nil || b.Get([]byte(key)) == nil {
+		return false, nil
+	}
+	return true, b.Delete([]byte(key))
+}
+
+// ForEach calls fn with every record of bucket in key order, stopping at
+// the first error.
+func (t *Tx) ForEach(bucket string, fn func(key string, raw json.RawMessage) error) error {
+	b := t.tx.Bucket([]byte(bucket))
+	if b == nil {
+		return nil
+	}
+	return b.ForEach(func(k, v []byte) error {
+		return fn(string(k), v)
+	})
+}
+
+// ForEachPrefix calls fn with every record of bucket whose key starts with
+// prefix, in key order, stopping at the first error.
+func (t *Tx) ForEachPrefix(bucket, prefix string, fn func(key string, raw json.RawMessage) error) error {
+	b := t.tx.Bucket([]byte(bucket))
+	if b == nil {
+		return nil
+	}
+	c := b.Cursor()
+	for k, v := c.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, v = c.Next() {
+		if err := fn(string(k), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
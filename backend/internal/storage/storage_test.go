@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type record struct {
+	Name string `json:"name"`
+}
+
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := Open(filepath.Join(t.TempDir(), "loomis.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestGetPut(t *testing.T) {
+	db := openTestDB(t)
+	var r record
+	if found, err := db.Get("things", "a", &r); err != nil || found {
+		t.Fatalf("Get() on a missing bucket = %v, %v, want not found", found, err)
+	}
+	if err := db.Put("things", "a", record{Name: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	if found, err := db.Get("things", "b", &r); err != nil || found {
+		t.Fatalf("Get() of a missing key = %v, %v, want not found", found, err)
+	}
+	if found, err := db.Get("things", "a", &r); err != nil || !found || r.Name != "first" {
+		t.Fatalf("Get() = %+v, %v, %v, want the stored record", r, found, err)
+	}
+
+	if err := db.Put("things", "a", record{Name: "second"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Get("things", "a", &r); err != nil || r.Name != "second" {
+		t.Fatalf("Get() after overwriting = %+v, %v, want the new record", r, err)
+	}
+
+	var n int
+	if _, err := db.Get("things", "a", &n); err == nil {
+		t.Error("Get() into the wrong type succeeded")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	db := openTestDB(t)
+	if err := db.Put("things", "a", record{Name: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []bool{true, false} {
+		err := db.Update(func(tx *Tx) error {
+			found, err := tx.Delete("things", "a")
+			if found != want {
+				t.Errorf("Delete() found = %v, want %v", found, want)
+			}
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := db.Update(func(tx *Tx) error {
+		found, err := tx.Delete("missing", "a")
+		if found {
+			t.Error("Delete() on a missing bucket found a record")
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateRollback(t *testing.T) {
+	db := openTestDB(t)
+	failed := errors.New("failed")
+	err := db.Update(func(tx *Tx) error {
+		if err := tx.Put("things", "a", record{Name: "a"}); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("Update() error = %v, want the error of fn", err)
+	}
+	if found, err := db.Get("things", "a", &record{}); err != nil || found {
+		t.Errorf("Get() after a failed update = %v, %v, want the write rolled back", found, err)
+	}
+}
+
+func TestForEach(t *testing.T) {
+	db := openTestDB(t)
+	for _, key := range []string{"u2/b", "u1/b", "u1/a", "u10/a"} {
+		if err := db.Put("things", key, record{Name: key}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name   string
+		prefix string
+		want   []string
+	}{
+		{"all", "", []string{"u1/a", "u1/b", "u10/a", "u2/b"}},
+		{"prefix", "u1/", []string{"u1/a", "u1/b"}},
+		{"no match", "u3/", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []string
+			err := db.View(func(tx *Tx) error {
+				each := func(key string, raw json.RawMessage) error {
+					var r record
+					if err := json.Unmarshal(raw, &r); err != nil {
+						return err
+					}
+					if r.Name != key {
+						t.Errorf("record at %s = %+v", key, r)
+					}
+					keys = append(keys, key)
+					return nil
+				}
+				if tt.prefix == "" {
+					return tx.ForEach("things", each)
+				}
+				return tx.ForEachPrefix("things", tt.prefix, each)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(keys, tt.want) {
+				t.Errorf("keys = %v, want %v", keys, tt.want)
+			}
+		})
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err := db.View(func(tx *Tx) error {
+		return tx.ForEach("things", func(string, json.RawMessage) error {
+			calls++
+			return stop
+		})
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("ForEach() = %v after %d calls, want it to stop at the first error", err, calls)
+	}
+}
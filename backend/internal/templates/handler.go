@@ -0,0 +1,123 @@
+package templates
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjrtuhin/loomis-backend/internal/charts"
+	"github.com/mjrtuhin/loomis-backend/internal/dashboards"
+	"github.com/mjrtuhin/loomis-backend/internal/data"
+)
+
+// defaultRefreshInterval is the refresh interval of dashboards made from a
+// template, in minutes, as the frontend gives new dashboards.
+const defaultRefreshInterval = 60
+
+type Handler struct {
+	generator  *charts.ChartGenerator
+	dashboards dashboards.Store
+	cache      data.Cache
+}
+
+// NewHandler creates a Handler. Sheets are loaded through cache, which may
+// be nil, and instantiated dashboards saved to store.
+func NewHandler(generator *charts.ChartGenerator, store dashboards.Store, cache data.Cache) *Handler {
+	return &Handler{generator: generator, dashboards: store, cache: cache}
+}
+
+// InstantiateRequest is the body of template instantiations.
+type InstantiateRequest struct {
+	SheetURL string `json:"sheetUrl" binding:"required"`
+	// Mapping picks the columns of some roles by hand; the others are
+	// mapped automatically.
+	Mapping map[string][]string `json:"mapping"`
+	// RefreshInterval of the dashboard, in minutes; 60 when unset.
+	RefreshInterval int `json:"refreshInterval"`
+	// Refresh skips the sheet cache.
+	Refresh bool `json:"refresh"`
+	// Save stores the dashboard instead of only returning it.
+	Save bool `json:"save"`
+}
+
+// List handles GET /api/templates.
+func (h *Handler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"templates": All()})
+}
+
+// Instantiate handles POST /api/templates/:id/instantiate, building a
+// dashboard from the template over a sheet. The response holds the column
+// mapping used, with the dashboard as saved when save is set.
+func (h *Handler) Instantiate(c *gin.Context) {
+	t, ok := Lookup(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "template not found"})
+		return
+	}
+	var req InstantiateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.RefreshInterval == 0 {
+		req.RefreshInterval = defaultRefreshInterval
+	}
+
+	ctx := c.Request.Context()
+	entry, err := data.LoadSheet(ctx, h.cache, req.SheetURL, req.Refresh)
+	if err != nil {
+		data.SheetError(c, err)
+		return
+	}
+	columns := InferColumns(&entry.Data)
+	mapping, err := Map(t, columns, req.Mapping)
+	if err != nil {
+		h.fail(c, err, columns)
+		return
+	}
+	layout, err := Instantiate(ctx, h.generator, t, &entry.Data, mapping)
+	if err != nil {
+		h.fail(c, err, columns)
+		return
+	}
+
+	d := dashboards.Dashboard{GoogleSheetURL: req.SheetURL, RefreshInterval: req.RefreshInterval, Layout: layout}
+	if err := d.Validate(); err != nil {
+		h.fail(c, err, columns)
+		return
+	}
+	status := http.StatusOK
+	if req.Save {
+		d.UserID = c.GetString("userID")
+		d.UpdatedBy = d.UserID
+		saved, err := h.dashboards.Create(ctx, d)
+		if err != nil {
+			h.fail(c, err, columns)
+			return
+		}
+		d, status = *saved, http.StatusCreated
+	}
+	c.JSON(status, gin.H{"mapping": mapping, "columns": columns, "dashboard": d})
+}
+
+// fail reports err, listing the sheet's columns with mapping errors so
+// that they can be picked by hand.
+func (h *Handler) fail(c *gin.Context, err error, columns []ColumnInfo) {
+	var validationErr *ValidationError
+	var dashboardErr *dashboards.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Cannot map sheet columns",
+			"fields":  validationErr.Fields,
+			"columns": columns,
+		})
+	case errors.As(err, &dashboardErr):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid dashboard",
+			"fields": dashboardErr.Fields,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,235 @@
+package templates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mjrtuhin/loomis-backend/internal/charts"
+	"github.com/mjrtuhin/loomis-backend/internal/dashboards"
+	"github.com/mjrtuhin/loomis-backend/internal/data"
+)
+
+// Instantiate builds the layout of t over sheet with the columns given by
+// mapping, generating the config of every chart. Charts and text blocks
+// reading an unmapped optional role are left out. When a date role is
+// mapped, rows are read in its order, so that "last" is the latest row.
+func Instantiate(ctx context.Context, gen *charts.ChartGenerator, t *Template, sheet *data.SheetData, mapping map[string][]string) (dashboards.Layout, error) {
+	index := make(map[string]int, len(sheet.Headers))
+	for i, h := range sheet.Headers {
+		index[h] = i
+	}
+	rows := sheet.Rows
+	for _, col := range t.Columns {
+		if col.DataType == "date" && len(mapping[col.Role]) > 0 {
+			rows = byDate(rows, index[mapping[col.Role][0]])
+			break
+		}
+	}
+	cell := func(row []string, column string) string {
+		if i := index[column]; i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+	names := func(role string) string {
+		return strings.Join(mapping[role], ", ")
+	}
+	title := func(s string) string {
+		for _, col := range t.Columns {
+			s = strings.ReplaceAll(s, "{"+col.Role+"}", names(col.Role))
+		}
+		return s
+	}
+
+	layout := dashboards.Layout{Charts: []dashboards.ChartItem{}, TextBlocks: []dashboards.TextBlock{}}
+	for _, spec := range t.TextBlocks {
+		columns := mapping[spec.Role]
+		for i, column := range columns {
+			var values []float64
+			for _, row := range rows {
+				if v, ok := parseNumber(cell(row, column)); ok {
+					values = append(values, v)
+				}
+			}
+			content := strings.NewReplacer(
+				"{column}", html.EscapeString(column),
+				"{value}", html.EscapeString(formatNumber(aggregate(values, spec.Aggregate))),
+			).Replace(spec.Content)
+			p := spec.Position
+			p.X += float64(i) * (p.W + gap)
+			id := spec.ID
+			if len(columns) > 1 {
+				id = fmt.Sprintf("%s_%d", spec.ID, i+1)
+			}
+			layout.TextBlocks = append(layout.TextBlocks, dashboards.TextBlock{ID: id, Type: "text", Content: content, Position: p})
+		}
+	}
+
+specs:
+	for _, spec := range t.Charts {
+		for _, role := range spec.roles() {
+			if len(mapping[role]) == 0 {
+				continue specs
+			}
+		}
+		category := mapping[spec.Category][0]
+		var values []string
+		for _, role := range spec.Values {
+			values = append(values, mapping[role]...)
+		}
+		groups, totals := group(rows, func(row []string) string { return cell(row, category) }, values, func(row []string, column string) (float64, bool) {
+			return parseNumber(cell(row, column))
+		})
+
+		req := charts.ChartRequest{Type: spec.ChartType, Title: title(spec.Title)}
+		if spec.Top > 0 {
+			sort.SliceStable(groups, func(i, j int) bool {
+				return aggregate(totals[groups[i]][0], spec.Aggregate) > aggregate(totals[groups[j]][0], spec.Aggregate)
+			})
+			if len(groups) > spec.Top {
+				groups = groups[:spec.Top]
+			}
+		}
+		req.XAxisData = groups
+		for i, column := range values {
+			s := charts.SeriesData{Name: column, Data: make([]float64, len(groups))}
+			for j, g := range groups {
+				s.Data[j] = aggregate(totals[g][i], spec.Aggregate)
+			}
+			req.Series = append(req.Series, s)
+		}
+		if spec.Options != nil {
+			raw, err := json.Marshal(spec.Options)
+			if err != nil {
+				return dashboards.Layout{}, err
+			}
+			req.Options = raw
+		}
+
+		resp, err := gen.GenerateChart(ctx, req)
+		if err != nil {
+			return dashboards.Layout{}, fmt.Errorf("failed to generate chart %s: %w", spec.ID, err)
+		}
+		layout.Charts = append(layout.Charts, dashboards.ChartItem{
+			ID:          spec.ID,
+			Type:        "chart",
+			ChartType:   spec.ChartType,
+			Position:    spec.Position,
+			ChartConfig: resp.ChartConfig,
+		})
+	}
+	return layout, nil
+}
+
+// group collects the values of the given columns by the label of each
+// row, in order of first appearance. Rows without a label are skipped.
+func group(rows [][]string, label func([]string) string, columns []string, value func([]string, string) (float64, bool)) ([]string, map[string][][]float64) {
+	var labels []string
+	totals := make(map[string][][]float64)
+	for _, row := range rows {
+		l := label(row)
+		if l == "" {
+			continue
+		}
+		if _, ok := totals[l]; !ok {
+			labels = append(labels, l)
+			totals[l] = make([][]float64, len(columns))
+		}
+		for i, column := range columns {
+			if v, ok := value(row, column); ok {
+				totals[l][i] = append(totals[l][i], v)
+			}
+		}
+	}
+	return labels, totals
+}
+
+// byDate returns rows sorted by the date in column i, oldest first. Rows
+// without a date go first, in sheet order, so that they are never the
+// last row.
+func byDate(rows [][]string, i int) [][]string {
+	type dated struct {
+		at  time.Time
+		ok  bool
+		row []string
+	}
+	keep := make([]dated, len(rows))
+	for k, row := range rows {
+		keep[k].row = row
+		if i < len(row) {
+			keep[k].at, keep[k].ok = charts.ParseDate(row[i])
+		}
+	}
+	sort.SliceStable(keep, func(a, b int) bool {
+		if keep[a].ok != keep[b].ok {
+			return !keep[a].ok
+		}
+		return keep[a].at.Before(keep[b].at)
+	})
+	out := make([][]string, len(keep))
+	for k, d := range keep {
+		out[k] = d.row
+	}
+	return out
+}
+
+// aggregate combines values with fn, summing them by default.
+func aggregate(values []float64, fn string) float64 {
+	if fn == "count" {
+		return float64(len(values))
+	}
+	if len(values) == 0 {
+		return 0
+	}
+	switch fn {
+	case "last":
+		return values[len(values)-1]
+	case "min", "max":
+		out := values[0]
+		for _, v := range values[1:] {
+			if fn == "min" {
+				out = math.Min(out, v)
+			} else {
+				out = math.Max(out, v)
+			}
+		}
+		return out
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	if fn == "mean" {
+		return sum / float64(len(values))
+	}
+	return sum
+}
+
+// formatNumber renders v rounded to two decimals with thousands
+// separators, e.g. 1234567.891 as "1,234,567.89".
+func formatNumber(v float64) string {
+	s := strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i:]
+	}
+	var b strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return sign + b.String() + frac
+}
@@ -0,0 +1,109 @@
+package templates
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mjrtuhin/loomis-backend/internal/charts"
+	"github.com/mjrtuhin/loomis-backend/internal/dashboards"
+	"github.com/mjrtuhin/loomis-backend/internal/data"
+)
+
+func chartIDs(layout dashboards.Layout) []string {
+	var ids []string
+	for _, c := range layout.Charts {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func TestInstantiate(t *testing.T) {
+	sales, _ := Lookup("sales-overview")
+	sheet := &data.SheetData{
+		Headers: []string{"Date", "Store", "Sales"},
+		Rows: [][]string{
+			{"2024-02-03", "North", "1,000"},
+			{"2024-01-15", "South", "$234.5"},
+			{"2024-03-01", "North", "NaN"},
+			{"2024-03-09", "", "100"},
+		},
+	}
+	mapping := map[string][]string{"date": {"Date"}, "amount": {"Sales"}, "category": {"Store"}}
+	layout, err := Instantiate(context.Background(), charts.NewChartGenerator(nil, nil), sales, sheet, mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"trend", "top", "share"}; !reflect.DeepEqual(chartIDs(layout), want) {
+		t.Errorf("charts = %v, want %v", chartIDs(layout), want)
+	}
+	want := []dashboards.TextBlock{{ID: "total", Type: "text", Content: "<p>Total Sales</p><h2>1,334.5</h2>", Position: kpiBox()}}
+	if !reflect.DeepEqual(layout.TextBlocks, want) {
+		t.Errorf("text blocks = %+v, want %+v", layout.TextBlocks, want)
+	}
+	top := layout.Charts[1]
+	if top.ChartType != "bar_horizontal" || top.Position != sales.Charts[1].Position {
+		t.Errorf("top chart = %s at %+v, want bar_horizontal at %+v", top.ChartType, top.Position, sales.Charts[1].Position)
+	}
+	title, _ := top.ChartConfig["title"].(map[string]interface{})
+	if title["text"] != "Top Store by Sales" {
+		t.Errorf("top chart title = %v, want %q", title["text"], "Top Store by Sales")
+	}
+
+	// An unmapped optional role leaves out the charts reading it.
+	mapping["category"] = nil
+	layout, err = Instantiate(context.Background(), charts.NewChartGenerator(nil, nil), sales, sheet, mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"trend"}; !reflect.DeepEqual(chartIDs(layout), want) {
+		t.Errorf("charts without category = %v, want %v", chartIDs(layout), want)
+	}
+}
+
+func TestInstantiateLatest(t *testing.T) {
+	kpi, _ := Lookup("kpi-scorecard")
+	sheet := &data.SheetData{
+		Headers: []string{"Month", "Revenue", "Users"},
+		Rows: [][]string{
+			{"2024-03-01", "300", "30"},
+			{"2024-01-01", "100", "10"},
+			{"2024-02-01", "200", "20"},
+			{"", "999", "99"},
+		},
+	}
+	mapping := map[string][]string{"period": {"Month"}, "metric": {"Revenue", "Users"}}
+	layout, err := Instantiate(context.Background(), charts.NewChartGenerator(nil, nil), kpi, sheet, mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second := kpiBox()
+	second.X += second.W + gap
+	want := []dashboards.TextBlock{
+		{ID: "latest_1", Type: "text", Content: "<p>Revenue</p><h2>300</h2>", Position: kpiBox()},
+		{ID: "latest_2", Type: "text", Content: "<p>Users</p><h2>30</h2>", Position: second},
+	}
+	if !reflect.DeepEqual(layout.TextBlocks, want) {
+		t.Errorf("text blocks = %+v, want %+v", layout.TextBlocks, want)
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1,000"},
+		{1234567.891, "1,234,567.89"},
+		{-12345.5, "-12,345.5"},
+	}
+	for _, tt := range tests {
+		if got := formatNumber(tt.in); got != tt.want {
+			t.Errorf("formatNumber(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package templates
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mjrtuhin/loomis-backend/internal/charts"
+	"github.com/mjrtuhin/loomis-backend/internal/data"
+)
+
+// inferThreshold is the share of a column's non-empty cells that must
+// parse as numbers or dates for the column to be typed so.
+const inferThreshold = 0.9
+
+// ColumnInfo is a sheet column with its inferred data type.
+type ColumnInfo struct {
+	Name string `json:"name"`
+	// DataType is "string", "number" or "date".
+	DataType string `json:"dataType"`
+}
+
+// InferColumns types the columns of sheet from their values. Numbers win
+// over dates, so that years stay numbers.
+func InferColumns(sheet *data.SheetData) []ColumnInfo {
+	out := make([]ColumnInfo, len(sheet.Headers))
+	for i, name := range sheet.Headers {
+		var filled, numbers, dates int
+		for _, row := range sheet.Rows {
+			if i >= len(row) || strings.TrimSpace(row[i]) == "" {
+				continue
+			}
+			filled++
+			if _, ok := parseNumber(row[i]); ok {
+				numbers++
+			} else if _, ok := charts.ParseDate(row[i]); ok {
+				dates++
+			}
+		}
+		dataType := "string"
+		switch {
+		case filled == 0:
+		case float64(numbers) >= inferThreshold*float64(filled):
+			dataType = "number"
+		case float64(dates) >= inferThreshold*float64(filled):
+			dataType = "date"
+		}
+		out[i] = ColumnInfo{Name: name, DataType: dataType}
+	}
+	return out
+}
+
+// parseNumber reads a cell as a number, allowing thousands separators, a
+// leading currency sign and a trailing percent sign. Cells reading NaN or
+// Inf are text, not numbers.
+func parseNumber(s string) (float64, bool) {
+	s = strings.TrimSpace(strings.ReplaceAll(s, ",", ""))
+	s = strings.TrimSuffix(strings.TrimLeft(s, "$€£¥"), "%")
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return v, true
+}
+
+// accepts reports whether a column of type columnType can fill a role of
+// type roleType. Any column can label categories.
+func accepts(roleType, columnType string) bool {
+	return roleType == columnType || roleType == "string"
+}
+
+// Map assigns the columns of a sheet to the roles of t. overrides, keyed
+// by role, are taken as given; an empty one leaves an optional role
+// unmapped. The other roles get the unused columns of a fitting type whose
+// names best match their hints. Single-column roles are mapped before
+// roles taking several columns, which get every fitting column matching a
+// hint, or every fitting column when none does.
+func Map(t *Template, columns []ColumnInfo, overrides map[string][]string) (map[string][]string, error) {
+	var errs []FieldError
+	fail := func(field, message string) {
+		errs = append(errs, FieldError{Field: field, Message: message})
+	}
+
+	types := make(map[string]string, len(columns))
+	for _, c := range columns {
+		types[c.Name] = c.DataType
+	}
+	mapping := make(map[string][]string)
+	used := make(map[string]bool)
+	roles := make([]string, 0, len(overrides))
+	for role := range overrides {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	for _, role := range roles {
+		names := overrides[role]
+		col, ok := t.column(role)
+		if !ok {
+			fail("mapping."+role, "is not a role of the template")
+			continue
+		}
+		switch {
+		case len(names) == 0 && !col.Optional:
+			fail("mapping."+role, "is required")
+			continue
+		case len(names) > 1 && !col.Multiple:
+			fail("mapping."+role, "takes a single column")
+			continue
+		}
+		for _, name := range names {
+			columnType, ok := types[name]
+			switch {
+			case !ok:
+				fail("mapping."+role, fmt.Sprintf("unknown column %q", name))
+			case !accepts(col.DataType, columnType):
+				fail("mapping."+role, fmt.Sprintf("column %q holds %s values, not %s", name, columnType, col.DataType))
+			}
+			used[name] = true
+		}
+		mapping[role] = names
+	}
+
+	pick := func(col Column) []string {
+		var best []string
+		bestScore := -1
+		var matching, fitting []string
+		for _, c := range columns {
+			if used[c.Name] || !accepts(col.DataType, c.DataType) {
+				continue
+			}
+			fitting = append(fitting, c.Name)
+			score := hintScore(c.Name, col.Hints)
+			if score > 0 {
+				matching = append(matching, c.Name)
+			}
+			// Label roles prefer text columns over numbers and dates.
+			if c.DataType == col.DataType {
+				score++
+			}
+			if score > bestScore {
+				best, bestScore = []string{c.Name}, score
+			}
+		}
+		switch {
+		case !col.Multiple:
+			return best
+		case len(matching) > 0:
+			return matching
+		default:
+			return fitting
+		}
+	}
+	for _, multiple := range []bool{false, true} {
+		for _, col := range t.Columns {
+			if col.Multiple != multiple {
+				continue
+			}
+			if _, ok := mapping[col.Role]; ok {
+				continue
+			}
+			names := pick(col)
+			if len(names) == 0 {
+				if !col.Optional {
+					fail("mapping."+col.Role, fmt.Sprintf("no %s column found", col.DataType))
+				}
+				continue
+			}
+			for _, name := range names {
+				used[name] = true
+			}
+			mapping[col.Role] = names
+		}
+	}
+
+	if errs != nil {
+		return nil, &ValidationError{Fields: errs}
+	}
+	return mapping, nil
+}
+
+// hintScore rates how well a column name matches the hints of a role: 2
+// when a word of the name is a hint, 1 when the name contains one.
+func hintScore(name string, hints []string) int {
+	lower := strings.ToLower(name)
+	words := strings.FieldsFunc(lower, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	score := 0
+	for _, hint := range hints {
+		for _, w := range words {
+			if w == hint {
+				return 2
+			}
+		}
+		if strings.Contains(lower, hint) {
+			score = 1
+		}
+	}
+	return score
+}
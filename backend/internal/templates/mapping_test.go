@@ -0,0 +1,229 @@
+package templates
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mjrtuhin/loomis-backend/internal/data"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"42", 42, true},
+		{" 1,234.5 ", 1234.5, true},
+		{"$12", 12, true},
+		{"€-3.5", -3.5, true},
+		{"45%", 45, true},
+		{"1e3", 1000, true},
+		{"", 0, false},
+		{"abc", 0, false},
+		{"NaN", 0, false},
+		{"Inf", 0, false},
+		{"-infinity", 0, false},
+		{"1e400", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseNumber(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseNumber(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestInferColumns(t *testing.T) {
+	sheet := &data.SheetData{
+		Headers: []string{"Year", "Ordered", "Region", "Sales", "Flag", "Notes", "Extra"},
+		Rows: [][]string{
+			{"2021", "2021-01-05", "North", "1,200", "NaN", ""},
+			{"2022", "2022-02-10", "South", "$80", "Inf", ""},
+			{"2023", "March 2023", "East", "n/a", "infinity", ""},
+		},
+	}
+	for i := 0; i < 7; i++ {
+		sheet.Rows = append(sheet.Rows, []string{"2024", "2024-01-01", "West", "10", "", " "})
+	}
+	want := []ColumnInfo{
+		{Name: "Year", DataType: "number"},
+		{Name: "Ordered", DataType: "date"},
+		{Name: "Region", DataType: "string"},
+		{Name: "Sales", DataType: "number"},
+		{Name: "Flag", DataType: "string"},
+		{Name: "Notes", DataType: "string"},
+		{Name: "Extra", DataType: "string"},
+	}
+	if got := InferColumns(sheet); !reflect.DeepEqual(got, want) {
+		t.Errorf("InferColumns() = %v, want %v", got, want)
+	}
+}
+
+func TestMap(t *testing.T) {
+	sales, _ := Lookup("sales-overview")
+	kpi, _ := Lookup("kpi-scorecard")
+	compare, _ := Lookup("category-comparison")
+	salesColumns := []ColumnInfo{
+		{Name: "Order ID", DataType: "number"},
+		{Name: "Subtotal", DataType: "number"},
+		{Name: "Sales", DataType: "number"},
+		{Name: "Order Date", DataType: "date"},
+		{Name: "Store", DataType: "string"},
+	}
+
+	tests := []struct {
+		name      string
+		t         *Template
+		columns   []ColumnInfo
+		overrides map[string][]string
+		want      map[string][]string
+	}{
+		{
+			name:    "hints",
+			t:       sales,
+			columns: salesColumns,
+			want: map[string][]string{
+				"date":     {"Order Date"},
+				"amount":   {"Sales"},
+				"category": {"Store"},
+			},
+		},
+		{
+			name:      "overrides",
+			t:         sales,
+			columns:   salesColumns,
+			overrides: map[string][]string{"amount": {"Subtotal"}, "category": {}},
+			want: map[string][]string{
+				"date":     {"Order Date"},
+				"amount":   {"Subtotal"},
+				"category": {},
+			},
+		},
+		{
+			name: "label prefers text",
+			t:    sales,
+			columns: []ColumnInfo{
+				{Name: "Sales", DataType: "number"},
+				{Name: "Day", DataType: "date"},
+				{Name: "Units", DataType: "number"},
+				{Name: "Rep", DataType: "string"},
+			},
+			want: map[string][]string{
+				"date":     {"Day"},
+				"amount":   {"Sales"},
+				"category": {"Rep"},
+			},
+		},
+		{
+			name: "multiple matching hints",
+			t:    kpi,
+			columns: []ColumnInfo{
+				{Name: "Week", DataType: "date"},
+				{Name: "Revenue", DataType: "number"},
+				{Name: "Temperature", DataType: "number"},
+				{Name: "Active users", DataType: "number"},
+				{Name: "Notes", DataType: "string"},
+			},
+			want: map[string][]string{
+				"period": {"Week"},
+				"metric": {"Revenue", "Active users"},
+			},
+		},
+		{
+			name: "multiple without hints",
+			t:    kpi,
+			columns: []ColumnInfo{
+				{Name: "Week", DataType: "date"},
+				{Name: "A", DataType: "number"},
+				{Name: "B", DataType: "number"},
+			},
+			want: map[string][]string{
+				"period": {"Week"},
+				"metric": {"A", "B"},
+			},
+		},
+		{
+			name: "single roles first",
+			t:    compare,
+			columns: []ColumnInfo{
+				{Name: "Total", DataType: "number"},
+				{Name: "Amount", DataType: "number"},
+			},
+			want: map[string][]string{
+				"category": {"Total"},
+				"measure":  {"Amount"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Map(tt.t, tt.columns, tt.overrides)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Map() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapErrors(t *testing.T) {
+	sales, _ := Lookup("sales-overview")
+	columns := []ColumnInfo{
+		{Name: "Region", DataType: "string"},
+		{Name: "Sales", DataType: "number"},
+		{Name: "Cost", DataType: "number"},
+	}
+
+	tests := []struct {
+		name      string
+		columns   []ColumnInfo
+		overrides map[string][]string
+		want      []FieldError
+	}{
+		{
+			name:      "unknown role",
+			columns:   columns,
+			overrides: map[string][]string{"date": {"Region"}, "profit": {"Sales"}},
+			want:      []FieldError{{Field: "mapping.date", Message: `column "Region" holds string values, not date`}, {Field: "mapping.profit", Message: "is not a role of the template"}},
+		},
+		{
+			name:      "required role left empty",
+			columns:   append(columns, ColumnInfo{Name: "Day", DataType: "date"}),
+			overrides: map[string][]string{"amount": {}},
+			want:      []FieldError{{Field: "mapping.amount", Message: "is required"}},
+		},
+		{
+			name:      "several columns for a single role",
+			columns:   append(columns, ColumnInfo{Name: "Day", DataType: "date"}),
+			overrides: map[string][]string{"amount": {"Sales", "Cost"}},
+			want:      []FieldError{{Field: "mapping.amount", Message: "takes a single column"}},
+		},
+		{
+			name:      "unknown column",
+			columns:   append(columns, ColumnInfo{Name: "Day", DataType: "date"}),
+			overrides: map[string][]string{"amount": {"Profit"}},
+			want:      []FieldError{{Field: "mapping.amount", Message: `unknown column "Profit"`}},
+		},
+		{
+			name:    "no fitting column",
+			columns: columns,
+			want:    []FieldError{{Field: "mapping.date", Message: "no date column found"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Map(sales, tt.columns, tt.overrides)
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Map() error = %v, want a ValidationError", err)
+			}
+			if !reflect.DeepEqual(verr.Fields, tt.want) {
+				t.Errorf("Map() fields = %v, want %v", verr.Fields, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+// Package templates holds the dashboard templates of the gallery. A
+// template declares the column roles it needs; instantiating it maps the
+// columns of a loaded sheet onto those roles and generates a populated
+// dashboard layout.
+package templates
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mjrtuhin/loomis-backend/internal/dashboards"
+)
+
+// Template is a dashboard blueprint.
+type Template struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Columns     []Column    `json:"columns"`
+	Charts      []ChartSpec `json:"charts"`
+	TextBlocks  []TextSpec  `json:"textBlocks"`
+}
+
+// Column is a column role of a template.
+type Column struct {
+	Role string `json:"role"`
+	// DataType is "string", "number" or "date".
+	DataType string `json:"dataType"`
+	// Multiple is set when the role takes several columns, e.g. one per
+	// metric.
+	Multiple bool `json:"multiple,omitempty"`
+	// Optional roles may be left unmapped; the charts and text blocks that
+	// read them are then left out.
+	Optional bool `json:"optional,omitempty"`
+	// Hints are words that, found in a column name, mark the column as the
+	// likely one for the role.
+	Hints       []string `json:"hints"`
+	Description string   `json:"description"`
+}
+
+// ChartSpec is a chart of a template. Its title may name the columns
+// mapped to a role as {role}.
+type ChartSpec struct {
+	ID        string `json:"id"`
+	ChartType string `json:"chartType"`
+	Title     string `json:"title"`
+	// Category is the role whose values label the chart's categories.
+	Category string `json:"category"`
+	// Values are the roles whose columns become series.
+	Values []string `json:"values"`
+	// Aggregate combines the rows sharing a category: "sum" (the default),
+	// "mean" or "count".
+	Aggregate string `json:"aggregate,omitempty"`
+	// Top keeps the categories with the largest first series, largest
+	// first. 0 keeps every category, in sheet order or by date.
+	Top int `json:"top,omitempty"`
+	// Options are the chart type's options, see charts/options.go.
+	Options  map[string]interface{} `json:"options,omitempty"`
+	Position dashboards.Position    `json:"position"`
+}
+
+// TextSpec is a text block of a template. A spec with a Role shows one
+// figure per column of the role, side by side from Position: Content
+// names the column as {column} and the figure as {value}.
+type TextSpec struct {
+	ID      string `json:"id"`
+	Content string `json:"content"`
+	Role    string `json:"role,omitempty"`
+	// Aggregate computes the figure: "sum" (the default), "mean", "min",
+	// "max", "count" or "last".
+	Aggregate string              `json:"aggregate,omitempty"`
+	Position  dashboards.Position `json:"position"`
+}
+
+// roles returns the roles a chart reads.
+func (s ChartSpec) roles() []string {
+	return append([]string{s.Category}, s.Values...)
+}
+
+func (t *Template) column(role string) (Column, bool) {
+	for _, c := range t.Columns {
+		if c.Role == role {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+// Lookup returns the built-in template with the given ID.
+func Lookup(id string) (*Template, bool) {
+	for i := range builtin {
+		if builtin[i].ID == id {
+			return &builtin[i], true
+		}
+	}
+	return nil, false
+}
+
+// All returns the built-in templates.
+func All() []Template {
+	return builtin
+}
+
+// FieldError describes one role that could not be mapped.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when the columns of a sheet cannot be mapped
+// onto a template.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + ": " + f.Message
+	}
+	return "invalid mapping: " + strings.Join(msgs, "; ")
+}
+
+// gap is the space between the items of the built-in layouts, in pixels.
+const gap = 20
+
+// box places an item of the built-in layouts on a grid of 400x300 cells,
+// the size the frontend gives new charts.
+func box(col, row, w, h float64) dashboards.Position {
+	return dashboards.Position{X: col * (400 + gap), Y: row * (300 + gap), W: w*400 + (w-1)*gap, H: h*300 + (h-1)*gap}
+}
+
+// kpiBox places a KPI text block in the strip above a built-in layout.
+func kpiBox() dashboards.Position {
+	return dashboards.Position{X: 0, Y: 0, W: 280, H: 100}
+}
+
+// below shifts p down past the KPI strip.
+func below(p dashboards.Position) dashboards.Position {
+	p.Y += kpiBox().H + gap
+	return p
+}
+
+var builtin = []Template{
+	{
+		ID:          "sales-overview",
+		Name:        "Sales overview",
+		Description: "Total sales, their trend by month and the leading categories.",
+		Columns: []Column{
+			{Role: "date", DataType: "date", Hints: []string{"date", "day", "month", "period", "ordered", "time"}, Description: "When each sale happened"},
+			{Role: "amount", DataType: "number", Hints: []string{"sales", "revenue", "amount", "total", "value", "price"}, Description: "Value of each sale"},
+			{Role: "category", DataType: "string", Optional: true, Hints: []string{"category", "product", "region", "segment", "channel", "store"}, Description: "What the sales are broken down by"},
+		},
+		TextBlocks: []TextSpec{
+			{ID: "total", Role: "amount", Content: "<p>Total {column}</p><h2>{value}</h2>", Position: kpiBox()},
+		},
+		Charts: []ChartSpec{
+			{
+				ID: "trend", ChartType: "line_area", Title: "{amount} by month",
+				Category: "date", Values: []string{"amount"},
+				Options:  map[string]interface{}{"time": map[string]interface{}{"resample": "month", "aggregate": "sum", "gaps": "zero"}},
+				Position: below(box(0, 0, 2, 1)),
+			},
+			{
+				ID: "top", ChartType: "bar_horizontal", Title: "Top {category} by {amount}",
+				Category: "category", Values: []string{"amount"}, Top: 10,
+				Position: below(box(0, 1, 1, 1)),
+			},
+			{
+				ID: "share", ChartType: "pie_doughnut", Title: "Share of {amount} by {category}",
+				Category: "category", Values: []string{"amount"},
+				Options:  map[string]interface{}{"maxPoints": 8},
+				Position: below(box(1, 1, 1, 1)),
+			},
+		},
+	},
+	{
+		ID:          "kpi-scorecard",
+		Name:        "KPI scorecard",
+		Description: "The latest value of each metric, with their history over time.",
+		Columns: []Column{
+			{Role: "period", DataType: "date", Hints: []string{"date", "period", "month", "week", "day", "quarter"}, Description: "The period of each row"},
+			{Role: "metric", DataType: "number", Multiple: true, Hints: []string{"kpi", "revenue", "users", "orders", "conversion", "rate", "count", "score"}, Description: "One column per metric"},
+		},
+		TextBlocks: []TextSpec{
+			{ID: "latest", Role: "metric", Aggregate: "last", Content: "<p>{column}</p><h2>{value}</h2>", Position: kpiBox()},
+		},
+		Charts: []ChartSpec{
+			{
+				ID: "history", ChartType: "line", Title: "Metrics over time",
+				Category: "period", Values: []string{"metric"},
+				Options:  map[string]interface{}{"time": map[string]interface{}{"aggregate": "last"}},
+				Position: below(box(0, 0, 2, 1)),
+			},
+		},
+	},
+	{
+		ID:          "category-comparison",
+		Name:        "Category comparison",
+		Description: "Measures compared across the categories of a column.",
+		Columns: []Column{
+			{Role: "category", DataType: "string", Hints: []string{"category", "name", "product", "region", "country", "team", "segment"}, Description: "The categories to compare"},
+			{Role: "measure", DataType: "number", Multiple: true, Hints: []string{"total", "amount", "count", "value", "score"}, Description: "One column per measure"},
+		},
+		Charts: []ChartSpec{
+			{
+				ID: "compare", ChartType: "bar", Title: "{measure} by {category}",
+				Category: "category", Values: []string{"measure"}, Top: 15,
+				Position: box(0, 0, 2, 1),
+			},
+			{
+				ID: "share", ChartType: "pie", Title: "Share by {category}",
+				Category: "category", Values: []string{"measure"},
+				Options:  map[string]interface{}{"maxPoints": 8},
+				Position: box(0, 1, 1, 1),
+			},
+			{
+				ID: "mean", ChartType: "bar_horizontal", Title: "Average {measure} by {category}",
+				Category: "category", Values: []string{"measure"}, Aggregate: "mean", Top: 8,
+				Position: box(1, 1, 1, 1),
+			},
+		},
+	},
+}
+
+func init() {
+	for _, t := range builtin {
+		for _, s := range t.Charts {
+			for _, role := range s.roles() {
+				if _, ok := t.column(role); !ok {
+					panic(fmt.Sprintf("template %s: chart %s reads unknown role %q", t.ID, s.ID, role))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package themes
+
+// axisKeys are the option keys of every axis component ECharts has.
+var axisKeys = []string{"xAxis", "yAxis", "radiusAxis", "angleAxis", "singleAxis", "parallelAxis"}
+
+// Apply styles the ECharts option object config with d. The palette
+// replaces the chart's colors only when replacePalette is set, e.g. when
+// the request picked no colors of its own; every other style fills in
+// values the chart left unset, so that chart-specific styling wins.
+func (d Definition) Apply(config map[string]interface{}, replacePalette bool) {
+	if len(d.Colors) > 0 && replacePalette {
+		colors := make([]interface{}, len(d.Colors))
+		for i, c := range d.Colors {
+			colors[i] = c
+		}
+		config["color"] = colors
+	}
+	fill(config, d.BackgroundColor, "backgroundColor")
+
+	if f := d.Font; f != nil {
+		fill(config, f.Family, "textStyle", "fontFamily")
+		fill(config, f.Size, "textStyle", "fontSize")
+		fill(config, f.Color, "textStyle", "color")
+		each(config["legend"], func(legend map[string]interface{}) {
+			fill(legend, f.Color, "textStyle", "color")
+		})
+	}
+
+	if t := d.Title; t != nil {
+		each(config["title"], func(title map[string]interface{}) {
+			fill(title, t.Family, "textStyle", "fontFamily")
+			fill(title, t.Size, "textStyle", "fontSize")
+			fill(title, t.Weight, "textStyle", "fontWeight")
+			fill(title, t.Color, "textStyle", "color")
+			fill(title, t.SubtitleColor, "subtextStyle", "color")
+			fill(title, t.Align, "left")
+		})
+	}
+
+	if a := d.Axis; a != nil {
+		for _, key := range axisKeys {
+			each(config[key], func(axis map[string]interface{}) {
+				fill(axis, a.LineColor, "axisLine", "lineStyle", "color")
+				fill(axis, a.LineColor, "axisTick", "lineStyle", "color")
+				fill(axis, a.LabelColor, "axisLabel", "color")
+				fill(axis, a.LabelColor, "nameTextStyle", "color")
+				fill(axis, a.SplitLineColor, "splitLine", "lineStyle", "color")
+				if a.SplitLines != nil {
+					fill(axis, *a.SplitLines, "splitLine", "show")
+				}
+			})
+		}
+	}
+
+	if g := d.Grid; g != nil {
+		// Charts on a single default grid carry no grid option; give them
+		// one when they have cartesian axes.
+		if _, ok := config["grid"]; !ok && config["xAxis"] != nil {
+			config["grid"] = map[string]interface{}{}
+		}
+		each(config["grid"], func(grid map[string]interface{}) {
+			fill(grid, g.Left, "left")
+			fill(grid, g.Right, "right")
+			fill(grid, g.Top, "top")
+			fill(grid, g.Bottom, "bottom")
+		})
+	}
+}
+
+// each calls fn with v, when it is an object, or with every object of v,
+// when it is a list such as the titles and grids of a faceted chart.
+func each(v interface{}, fn func(map[string]interface{})) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		fn(v)
+	case []interface{}:
+		for _, item := range v {
+			if item, ok := item.(map[string]interface{}); ok {
+				fn(item)
+			}
+		}
+	}
+}
+
+// fill sets the value at path below obj, creating the objects on the way,
+// unless a value is already there. Zero values are not set.
+func fill(obj map[string]interface{}, value interface{}, path ...string) {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return
+		}
+	case int:
+		if v == 0 {
+			return
+		}
+	}
+	for _, key := range path[:len(path)-1] {
+		next, ok := obj[key].(map[string]interface{})
+		if !ok {
+			if obj[key] != nil {
+				return
+			}
+			next = map[string]interface{}{}
+			obj[key] = next
+		}
+		obj = next
+	}
+	last := path[len(path)-1]
+	if obj[last] == nil {
+		obj[last] = value
+	}
+}
@@ -0,0 +1,107 @@
+package themes
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/mjrtuhin/loomis-backend/internal/storage"
+)
+
+// bucket holds the versions of each theme in the embedded database, keyed
+// by ID.
+const bucket = "themes"
+
+type boltStore struct {
+	db *storage.DB
+}
+
+// NewBoltStore creates a Store in the embedded database db.
+func NewBoltStore(db *storage.DB) Store {
+	return &boltStore{db: db}
+}
+
+func (s *boltStore) List(_ context.Context, owner string) ([]Theme, error) {
+	var out []Theme
+	err := s.db.View(func(tx *storage.Tx) error {
+		return tx.ForEach(bucket, func(_ string, raw json.RawMessage) error {
+			var versions []Theme
+			if err := json.Unmarshal(raw, &versions); err != nil {
+				return err
+			}
+			if latest := versions[len(versions)-1]; latest.Owner == owner {
+				out = append(out, latest)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortByName(out)
+	return out, nil
+}
+
+func (s *boltStore) Get(ctx context.Context, id string, version int) (*Theme, error) {
+	versions, err := s.Versions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if version < 0 || version > len(versions) {
+		return nil, ErrNotFound
+	}
+	if version == 0 {
+		version = len(versions)
+	}
+	return &versions[version-1], nil
+}
+
+func (s *boltStore) Versions(_ context.Context, id string) ([]Theme, error) {
+	var versions []Theme
+	found, err := s.db.Get(bucket, id, &versions)
+	if err != nil {
+		return nil, err
+	}
+	if !found || len(versions) == 0 {
+		return nil, ErrNotFound
+	}
+	return versions, nil
+}
+
+func (s *boltStore) Save(_ context.Context, t Theme) (*Theme, error) {
+	isNew := t.ID == ""
+	if isNew {
+		id, err := newID()
+		if err != nil {
+			return nil, err
+		}
+		t.ID = id
+	}
+	err := s.db.Update(func(tx *storage.Tx) error {
+		var versions []Theme
+		found, err := tx.Get(bucket, t.ID, &versions)
+		if err != nil {
+			return err
+		}
+		if !found && !isNew {
+			return ErrNotFound
+		}
+		t.Version = len(versions) + 1
+		t.CreatedAt = time.Now().UTC()
+		return tx.Put(bucket, t.ID, append(versions, t))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (s *boltStore) Delete(_ context.Context, id string) error {
+	return s.db.Update(func(tx *storage.Tx) error {
+		found, err := tx.Delete(bucket, id)
+		if err == nil && !found {
+			return ErrNotFound
+		}
+		return err
+	})
+}
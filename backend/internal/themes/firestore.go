@@ -0,0 +1,187 @@
+package themes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// collection is the Firestore collection of brand themes. Each
+	// document holds the latest version of a theme.
+	collection = "themes"
+	// versionsCollection is the subcollection of a theme document holding
+	// every version, keyed by number.
+	versionsCollection = "versions"
+)
+
+// firestoreTheme is a theme version as stored in Firestore. Owner and
+// Number are kept as fields for queries; the theme itself is JSON text.
+type firestoreTheme struct {
+	Owner  string `firestore:"owner"`
+	Number int    `firestore:"number"`
+	Theme  string `firestore:"theme"`
+}
+
+func toFirestoreTheme(t Theme) (firestoreTheme, error) {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return firestoreTheme{}, err
+	}
+	return firestoreTheme{Owner: t.Owner, Number: t.Version, Theme: string(b)}, nil
+}
+
+func fromDoc(doc *firestore.DocumentSnapshot) (*Theme, error) {
+	var ft firestoreTheme
+	if err := doc.DataTo(&ft); err != nil {
+		return nil, fmt.Errorf("failed to read theme %s: %w", doc.Ref.Path, err)
+	}
+	var t Theme
+	if err := json.Unmarshal([]byte(ft.Theme), &t); err != nil {
+		return nil, fmt.Errorf("failed to read theme %s: %w", doc.Ref.Path, err)
+	}
+	return &t, nil
+}
+
+type firestoreStore struct {
+	client *firestore.Client
+}
+
+// NewFirestoreStore creates a Store on the themes collection of the
+// Firebase project. An empty projectID is detected from the credentials.
+func NewFirestoreStore(ctx context.Context, projectID, credentialsJSON string) (Store, error) {
+	if projectID == "" {
+		projectID = firestore.DetectProjectID
+	}
+	client, err := firestore.NewClient(ctx, projectID, option.WithCredentialsJSON([]byte(credentialsJSON)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Firestore: %w", err)
+	}
+	return &firestoreStore{client: client}, nil
+}
+
+func (s *firestoreStore) List(ctx context.Context, owner string) ([]Theme, error) {
+	docs, err := s.client.Collection(collection).Where("owner", "==", owner).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Theme, 0, len(docs))
+	for _, doc := range docs {
+		t, err := fromDoc(doc)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *t)
+	}
+	sortByName(out)
+	return out, nil
+}
+
+func (s *firestoreStore) Get(ctx context.Context, id string, version int) (*Theme, error) {
+	if version < 0 {
+		return nil, ErrNotFound
+	}
+	ref := s.client.Collection(collection).Doc(id)
+	if version > 0 {
+		ref = ref.Collection(versionsCollection).Doc(strconv.Itoa(version))
+	}
+	doc, err := ref.Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fromDoc(doc)
+}
+
+func (s *firestoreStore) Versions(ctx context.Context, id string) ([]Theme, error) {
+	if _, err := s.Get(ctx, id, 0); err != nil {
+		return nil, err
+	}
+	docs, err := s.client.Collection(collection).Doc(id).Collection(versionsCollection).
+		OrderBy("number", firestore.Asc).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Theme, 0, len(docs))
+	for _, doc := range docs {
+		t, err := fromDoc(doc)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *t)
+	}
+	return out, nil
+}
+
+func (s *firestoreStore) Save(ctx context.Context, t Theme) (*Theme, error) {
+	isNew := t.ID == ""
+	if isNew {
+		id, err := newID()
+		if err != nil {
+			return nil, err
+		}
+		t.ID = id
+	}
+	ref := s.client.Collection(collection).Doc(t.ID)
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		t.Version = 1
+		if !isNew {
+			doc, err := tx.Get(ref)
+			if status.Code(err) == codes.NotFound {
+				return ErrNotFound
+			}
+			if err != nil {
+				return err
+			}
+			prev, err := fromDoc(doc)
+			if err != nil {
+				return err
+			}
+			t.Version = prev.Version + 1
+		}
+		t.CreatedAt = time.Now().UTC()
+		ft, err := toFirestoreTheme(t)
+		if err != nil {
+			return err
+		}
+		if err := tx.Set(ref, ft); err != nil {
+			return err
+		}
+		// Creating rather than setting the version fails the transaction
+		// if a concurrent save took the same number.
+		return tx.Create(ref.Collection(versionsCollection).Doc(strconv.Itoa(t.Version)), ft)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (s *firestoreStore) Delete(ctx context.Context, id string) error {
+	ref := s.client.Collection(collection).Doc(id)
+	if _, err := ref.Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return ErrNotFound
+		}
+		return err
+	}
+	versions, err := ref.Collection(versionsCollection).DocumentRefs(ctx).GetAll()
+	if err != nil {
+		return err
+	}
+	for _, v := range versions {
+		if _, err := v.Delete(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
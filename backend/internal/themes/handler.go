@@ -0,0 +1,158 @@
+package themes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	store Store
+}
+
+func NewHandler(store Store) *Handler {
+	return &Handler{store: store}
+}
+
+// ThemeRequest is the body of theme creates and updates.
+type ThemeRequest struct {
+	Name       string     `json:"name"`
+	Definition Definition `json:"definition"`
+}
+
+// List handles GET /api/themes, listing the caller's themes.
+func (h *Handler) List(c *gin.Context) {
+	themes, err := h.store.List(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if themes == nil {
+		themes = []Theme{}
+	}
+	c.JSON(http.StatusOK, gin.H{"themes": themes})
+}
+
+// Get handles GET /api/themes/:id, returning the latest version or the
+// one given by the version query parameter.
+func (h *Handler) Get(c *gin.Context) {
+	version, err := strconv.Atoi(c.DefaultQuery("version", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version must be a number"})
+		return
+	}
+	theme, err := h.store.Get(c.Request.Context(), c.Param("id"), version)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, theme)
+}
+
+// Versions handles GET /api/themes/:id/versions.
+func (h *Handler) Versions(c *gin.Context) {
+	versions, err := h.store.Versions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"versions": versions})
+}
+
+// Create handles POST /api/themes.
+func (h *Handler) Create(c *gin.Context) {
+	t, ok := h.bind(c)
+	if !ok {
+		return
+	}
+	t.Owner = c.GetString("userID")
+	saved, err := h.store.Save(c.Request.Context(), t)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, saved)
+}
+
+// Update handles PUT /api/themes/:id, saving a new version.
+func (h *Handler) Update(c *gin.Context) {
+	current, ok := h.owned(c)
+	if !ok {
+		return
+	}
+	t, ok := h.bind(c)
+	if !ok {
+		return
+	}
+	t.ID, t.Owner = current.ID, current.Owner
+	saved, err := h.store.Save(c.Request.Context(), t)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, saved)
+}
+
+// Delete handles DELETE /api/themes/:id.
+func (h *Handler) Delete(c *gin.Context) {
+	current, ok := h.owned(c)
+	if !ok {
+		return
+	}
+	if err := h.store.Delete(c.Request.Context(), current.ID); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// bind strictly decodes and validates the request body, so that misspelt
+// style fields are reported instead of silently ignored.
+func (h *Handler) bind(c *gin.Context) (Theme, bool) {
+	var req ThemeRequest
+	dec := json.NewDecoder(c.Request.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return Theme{}, false
+	}
+	t := Theme{Name: req.Name, Definition: req.Definition}
+	if err := t.Validate(); err != nil {
+		h.fail(c, err)
+		return Theme{}, false
+	}
+	return t, true
+}
+
+// owned returns the latest version of the theme in the path, failing the
+// request unless the caller owns it.
+func (h *Handler) owned(c *gin.Context) (*Theme, bool) {
+	t, err := h.store.Get(c.Request.Context(), c.Param("id"), 0)
+	if err != nil {
+		h.fail(c, err)
+		return nil, false
+	}
+	if t.Owner != c.GetString("userID") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the owner can change a theme"})
+		return nil, false
+	}
+	return t, true
+}
+
+func (h *Handler) fail(c *gin.Context, err error) {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid theme",
+			"fields": validationErr.Fields,
+		})
+	case errors.Is(err, ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
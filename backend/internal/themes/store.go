@@ -0,0 +1,191 @@
+package themes
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned for unknown theme IDs and versions.
+var ErrNotFound = errors.New("theme not found")
+
+// Store keeps every version of every brand theme.
+type Store interface {
+	// List returns the latest version of each theme of owner, by name.
+	List(ctx context.Context, owner string) ([]Theme, error)
+	// Get returns the given version of a theme, or its latest version when
+	// version is 0.
+	Get(ctx context.Context, id string, version int) (*Theme, error)
+	// Versions returns every version of a theme, oldest first.
+	Versions(ctx context.Context, id string) ([]Theme, error)
+	// Save stores t as the next version of theme t.ID, or as version 1 of
+	// a new theme when t.ID is empty, and returns the stored version.
+	Save(ctx context.Context, t Theme) (*Theme, error)
+	// Delete removes a theme with all its versions.
+	Delete(ctx context.Context, id string) error
+}
+
+// fileStore keeps themes in memory and, when path is set, rewrites them
+// all to the JSON file at path after every change.
+type fileStore struct {
+	mu     sync.RWMutex
+	path   string
+	themes map[string][]Theme
+}
+
+// NewStore creates a Store backed by the JSON file at path, loading the
+// themes already saved there. With an empty path themes are kept in memory
+// only and are lost on restart.
+func NewStore(path string) (Store, error) {
+	s := &fileStore{path: path, themes: make(map[string][]Theme)}
+	if path == "" {
+		return s, nil
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read themes: %w", err)
+	}
+	if err := json.Unmarshal(b, &s.themes); err != nil {
+		return nil, fmt.Errorf("failed to parse themes: %w", err)
+	}
+	return s, nil
+}
+
+func (s *fileStore) List(_ context.Context, owner string) ([]Theme, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []Theme
+	for _, versions := range s.themes {
+		if latest := versions[len(versions)-1]; latest.Owner == owner {
+			out = append(out, latest)
+		}
+	}
+	sortByName(out)
+	return out, nil
+}
+
+func (s *fileStore) Get(_ context.Context, id string, version int) (*Theme, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions := s.themes[id]
+	if len(versions) == 0 || version < 0 || version > len(versions) {
+		return nil, ErrNotFound
+	}
+	if version == 0 {
+		version = len(versions)
+	}
+	t := versions[version-1]
+	return &t, nil
+}
+
+func (s *fileStore) Versions(_ context.Context, id string) ([]Theme, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions := s.themes[id]
+	if len(versions) == 0 {
+		return nil, ErrNotFound
+	}
+	return append([]Theme(nil), versions...), nil
+}
+
+func (s *fileStore) Save(_ context.Context, t Theme) (*Theme, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t.ID == "" {
+		id, err := newID()
+		if err != nil {
+			return nil, err
+		}
+		t.ID = id
+	} else if len(s.themes[t.ID]) == 0 {
+		return nil, ErrNotFound
+	}
+	t.Version = len(s.themes[t.ID]) + 1
+	t.CreatedAt = time.Now().UTC()
+	s.themes[t.ID] = append(s.themes[t.ID], t)
+	if err := s.flush(); err != nil {
+		s.themes[t.ID] = s.themes[t.ID][:t.Version-1]
+		if t.Version == 1 {
+			delete(s.themes, t.ID)
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (s *fileStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions := s.themes[id]
+	if len(versions) == 0 {
+		return ErrNotFound
+	}
+	delete(s.themes, id)
+	if err := s.flush(); err != nil {
+		s.themes[id] = versions
+		return err
+	}
+	return nil
+}
+
+// flush writes the themes to a temporary file next to s.path and renames
+// it over s.path, so that a failed write never truncates saved themes.
+func (s *fileStore) flush() error {
+	if s.path == "" {
+		return nil
+	}
+	b, err := json.Marshal(s.themes)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save themes: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save themes: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save themes: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save themes: %w", err)
+	}
+	return nil
+}
+
+// sortByName orders themes by name, then ID.
+func sortByName(list []Theme) {
+	sort.Slice(list, func(a, b int) bool {
+		if list[a].Name != list[b].Name {
+			return list[a].Name < list[b].Name
+		}
+		return list[a].ID < list[b].ID
+	})
+}
+
+func newID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,108 @@
+package themes
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/mjrtuhin/loomis-backend/internal/storage"
+)
+
+// stores returns a fresh store of each local kind.
+func stores(t *testing.T) map[string]Store {
+	t.Helper()
+	memory, err := NewStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := NewStore(filepath.Join(t.TempDir(), "themes.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := storage.Open(filepath.Join(t.TempDir(), "loomis.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return map[string]Store{"memory": memory, "file": file, "bolt": NewBoltStore(db)}
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	for name, s := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			brand, err := s.Save(ctx, Theme{Name: "Brand", Owner: "u1", Definition: Definition{Colors: []string{"#112233"}}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if brand.ID == "" || brand.Version != 1 || brand.CreatedAt.IsZero() {
+				t.Fatalf("Save() = %+v, want an ID, version 1 and a creation time", brand)
+			}
+			if _, err := s.Save(ctx, Theme{Name: "Accent", Owner: "u1"}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.Save(ctx, Theme{Name: "Other", Owner: "u2"}); err != nil {
+				t.Fatal(err)
+			}
+
+			brand.Definition.Colors = []string{"#445566"}
+			v2, err := s.Save(ctx, *brand)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v2.ID != brand.ID || v2.Version != 2 {
+				t.Fatalf("Save() of an existing theme = %+v, want version 2 of %s", v2, brand.ID)
+			}
+
+			list, err := s.List(ctx, "u1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(list) != 2 || list[0].Name != "Accent" || list[1].Name != "Brand" || list[1].Version != 2 {
+				t.Fatalf("List(u1) = %+v, want Accent and version 2 of Brand", list)
+			}
+
+			latest, err := s.Get(ctx, brand.ID, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if latest.Version != 2 || latest.Definition.Colors[0] != "#445566" {
+				t.Fatalf("Get(0) = %+v, want version 2", latest)
+			}
+			first, err := s.Get(ctx, brand.ID, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if first.Version != 1 || first.Definition.Colors[0] != "#112233" {
+				t.Fatalf("Get(1) = %+v, want version 1", first)
+			}
+			if _, err := s.Get(ctx, brand.ID, 3); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Get(3) error = %v, want ErrNotFound", err)
+			}
+			versions, err := s.Versions(ctx, brand.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(versions) != 2 || versions[0].Version != 1 || versions[1].Version != 2 {
+				t.Fatalf("Versions() = %+v, want versions 1 and 2", versions)
+			}
+
+			if _, err := s.Save(ctx, Theme{ID: "missing", Name: "Brand", Owner: "u1"}); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Save() of an unknown ID error = %v, want ErrNotFound", err)
+			}
+			if err := s.Delete(ctx, brand.ID); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.Get(ctx, brand.ID, 0); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Get() after Delete error = %v, want ErrNotFound", err)
+			}
+			if _, err := s.Versions(ctx, brand.ID); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Versions() after Delete error = %v, want ErrNotFound", err)
+			}
+			if err := s.Delete(ctx, brand.ID); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Delete() after Delete error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package themes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Theme is one version of a brand theme. Every save of a theme adds a
+// version; older versions stay readable so that charts pinned to one keep
+// their look.
+type Theme struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Owner is the user ID of the theme's author, the only user allowed to
+	// change or delete it. Anyone holding the ID may use it in charts.
+	Owner      string     `json:"owner"`
+	Version    int        `json:"version"`
+	Definition Definition `json:"definition"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// Definition is the styling of a brand theme. Unset fields leave the
+// chart's own styling in place.
+type Definition struct {
+	// Colors is the series color palette.
+	Colors          []string    `json:"colors"`
+	BackgroundColor string      `json:"backgroundColor"`
+	Font            *FontStyle  `json:"font"`
+	Title           *TitleStyle `json:"title"`
+	Axis            *AxisStyle  `json:"axis"`
+	Grid            *GridStyle  `json:"grid"`
+}
+
+// FontStyle is the default style of all chart text.
+type FontStyle struct {
+	Family string `json:"family"`
+	Size   int    `json:"size"`
+	Color  string `json:"color"`
+}
+
+// TitleStyle styles chart titles and subtitles.
+type TitleStyle struct {
+	Family        string `json:"family"`
+	Size          int    `json:"size"`
+	Weight        string `json:"weight"`
+	Color         string `json:"color"`
+	SubtitleColor string `json:"subtitleColor"`
+	// Align is "left", "center" or "right".
+	Align string `json:"align"`
+}
+
+// AxisStyle styles the axes of cartesian, polar and parallel charts.
+type AxisStyle struct {
+	LineColor      string `json:"lineColor"`
+	LabelColor     string `json:"labelColor"`
+	SplitLineColor string `json:"splitLineColor"`
+	// SplitLines shows or hides the grid lines across the plot.
+	SplitLines *bool `json:"splitLines"`
+}
+
+// GridStyle sets the margins of the plot area, in pixels ("40") or
+// percent of the chart ("10%").
+type GridStyle struct {
+	Left   string `json:"left"`
+	Right  string `json:"right"`
+	Top    string `json:"top"`
+	Bottom string `json:"bottom"`
+}
+
+// FieldError describes one invalid field of a theme.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when a theme is malformed.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + ": " + f.Message
+	}
+	return "invalid theme: " + strings.Join(msgs, "; ")
+}
+
+var colorPattern = regexp.MustCompile(`^(#[0-9a-fA-F]{3}|#[0-9a-fA-F]{6}|#[0-9a-fA-F]{8}|rgba?\([0-9.,\s%]+\)|hsla?\([0-9.,\s%deg]+\)|[a-zA-Z]+)$`)
+
+// ValidColor reports whether c is a CSS color ECharts understands: a hex,
+// rgb(a) or hsl(a) color, or a color name.
+func ValidColor(c string) bool {
+	return colorPattern.MatchString(strings.TrimSpace(c))
+}
+
+var marginPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?%?$`)
+
+// Validate checks t, returning a *ValidationError listing every problem.
+func (t *Theme) Validate() error {
+	var errs []FieldError
+	fail := func(field, message string) {
+		errs = append(errs, FieldError{Field: field, Message: message})
+	}
+	color := func(field, c string) {
+		if c != "" && !ValidColor(c) {
+			fail(field, fmt.Sprintf("%q is not a valid color", c))
+		}
+	}
+	size := func(field string, n int) {
+		if n < 0 || n > 200 {
+			fail(field, "must be between 0 and 200")
+		}
+	}
+
+	if strings.TrimSpace(t.Name) == "" {
+		fail("name", "is required")
+	}
+	d := t.Definition
+	for i, c := range d.Colors {
+		if !ValidColor(c) {
+			fail(fmt.Sprintf("definition.colors[%d]", i), fmt.Sprintf("%q is not a valid color", c))
+		}
+	}
+	color("definition.backgroundColor", d.BackgroundColor)
+	if f := d.Font; f != nil {
+		size("definition.font.size", f.Size)
+		color("definition.font.color", f.Color)
+	}
+	if ti := d.Title; ti != nil {
+		size("definition.title.size", ti.Size)
+		color("definition.title.color", ti.Color)
+		color("definition.title.subtitleColor", ti.SubtitleColor)
+		switch ti.Weight {
+		case "", "normal", "bold", "bolder", "lighter":
+		default:
+			fail("definition.title.weight", "must be one of normal, bold, bolder, lighter")
+		}
+		switch ti.Align {
+		case "", "left", "center", "right":
+		default:
+			fail("definition.title.align", "must be one of left, center, right")
+		}
+	}
+	if a := d.Axis; a != nil {
+		color("definition.axis.lineColor", a.LineColor)
+		color("definition.axis.labelColor", a.LabelColor)
+		color("definition.axis.splitLineColor", a.SplitLineColor)
+	}
+	if g := d.Grid; g != nil {
+		margins := []struct{ field, value string }{
+			{"left", g.Left}, {"right", g.Right}, {"top", g.Top}, {"bottom", g.Bottom},
+		}
+		for _, m := range margins {
+			if m.value != "" && !marginPattern.MatchString(m.value) {
+				fail("definition.grid."+m.field, fmt.Sprintf("%q is not a number of pixels or a percentage", m.value))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Fields: errs}
+	}
+	return nil
+}
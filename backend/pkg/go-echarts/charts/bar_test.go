@@ -1,9 +1,11 @@
 package charts
 
 import (
+	"encoding/json"
 	"io"
 	"testing"
 
+	"github.com/go-echarts/go-echarts/v2/opts"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -30,3 +32,63 @@ func TestBarDefaultValue(t *testing.T) {
 	assert.Equal(t, "Awesome go-echarts", bar.PageTitle)
 	assert.Equal(t, host, bar.AssetsHost)
 }
+
+// progressiveJSON is the part of a chart's JSON output configuring
+// progressive and large mode rendering.
+type progressiveJSON struct {
+	Progressive          *int `json:"progressive"`
+	ProgressiveThreshold *int `json:"progressiveThreshold"`
+	Series               []struct {
+		Progressive          *int  `json:"progressive"`
+		ProgressiveThreshold *int  `json:"progressiveThreshold"`
+		Large                *bool `json:"large"`
+		LargeThreshold       *int  `json:"largeThreshold"`
+	} `json:"series"`
+}
+
+func decodeProgressiveJSON(t *testing.T, obj map[string]interface{}) progressiveJSON {
+	t.Helper()
+	b, err := json.Marshal(obj)
+	assert.NoError(t, err)
+	var got progressiveJSON
+	assert.NoError(t, json.Unmarshal(b, &got))
+	return got
+}
+
+func TestBarProgressiveJSON(t *testing.T) {
+	bar := NewBar()
+	bar.SetGlobalOptions(WithAnimation(false), WithProgressive(400), WithProgressiveThreshold(3000))
+	bar.AddSeries("a", []opts.BarData{{Value: 1}}, WithSeriesProgressive(500, 2000), WithSeriesLarge(1000))
+	bar.Validate()
+
+	got := decodeProgressiveJSON(t, bar.JSON())
+	assert.Equal(t, 400, *got.Progressive)
+	assert.Equal(t, 3000, *got.ProgressiveThreshold)
+	assert.Len(t, got.Series, 1)
+	assert.Equal(t, 500, *got.Series[0].Progressive)
+	assert.Equal(t, 2000, *got.Series[0].ProgressiveThreshold)
+	assert.True(t, *got.Series[0].Large)
+	assert.Equal(t, 1000, *got.Series[0].LargeThreshold)
+}
+
+func TestBarProgressiveJSONUnset(t *testing.T) {
+	bar := NewBar()
+	bar.AddSeries("a", []opts.BarData{{Value: 1}})
+	bar.Validate()
+
+	got := decodeProgressiveJSON(t, bar.JSON())
+	assert.Nil(t, got.Progressive)
+	assert.Nil(t, got.ProgressiveThreshold)
+	assert.Nil(t, got.Series[0].Progressive)
+	assert.Nil(t, got.Series[0].Large)
+}
+
+func TestBarColorsWithTheme(t *testing.T) {
+	bar := NewBar()
+	bar.SetGlobalOptions(WithInitializationOpts(opts.Initialization{Theme: "dark"}))
+	_, ok := bar.JSON()["color"]
+	assert.False(t, ok, "the palette of the theme applies by default")
+
+	bar.SetGlobalOptions(WithColorsOpts(opts.Colors{"#123456"}))
+	assert.Equal(t, []string{"#123456"}, bar.JSON()["color"])
+}
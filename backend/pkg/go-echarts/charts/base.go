@@ -37,6 +37,7 @@ type BaseConfiguration struct {
 	opts.Assets            `json:"-"`
 	opts.RadarComponent    `json:"-"`
 	opts.GeoComponent      `json:"-"`
+	opts.GlobeComponent    `json:"-"`
 	opts.ParallelComponent `json:"-"`
 	opts.JSFunctions       `json:"-"`
 	opts.SingleAxis        `json:"-"`
@@ -55,6 +56,9 @@ type BaseConfiguration struct {
 	// If no color is set in series, the colors would be adopted sequentially and circularly
 	// from this list as the colors of series.
 	Colors []string
+	// customColors is set when Colors comes from WithColorsOpts rather than
+	// the defaults, so that it overrides the palette of the theme.
+	customColors bool
 
 	// Animation configs
 	// Animation whether enable the animation, default true
@@ -89,6 +93,7 @@ type BaseConfiguration struct {
 	has3DAxis     bool
 	hasXYAxis     bool
 	hasGeo        bool
+	hasGlobe      bool
 	hasRadar      bool
 	hasParallel   bool
 	hasSingleAxis bool
@@ -140,8 +145,7 @@ func (bc *BaseConfiguration) json() map[string]interface{} {
 	}
 
 	if bc.Progressive != nil {
-		obj["progressive"] = bc.Animation
-
+		obj["progressive"] = bc.Progressive
 	}
 
 	if bc.ProgressiveThreshold != nil {
@@ -172,6 +176,10 @@ func (bc *BaseConfiguration) json() map[string]interface{} {
 		obj["geo"] = visitor.VisitGeo(bc.GeoComponent)
 	}
 
+	if bc.hasGlobe {
+		obj["globe"] = visitor.VisitGlobe(bc.GlobeComponent)
+	}
+
 	if bc.hasRadar {
 		obj["radar"] = visitor.VisitRadar(bc.RadarComponent)
 	}
@@ -207,7 +215,7 @@ func (bc *BaseConfiguration) json() map[string]interface{} {
 		obj["grid3D"] = visitor.VisitGrid3D(bc.Grid3D)
 	}
 
-	if bc.Theme == "white" {
+	if bc.Theme == "white" || bc.customColors {
 		obj["color"] = bc.Colors
 	}
 
@@ -263,6 +271,7 @@ func (bc *BaseConfiguration) initSeriesColors() {
 
 func (bc *BaseConfiguration) setSeriesColors(colors []string) {
 	bc.Colors = colors
+	bc.customColors = true
 }
 
 func (bc *BaseConfiguration) setBaseGlobalOptions(opts ...GlobalOpts) {
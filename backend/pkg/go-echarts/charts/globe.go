@@ -0,0 +1,61 @@
+package charts
+
+import (
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/go-echarts/go-echarts/v2/render"
+	"github.com/go-echarts/go-echarts/v2/types"
+)
+
+// Globe represents an echarts-gl globe with scatter3D and lines3D series on it.
+type Globe struct {
+	BaseConfiguration
+}
+
+// Type returns the chart type.
+func (*Globe) Type() string { return types.ChartGlobe }
+
+// NewGlobe creates a new globe chart.
+func NewGlobe() *Globe {
+	c := &Globe{}
+	c.initBaseConfiguration()
+	c.Renderer = render.NewChartRender(c, c.Validate)
+	c.JSAssets.Add(opts.CompatibleEchartsJS)
+	c.JSAssets.Add("echarts-gl.min.js")
+	c.hasGlobe = true
+	return c
+}
+
+// WithGlobeOpts sets the globe component.
+func WithGlobeOpts(opt opts.GlobeComponent) GlobalOpts {
+	return func(bc *BaseConfiguration) {
+		bc.GlobeComponent = opt
+	}
+}
+
+// AddScatter3D adds a series of points, each valued as [lng, lat] or
+// [lng, lat, altitude].
+func (c *Globe) AddScatter3D(name string, data []opts.Chart3DData, options ...SeriesOpts) *Globe {
+	series := SingleSeries{Name: name, Type: types.ChartScatter3D, Data: data, CoordSystem: types.ChartGlobe}
+	series.ConfigureSeriesOpts(options...)
+	c.MultiSeries = append(c.MultiSeries, series)
+	return c
+}
+
+// AddLines3D adds a series of flow lines between coordinates.
+func (c *Globe) AddLines3D(name string, data []opts.Lines3DData, options ...SeriesOpts) *Globe {
+	series := SingleSeries{Name: name, Type: types.ChartLines3D, Data: data, CoordSystem: types.ChartGlobe}
+	series.ConfigureSeriesOpts(options...)
+	c.MultiSeries = append(c.MultiSeries, series)
+	return c
+}
+
+// SetGlobalOptions sets options for the Globe instance.
+func (c *Globe) SetGlobalOptions(options ...GlobalOpts) *Globe {
+	c.BaseConfiguration.setBaseGlobalOptions(options...)
+	return c
+}
+
+// Validate validates the given configuration.
+func (c *Globe) Validate() {
+	c.Assets.Validate(c.AssetsHost)
+}
@@ -0,0 +1,40 @@
+package charts
+
+import (
+	"io"
+	"testing"
+
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/go-echarts/go-echarts/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobeAssetsBeforeRender(t *testing.T) {
+	globe := NewGlobe()
+	assert.Equal(t, []string{"echarts.min.js", "echarts@4.min.js", "echarts-gl.min.js"}, globe.JSAssets.Values)
+}
+
+func TestGlobeAssetsAfterRender(t *testing.T) {
+	globe := NewGlobe()
+	err := globe.Render(io.Discard)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{host + "echarts.min.js", host + "echarts@4.min.js", host + "echarts-gl.min.js"}, globe.JSAssets.Values)
+}
+
+func TestGlobeJSON(t *testing.T) {
+	globe := NewGlobe()
+	globe.SetGlobalOptions(WithGlobeOpts(opts.GlobeComponent{Shading: "lambert"}))
+	globe.AddScatter3D("cities", []opts.Chart3DData{{Name: "Paris", Value: []interface{}{2.35, 48.86, 10}}})
+	globe.AddLines3D("flights", []opts.Lines3DData{{Coords: [][]float32{{2.35, 48.86}, {-74, 40.7}}}},
+		WithLines3DEffectOpts(opts.Lines3DEffect{Show: opts.Bool(true)}))
+
+	obj := globe.JSON()
+	assert.Equal(t, opts.GlobeComponent{Shading: "lambert"}, obj["globe"])
+
+	series := obj["series"].(MultiSeries)
+	assert.Equal(t, types.ChartScatter3D, series[0].Type)
+	assert.Equal(t, types.ChartGlobe, series[0].CoordSystem)
+	assert.Equal(t, types.ChartLines3D, series[1].Type)
+	assert.Equal(t, types.ChartGlobe, series[1].CoordSystem)
+	assert.NotNil(t, series[1].Lines3DEffect)
+}
@@ -4,6 +4,7 @@ import (
 	"io"
 	"testing"
 
+	"github.com/go-echarts/go-echarts/v2/opts"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,3 +19,17 @@ func TestLineAssetsAfterRender(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, []string{host + "echarts.min.js"}, line.JSAssets.Values)
 }
+
+func TestLineProgressiveJSON(t *testing.T) {
+	line := NewLine()
+	line.SetGlobalOptions(WithProgressive(400), WithProgressiveThreshold(3000))
+	line.AddSeries("a", []opts.LineData{{Value: 1}}, WithSeriesProgressive(400, 3000))
+	line.Validate()
+
+	got := decodeProgressiveJSON(t, line.JSON())
+	assert.Equal(t, 400, *got.Progressive)
+	assert.Equal(t, 3000, *got.ProgressiveThreshold)
+	assert.Equal(t, 400, *got.Series[0].Progressive)
+	assert.Equal(t, 3000, *got.Series[0].ProgressiveThreshold)
+	assert.Nil(t, got.Series[0].Large)
+}
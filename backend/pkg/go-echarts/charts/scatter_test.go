@@ -4,6 +4,7 @@ import (
 	"io"
 	"testing"
 
+	"github.com/go-echarts/go-echarts/v2/opts"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,3 +19,17 @@ func TestScatterAssetsAfterRender(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, []string{host + "echarts.min.js"}, scatter.JSAssets.Values)
 }
+
+func TestScatterProgressiveJSON(t *testing.T) {
+	scatter := NewScatter()
+	scatter.SetGlobalOptions(WithProgressive(1000))
+	scatter.AddSeries("a", []opts.ScatterData{{Value: []float64{1, 2}}}, WithSeriesProgressive(1000, 3000), WithSeriesLarge(2000))
+	scatter.Validate()
+
+	got := decodeProgressiveJSON(t, scatter.JSON())
+	assert.Equal(t, 1000, *got.Progressive)
+	assert.Nil(t, got.ProgressiveThreshold)
+	assert.Equal(t, 1000, *got.Series[0].Progressive)
+	assert.True(t, *got.Series[0].Large)
+	assert.Equal(t, 2000, *got.Series[0].LargeThreshold)
+}
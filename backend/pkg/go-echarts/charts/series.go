@@ -125,6 +125,8 @@ type SingleSeries struct {
 	// series data
 	Data         interface{} `json:"data,omitempty"`
 	DatasetIndex int         `json:"datasetIndex,omitempty"`
+	// Dimensions names the values of each data item, e.g. for encode.
+	Dimensions []string `json:"dimensions,omitempty"`
 
 	// Gauge
 	Progress   *opts.Progress  `json:"progress,omitempty"`
@@ -135,15 +137,17 @@ type SingleSeries struct {
 	SplitLine  *opts.SplitLine `json:"splitLine,omitempty"`
 	Detail     *opts.Detail    `json:"detail,omitempty"`
 	Title      *opts.Title     `json:"title,omitempty"`
-	Min        int             `json:"min,omitempty"`
-	Max        int             `json:"max,omitempty"`
+	Min        interface{}     `json:"min,omitempty"`
+	Max        interface{}     `json:"max,omitempty"`
 	StartAngle float64         `json:"startAngle,omitempty"`
 	EndAngle   float64         `json:"endAngle,omitempty"`
 
-	Large               types.Bool `json:"large,omitempty"`
-	LargeThreshold      int        `json:"largeThreshold,omitempty"`
-	HoverLayerThreshold int        `json:"hoverLayerThreshold,omitempty"`
-	UseUTC              types.Bool `json:"useUTC,omitempty"`
+	Large                types.Bool `json:"large,omitempty"`
+	LargeThreshold       int        `json:"largeThreshold,omitempty"`
+	Progressive          types.Int  `json:"progressive,omitempty"`
+	ProgressiveThreshold types.Int  `json:"progressiveThreshold,omitempty"`
+	HoverLayerThreshold  int        `json:"hoverLayerThreshold,omitempty"`
+	UseUTC               types.Bool `json:"useUTC,omitempty"`
 
 	// Animation related configs
 	Animation               types.Bool `json:"animation,omitempty"`
@@ -174,8 +178,15 @@ type SingleSeries struct {
 	*opts.CircularStyle `json:"circular,omitempty"`
 	*opts.SeriesTooltip `json:"tooltip,omitempty"`
 
+	// EndLabel is the label drawn at the end of a line series.
+	EndLabel *opts.Label `json:"endLabel,omitempty"`
+
 	// Calendar
 	CalendarIndex int `json:"calendarIndex,omitempty"`
+
+	// Lines3D
+	Polyline            types.Bool `json:"polyline,omitempty"`
+	*opts.Lines3DEffect `json:"effect,omitempty"`
 }
 
 type SeriesOpts func(s *SingleSeries)
@@ -219,6 +230,26 @@ func WithSeriesSymbolKeepAspect(enable bool) SeriesOpts {
 	}
 }
 
+// WithSeriesProgressive renders the series progressively, chunk graphic
+// elements per frame, once it holds more than threshold items.
+// Supported charts: Bar/Line/Scatter/Custom and a few more, see ECharts docs.
+func WithSeriesProgressive(chunk, threshold int) SeriesOpts {
+	return func(s *SingleSeries) {
+		s.Progressive = opts.Int(chunk)
+		s.ProgressiveThreshold = opts.Int(threshold)
+	}
+}
+
+// WithSeriesLarge enables large mode, which draws the series in a single
+// optimized pass without per-item styles, once it holds more than threshold
+// items. Supported charts: Bar/Scatter/Lines/Kline.
+func WithSeriesLarge(threshold int) SeriesOpts {
+	return func(s *SingleSeries) {
+		s.Large = opts.Bool(true)
+		s.LargeThreshold = threshold
+	}
+}
+
 func WithAnimationOpts(opt opts.Animation) SeriesOpts {
 	return func(s *SingleSeries) {
 		s.Animation = opt.Animation
@@ -239,6 +270,13 @@ func WithLabelOpts(opt opts.Label) SeriesOpts {
 	}
 }
 
+// WithEndLabelOpts sets the label at the end of a line series.
+func WithEndLabelOpts(opt opts.Label) SeriesOpts {
+	return func(s *SingleSeries) {
+		s.EndLabel = &opt
+	}
+}
+
 // WithLabelLayoutOpts sets the label.
 func WithLabelLayoutOpts(opt opts.LabelLayout) SeriesOpts {
 	return func(s *SingleSeries) {
@@ -281,6 +319,13 @@ func WithRippleEffectOpts(opt opts.RippleEffect) SeriesOpts {
 	}
 }
 
+// WithLines3DEffectOpts sets the trail effect of lines3D.
+func WithLines3DEffectOpts(opt opts.Lines3DEffect) SeriesOpts {
+	return func(s *SingleSeries) {
+		s.Lines3DEffect = &opt
+	}
+}
+
 // WithLineStyleOpts sets the line style.
 func WithLineStyleOpts(opt opts.LineStyle) SeriesOpts {
 	return func(s *SingleSeries) {
@@ -739,6 +784,13 @@ func WithEncodeOpts(opt opts.Encode) SeriesOpts {
 	}
 }
 
+// WithDimensionsOpts names the dimensions of the series data.
+func WithDimensionsOpts(dimensions ...string) SeriesOpts {
+	return func(s *SingleSeries) {
+		s.Dimensions = dimensions
+	}
+}
+
 // WithDatasetIndex sets the datasetIndex option.
 func WithDatasetIndex(index int) SeriesOpts {
 	return func(s *SingleSeries) {
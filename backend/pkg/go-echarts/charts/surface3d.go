@@ -25,6 +25,6 @@ func NewSurface3D() *Surface3D {
 
 // AddSeries adds the new series.
 func (c *Surface3D) AddSeries(name string, data []opts.Chart3DData, options ...SeriesOpts) *Surface3D {
-	c.addSeries(types.ChartScatter3D, name, data, options...)
+	c.addSeries(types.ChartSurface3D, name, data, options...)
 	return c
 }
@@ -34,6 +34,7 @@ type ConfigurationVisitor interface {
 	VisitYAxis3D(yAxis3D opts.YAxis3D) interface{}
 	VisitZAxis3D(zAxis3D opts.ZAxis3D) interface{}
 	VisitGrid3D(grid3D opts.Grid3D) interface{}
+	VisitGlobe(globe opts.GlobeComponent) interface{}
 	VisitBrush(brush opts.Brush) interface{}
 	VisitCalendar(calendar []*opts.Calendar) interface{}
 }
@@ -146,6 +147,10 @@ func (b BaseConfigurationVisitor) VisitGrid3D(grid3D opts.Grid3D) interface{} {
 	return grid3D
 }
 
+func (b BaseConfigurationVisitor) VisitGlobe(globe opts.GlobeComponent) interface{} {
+	return globe
+}
+
 func (b BaseConfigurationVisitor) VisitBrush(brush opts.Brush) interface{} {
 	return brush
 }
@@ -1,6 +1,8 @@
 package components
 
 import (
+	"html/template"
+
 	"github.com/go-echarts/go-echarts/v2/opts"
 	"github.com/go-echarts/go-echarts/v2/render"
 	"github.com/go-echarts/go-echarts/v2/util"
@@ -13,8 +15,29 @@ const (
 	PageCenterLayout Layout = "center"
 	PageFlexLayout   Layout = "flex"
 	PageFullLayout   Layout = "full"
+	// PageGridLayout places every item added with AddChartAt, AddTextAt or
+	// AddHTMLAt at its position, like on a dashboard canvas.
+	PageGridLayout Layout = "grid"
 )
 
+// GridPosition places an item of a grid layout page, in pixels from the
+// top left corner of the grid.
+type GridPosition struct {
+	Left   float64
+	Top    float64
+	Width  float64
+	Height float64
+}
+
+// GridItem is an item of a grid layout page: a chart, a block of trusted
+// HTML or a block of text.
+type GridItem struct {
+	GridPosition
+	Chart Charter
+	HTML  template.HTML
+	Text  string
+}
+
 // Charter represents a chart value which provides its type, assets and can be validated.
 type Charter interface {
 	Type() string
@@ -33,6 +56,8 @@ type Page struct {
 
 	Charts []Charter
 	Layout Layout
+	// Items are the charts and HTML blocks drawn by the grid layout.
+	Items []GridItem
 }
 
 // NewPage creates a new page.
@@ -90,6 +115,51 @@ func (page *Page) AddCharts(charts ...Charter) *Page {
 	return page
 }
 
+// AddChartAt adds a new chart to the page, placed at pos and sized to it by
+// the grid layout. Other layouts draw it as if added with AddCharts.
+func (page *Page) AddChartAt(chart Charter, pos GridPosition) *Page {
+	page.AddCharts(chart)
+	page.Items = append(page.Items, GridItem{GridPosition: pos, Chart: chart})
+	return page
+}
+
+// AddTextAt adds a block of text to the page, placed at pos. The text is
+// escaped and only drawn by the grid layout.
+func (page *Page) AddTextAt(text string, pos GridPosition) *Page {
+	page.Items = append(page.Items, GridItem{GridPosition: pos, Text: text})
+	return page
+}
+
+// AddHTMLAt adds a block of HTML to the page, placed at pos. The HTML is
+// written as is and only drawn by the grid layout, so it must be trusted:
+// sanitize any user input first, or use AddTextAt.
+func (page *Page) AddHTMLAt(html template.HTML, pos GridPosition) *Page {
+	page.Items = append(page.Items, GridItem{GridPosition: pos, HTML: html})
+	return page
+}
+
+// GridWidth returns the width of the grid, which holds every item.
+func (page *Page) GridWidth() float64 {
+	var width float64
+	for _, item := range page.Items {
+		if right := item.Left + item.Width; right > width {
+			width = right
+		}
+	}
+	return width
+}
+
+// GridHeight returns the height of the grid, which holds every item.
+func (page *Page) GridHeight() float64 {
+	var height float64
+	for _, item := range page.Items {
+		if bottom := item.Top + item.Height; bottom > height {
+			height = bottom
+		}
+	}
+	return height
+}
+
 // Validate validates the given configuration.
 func (page *Page) Validate() {
 	util.SetDefaultValue(page)
@@ -0,0 +1,61 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/stretchr/testify/assert"
+)
+
+func newGridPage() *Page {
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(charts.WithInitializationOpts(opts.Initialization{ChartID: "bar"}))
+	liquid := charts.NewLiquid()
+	liquid.SetGlobalOptions(charts.WithInitializationOpts(opts.Initialization{ChartID: "liquid"}))
+
+	page := NewPage()
+	page.SetLayout(PageGridLayout)
+	page.AddChartAt(bar, GridPosition{Left: 0, Top: 120, Width: 600, Height: 400})
+	page.AddHTMLAt("<h2>Revenue</h2>", GridPosition{Left: 0, Top: 0, Width: 300, Height: 100})
+	page.AddChartAt(liquid, GridPosition{Left: 620, Top: 120, Width: 300, Height: 300})
+	page.AddTextAt("<b>Q1</b> & Q2", GridPosition{Left: 320, Top: 0, Width: 300, Height: 100})
+	return page
+}
+
+func TestPageAddChartAt(t *testing.T) {
+	page := newGridPage()
+	assert.Len(t, page.Charts, 2)
+	assert.Len(t, page.Items, 4)
+	assert.Equal(t, []string{"echarts.min.js", "echarts@4.min.js", "echarts-liquidfill.min.js"}, page.JSAssets.Values)
+}
+
+func TestPageGridSize(t *testing.T) {
+	page := newGridPage()
+	assert.Equal(t, 920.0, page.GridWidth())
+	assert.Equal(t, 520.0, page.GridHeight())
+}
+
+func TestPageGridLayoutRender(t *testing.T) {
+	html := string(newGridPage().RenderContent())
+	assert.Contains(t, html, `<div class="grid" style="width:920px;height:520px;">`)
+	assert.Contains(t, html, `<div class="grid-item" style="left:0px;top:120px;width:600px;height:400px;"> <div class="container">`)
+	assert.Contains(t, html, `<div class="grid-item" style="left:0px;top:0px;width:300px;height:100px;"> <h2>Revenue</h2>`)
+	assert.Contains(t, html, `id="liquid"`)
+}
+
+func TestPageGridLayoutEscapesText(t *testing.T) {
+	html := string(newGridPage().RenderContent())
+	assert.Contains(t, html, `<div class="grid-item" style="left:320px;top:0px;width:300px;height:100px;"> &lt;b&gt;Q1&lt;/b&gt; &amp; Q2`)
+	assert.NotContains(t, html, "<b>Q1</b>")
+}
+
+func TestPageOtherLayoutsSkipHTML(t *testing.T) {
+	page := newGridPage()
+	page.SetLayout(PageFlexLayout)
+	html := string(page.RenderContent())
+	assert.NotContains(t, html, "Revenue")
+	assert.NotContains(t, html, "Q1")
+	assert.Contains(t, html, `id="bar"`)
+	assert.Contains(t, html, `id="liquid"`)
+}
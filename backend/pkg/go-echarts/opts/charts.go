@@ -508,7 +508,7 @@ type TreeMapNode struct {
 	Name string `json:"name"`
 
 	// Value of the tree node item.
-	Value int `json:"value,omitempty"`
+	Value float64 `json:"value,omitempty"`
 
 	Children []TreeMapNode `json:"children,omitempty"`
 }
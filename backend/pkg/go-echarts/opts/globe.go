@@ -0,0 +1,100 @@
+package opts
+
+import "github.com/go-echarts/go-echarts/v2/types"
+
+// GlobeComponent is the option set for the echarts-gl globe component.
+// https://echarts.apache.org/en/option-gl.html#globe
+type GlobeComponent struct {
+	// Whether to show the globe component.
+	Show types.Bool `json:"show,omitempty"`
+
+	// Radius of the globe. default 100
+	GlobeRadius float32 `json:"globeRadius,omitempty"`
+
+	// Outer radius of the globe, used by atmosphere and by lines3D. default 150
+	GlobeOuterRadius float32 `json:"globeOuterRadius,omitempty"`
+
+	// Texture of the globe. It can be an image url, or a color like "#000".
+	BaseTexture string `json:"baseTexture,omitempty"`
+
+	// Height texture of the globe, used for bump mapping and displacement.
+	HeightTexture string `json:"heightTexture,omitempty"`
+
+	// Environment texture, e.g. a starfield image url, or "auto"/"none".
+	EnvironmentTexture string `json:"environment,omitempty"`
+
+	// Scale of the displacement driven by HeightTexture. default 0
+	DisplacementScale float32 `json:"displacementScale,omitempty"`
+
+	// Shading of the globe.
+	// Options: "color", "lambert", "realistic"
+	Shading string `json:"shading,omitempty"`
+
+	// Light settings of the scene.
+	Light *GlobeLight `json:"light,omitempty"`
+
+	// Rotate or scale fellows the mouse
+	ViewControl *ViewControl `json:"viewControl,omitempty"`
+}
+
+// GlobeLight contains light options of the globe scene.
+type GlobeLight struct {
+	// Main light source, usually the sun.
+	Main *GlobeMainLight `json:"main,omitempty"`
+
+	// Ambient light.
+	Ambient *GlobeAmbientLight `json:"ambient,omitempty"`
+}
+
+// GlobeMainLight is the main light source of the globe scene.
+type GlobeMainLight struct {
+	// Intensity of the light. default 1
+	Intensity float32 `json:"intensity,omitempty"`
+
+	// Whether the light casts shadows.
+	Shadow types.Bool `json:"shadow,omitempty"`
+}
+
+// GlobeAmbientLight is the ambient light of the globe scene.
+type GlobeAmbientLight struct {
+	// Intensity of the light. default 0.2
+	Intensity float32 `json:"intensity,omitempty"`
+}
+
+// Lines3DData
+// https://echarts.apache.org/en/option-gl.html#series-lines3D.data
+type Lines3DData struct {
+	// Name of data item.
+	Name string `json:"name,omitempty"`
+
+	// Coords of the line, e.g. [][]float32{{lng1, lat1}, {lng2, lat2}} on a globe.
+	Coords [][]float32 `json:"coords,omitempty"`
+
+	// Value of the data item.
+	Value interface{} `json:"value,omitempty"`
+
+	// The style of this line.
+	LineStyle *LineStyle `json:"lineStyle,omitempty"`
+}
+
+// Lines3DEffect is the trail effect option set of lines3D.
+// https://echarts.apache.org/en/option-gl.html#series-lines3D.effect
+type Lines3DEffect struct {
+	// Whether to show the trail effect.
+	Show types.Bool `json:"show,omitempty"`
+
+	// Period of the trail animation, in seconds. default 4
+	Period float32 `json:"period,omitempty"`
+
+	// Width of the trail. default 4
+	TrailWidth float32 `json:"trailWidth,omitempty"`
+
+	// Length of the trail, in [0, 1]. default 0.1
+	TrailLength float32 `json:"trailLength,omitempty"`
+
+	// Color of the trail, defaults to the line color.
+	TrailColor string `json:"trailColor,omitempty"`
+
+	// Opacity of the trail.
+	TrailOpacity float32 `json:"trailOpacity,omitempty"`
+}
@@ -4,8 +4,9 @@ import "github.com/go-echarts/go-echarts/v2/types"
 
 // ParallelAxis is the option set for a parallel axis.
 type ParallelAxis struct {
-	// Dimension index of coordinate axis.
-	Dim int `json:"dim,omitempty"`
+	// Dimension index of coordinate axis. It is always written, as the
+	// first axis reads dimension 0.
+	Dim int `json:"dim"`
 
 	// Name of axis.
 	Name string `json:"name,omitempty"`
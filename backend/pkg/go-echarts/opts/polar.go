@@ -44,16 +44,18 @@ type PolarAxisBase struct {
 	// 'category' Category axis, suitable for discrete category data.
 	// 'time' Time axis, suitable for continuous time series data.
 	// 'log' Log axis, suitable for log data.
-	Type        string     `json:"type,omitempty"`
-	StartAngle  float64    `json:"startAngle,omitempty"`
-	BoundaryGap types.Bool `json:"boundaryGap,omitempty"`
-	Min         float64    `json:"min,omitempty"`
-	Max         float64    `json:"max,omitempty"`
-	Scale       types.Bool `json:"scale,omitempty"`
-	SplitNumber int        `json:"splitNumber,omitempty"`
-	MinInterval float64    `json:"minInterval,omitempty"`
-	MaxInterval float64    `json:"maxInterval,omitempty"`
-	Interval    float64    `json:"interval,omitempty"`
+	Type string `json:"type,omitempty"`
+	// Data Category data, available in type: 'category' axis.
+	Data        interface{} `json:"data,omitempty"`
+	StartAngle  float64     `json:"startAngle,omitempty"`
+	BoundaryGap types.Bool  `json:"boundaryGap,omitempty"`
+	Min         float64     `json:"min,omitempty"`
+	Max         float64     `json:"max,omitempty"`
+	Scale       types.Bool  `json:"scale,omitempty"`
+	SplitNumber int         `json:"splitNumber,omitempty"`
+	MinInterval float64     `json:"minInterval,omitempty"`
+	MaxInterval float64     `json:"maxInterval,omitempty"`
+	Interval    float64     `json:"interval,omitempty"`
 	// LogBase Base of logarithm, which is valid only for numeric axes with type: 'log'.
 	LogBase float64 `json:"logBase,omitempty"`
 	// Silent Set this to true, to prevent interaction with the axis.
@@ -331,7 +331,7 @@ type MarkAreaData struct {
 	X        interface{} `json:"x,omitempty"`
 	Y        interface{} `json:"y,omitempty"`
 	XAxis    interface{} `json:"xAxis,omitempty"`
-	YAxis    interface{} `json:"YAxis,omitempty"`
+	YAxis    interface{} `json:"yAxis,omitempty"`
 	MarkAreaStyle
 }
 
@@ -0,0 +1,14 @@
+package opts
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkAreaDataAxes(t *testing.T) {
+	b, err := json.Marshal(MarkAreaData{XAxis: "Mon", YAxis: 10})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"xAxis":"Mon","yAxis":10}`, string(b))
+}
@@ -22,6 +22,17 @@
     {{- range .Charts }} {{ template "base" . }} {{- end }}
 {{ end }}
 
+{{ if eq .Layout "grid" }}
+    <style> .grid {position: relative; margin: auto;} .grid-item {position: absolute; box-sizing: border-box; overflow: auto;} .grid-item .container, .grid-item .item {width: 100% !important; height: 100% !important;} </style>
+    <div class="grid" style="width:{{ .GridWidth }}px;height:{{ .GridHeight }}px;">
+    {{- range .Items }}
+        <div class="grid-item" style="left:{{ .Left }}px;top:{{ .Top }}px;width:{{ .Width }}px;height:{{ .Height }}px;">
+        {{- if .Chart }} {{ template "base" .Chart }} {{- else if .HTML }} {{ .HTML }} {{- else }} {{ .Text }} {{- end }}
+        </div>
+    {{- end }}
+    </div>
+{{ end }}
+
 </body>
 </html>
 {{ end }}
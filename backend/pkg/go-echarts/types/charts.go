@@ -10,11 +10,13 @@ const (
 	ChartFunnel        = "funnel"
 	ChartGauge         = "gauge"
 	ChartGeo           = "geo"
+	ChartGlobe         = "globe"
 	ChartGraph         = "graph"
 	ChartHeatMap       = "heatmap"
 	ChartKline         = "candlestick"
 	ChartLine          = "line"
 	ChartLine3D        = "line3D"
+	ChartLines3D       = "lines3D"
 	ChartLiquid        = "liquidFill"
 	ChartMap           = "map"
 	ChartParallel      = "parallel"
@@ -66,6 +66,20 @@ function sanitizeForFirestore(obj: any): any {
   return obj;
 }
 
+// Dashboards saved through the backend API keep each chartConfig as JSON
+// text, since Firestore rejects the nested arrays chart options often hold.
+function fromSnapshot(id: string, data: any): Dashboard {
+  if (!data.layout?.charts) {
+    return { id, ...data } as Dashboard;
+  }
+
+  const charts = data.layout.charts.map((chart: any) => ({
+    ...chart,
+    chartConfig: typeof chart.chartConfig === 'string' ? JSON.parse(chart.chartConfig) : chart.chartConfig
+  }));
+  return { id, ...data, layout: { ...data.layout, charts } } as Dashboard;
+}
+
 export const dashboardService = {
   async create(userId: string, googleSheetUrl: string, items: any[], refreshInterval: number = 60) {
     const dashboardId = `dashboard_${Date.now()}`;
@@ -143,10 +157,7 @@ export const dashboardService = {
       return null;
     }
 
-    return {
-      id: snapshot.id,
-      ...snapshot.data()
-    } as Dashboard;
+    return fromSnapshot(snapshot.id, snapshot.data());
   },
 
   async getUserDashboards(userId: string): Promise<Dashboard[]> {
@@ -156,10 +167,7 @@ export const dashboardService = {
     );
     
     const snapshot = await getDocs(q);
-    return snapshot.docs.map(doc => ({
-      id: doc.id,
-      ...doc.data()
-    })) as Dashboard[];
+    return snapshot.docs.map(doc => fromSnapshot(doc.id, doc.data()));
   },
 
   async delete(dashboardId: string) {